@@ -0,0 +1,53 @@
+# A module wrapping cfcert_origin_certificate, tested in tests/ with the
+# provider's mock data instead of live credentials. Run from this directory:
+#
+#   terraform init && terraform test
+
+terraform {
+  required_version = ">= 1.7"
+  required_providers {
+    cfcert = {
+      source = "envato/cfcert"
+    }
+  }
+}
+
+variable "domain_name" {
+  type = string
+}
+
+variable "team" {
+  type = string
+}
+
+variable "renew_within_days" {
+  type    = number
+  default = 30
+}
+
+resource "cfcert_origin_certificate" "this" {
+  domain_name = var.domain_name
+  alias       = var.team
+
+  tags = {
+    Team = var.team
+  }
+
+  rotation_policy = {
+    renew_before_days = var.renew_within_days
+  }
+}
+
+output "certificate_arn" {
+  value = cfcert_origin_certificate.this.certificate_arn
+}
+
+output "expires_at" {
+  value = cfcert_origin_certificate.this.expires_at
+}
+
+# Parsing expires_at is why the mock data matters: Terraform's generated
+# values for a mocked provider are random strings timeadd would reject.
+output "renew_after" {
+  value = timeadd(cfcert_origin_certificate.this.expires_at, "-${var.renew_within_days * 24}h")
+}
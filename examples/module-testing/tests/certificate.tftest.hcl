@@ -0,0 +1,55 @@
+# Runs the module against the cfcert provider's mock data, so no Cloudflare
+# or AWS credentials are needed.
+
+mock_provider "cfcert" {
+  source = "../../testing"
+}
+
+variables {
+  domain_name = "example.com"
+  team        = "payments"
+}
+
+run "tags_the_certificate_with_the_team" {
+  command = plan
+
+  assert {
+    condition     = cfcert_origin_certificate.this.tags["Team"] == "payments"
+    error_message = "The certificate is not tagged with the owning team."
+  }
+
+  assert {
+    condition     = cfcert_origin_certificate.this.alias == "payments"
+    error_message = "The certificate is not adopted under the team's alias."
+  }
+}
+
+run "renews_before_expiry" {
+  variables {
+    renew_within_days = 45
+  }
+
+  assert {
+    condition     = output.renew_after == "2040-11-17T00:00:00Z"
+    error_message = "renew_after is not 45 days before the mock expiry."
+  }
+
+  assert {
+    condition     = startswith(output.certificate_arn, "arn:aws:acm:")
+    error_message = "certificate_arn is not an ACM ARN."
+  }
+}
+
+run "expiry_from_an_override" {
+  override_resource {
+    target = cfcert_origin_certificate.this
+    values = {
+      expires_at = "2030-06-30T00:00:00Z"
+    }
+  }
+
+  assert {
+    condition     = output.renew_after == "2030-05-31T00:00:00Z"
+    error_message = "renew_after does not follow the overridden expiry."
+  }
+}
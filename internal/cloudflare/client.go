@@ -0,0 +1,350 @@
+// Package cloudflare is a client for the parts of the Cloudflare API v4 the
+// provider uses: Origin CA certificates, zones and DNS records.
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultBaseURL is the base URL of the Cloudflare API v4.
+const DefaultBaseURL = "https://api.cloudflare.com/client/v4"
+
+// Client is the Cloudflare API as the provider uses it. New returns the HTTP
+// implementation; tests can substitute a fake.
+type Client interface {
+	// Issue requests an Origin CA certificate for req's CSR and hostnames.
+	Issue(ctx context.Context, req OriginCertRequest) (OriginCert, error)
+	// Get looks up a previously issued certificate by its ID.
+	Get(ctx context.Context, id string) (OriginCert, error)
+	// Revoke revokes a certificate by its ID.
+	Revoke(ctx context.Context, id string) error
+	// List returns the certificates issued for hostnames in a zone.
+	List(ctx context.Context, zoneID string) ([]OriginCert, error)
+	// Zone looks up a zone by name, returning false if the account has no
+	// zone with that name. Only zones in Config.AccountID are found when it
+	// is set.
+	Zone(ctx context.Context, name string) (Zone, bool, error)
+	// DNSRecords returns the records in a zone named exactly name.
+	DNSRecords(ctx context.Context, zoneID, name string) ([]DNSRecord, error)
+}
+
+// Doer sends HTTP requests. It is satisfied by *http.Client.
+type Doer interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// LogFunc records one API call once it has finished, after any retries.
+type LogFunc func(ctx context.Context, operation string, start time.Time, attempts int, err error, fields map[string]interface{})
+
+// Config configures the client returned by New.
+type Config struct {
+	// BaseURL is the API's base URL, without a trailing slash. It defaults
+	// to DefaultBaseURL.
+	BaseURL string
+	// APIToken is a Cloudflare API token, used in preference to ServiceKey.
+	APIToken string
+	// RefreshAPIToken, when set, reads a new API token after the API rejects
+	// the current one with HTTP 401 or 403. The request is sent once more if
+	// the token changed, so a token rotated during a long apply is picked up.
+	RefreshAPIToken func(ctx context.Context) (string, error)
+	// ServiceKey is an Origin CA key.
+	ServiceKey string
+	// AccountID, when set, limits zone lookups to zones in that Cloudflare
+	// account, for API tokens that can access several accounts. Certificates
+	// are listed by zone, so it scopes List too.
+	AccountID string
+	// HTTPClient sends requests. It defaults to http.DefaultClient.
+	HTTPClient Doer
+	// LogCall, when set, is called after every API call.
+	LogCall LogFunc
+	// MaxAttempts bounds how many times a request that Cloudflare rate
+	// limits (429), fails with a 5xx, or that hits a transient network error
+	// is sent. It defaults to DefaultMaxAttempts.
+	MaxAttempts int
+	// MaxRetryDelay caps both the exponential backoff and any Retry-After
+	// the API asks for. It defaults to DefaultMaxRetryDelay.
+	MaxRetryDelay time.Duration
+}
+
+// New returns a Client that calls the Cloudflare API over HTTP.
+func New(cfg Config) Client {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.MaxRetryDelay <= 0 {
+		cfg.MaxRetryDelay = DefaultMaxRetryDelay
+	}
+	return &httpClient{cfg: cfg, token: cfg.APIToken}
+}
+
+// httpClient is the Client returned by New.
+type httpClient struct {
+	cfg Config
+
+	// mu guards token, the API token requests are sent with. It starts as
+	// Config.APIToken and is replaced by Config.RefreshAPIToken.
+	mu    sync.Mutex
+	token string
+}
+
+// DefaultMaxAttempts is Config.MaxAttempts when it is not set.
+const DefaultMaxAttempts = 5
+
+// maxResponseBytes caps how much of a response is read, so an unexpected
+// error page cannot exhaust memory.
+const maxResponseBytes = 1 << 20
+
+// DefaultMaxRetryDelay is Config.MaxRetryDelay when it is not set.
+const DefaultMaxRetryDelay = 60 * time.Second
+
+// response is the envelope every Cloudflare API v4 response uses.
+type response struct {
+	Success    bool            `json:"success"`
+	Result     json.RawMessage `json:"result"`
+	ResultInfo *resultInfo     `json:"result_info"`
+	Errors     []ErrorDetail   `json:"errors"`
+}
+
+// resultInfo describes the page a list response holds.
+type resultInfo struct {
+	Page       int `json:"page"`
+	TotalPages int `json:"total_pages"`
+}
+
+// call sends a request to path and decodes the response's result into result,
+// which may be nil. It returns the response's ray ID and page information.
+func (c *httpClient) call(ctx context.Context, method, path string, body, result any) (string, *resultInfo, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return "", nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+	status, header, respBody, retries, err := c.do(ctx, method, path, payload)
+	if err != nil {
+		return "", nil, retries.wrap(err)
+	}
+	info, err := parseResponse(status, header, respBody, result)
+	return header.Get("Cf-Ray"), info, retries.wrap(err)
+}
+
+// do sends a request, retrying rate limited and server error responses and
+// transient network failures with jittered exponential backoff, and returns
+// the final response along with a record of every attempt. Retrying an issuance is safe because it is for the same
+// CSR, though it can leave an unused certificate in Cloudflare if the first
+// attempt did succeed; that is preferable to failing the whole apply.
+func (c *httpClient) do(ctx context.Context, method, path string, body []byte) (int, http.Header, []byte, *RetryError, error) {
+	start := time.Now()
+	retries := &RetryError{}
+	endpoint := c.cfg.BaseURL + path
+	operation := method + " " + endpoint
+	if u, err := url.Parse(endpoint); err == nil {
+		operation = method + " " + u.Path
+	}
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		token := c.apiToken()
+		status, header, respBody, err := c.send(ctx, method, endpoint, token, body)
+		retries.Attempts = attempt
+		retries.Elapsed = time.Since(start)
+		if err != nil {
+			retries.Responses = append(retries.Responses, "network error")
+			if isTransientNetworkError(err) && attempt < c.cfg.MaxAttempts {
+				time.Sleep(retryDelay(attempt, "", c.cfg.MaxRetryDelay))
+				continue
+			}
+			c.log(ctx, operation, start, attempt, err, nil)
+			return 0, nil, nil, retries, err
+		}
+		retries.Responses = append(retries.Responses, strconv.Itoa(status))
+		if (status == http.StatusUnauthorized || status == http.StatusForbidden) && token != "" && c.cfg.RefreshAPIToken != nil && !refreshed {
+			refreshed = true
+			changed, err := c.refreshAPIToken(ctx, token)
+			if err != nil {
+				err = fmt.Errorf("the API rejected the token with HTTP %d, and reading a new one failed: %w", status, err)
+				c.log(ctx, operation, start, attempt, err, nil)
+				return 0, nil, nil, retries, err
+			}
+			if changed {
+				continue
+			}
+		}
+		if (status == http.StatusTooManyRequests || status >= 500) && attempt < c.cfg.MaxAttempts {
+			time.Sleep(retryDelay(attempt, header.Get("Retry-After"), c.cfg.MaxRetryDelay))
+			continue
+		}
+		var statusErr error
+		if status >= 400 {
+			statusErr = fmt.Errorf("HTTP %d", status)
+		}
+		c.log(ctx, operation, start, attempt, statusErr, map[string]interface{}{
+			"status": status,
+			"ray_id": header.Get("Cf-Ray"),
+		})
+		return status, header, respBody, retries, nil
+	}
+}
+
+func (c *httpClient) log(ctx context.Context, operation string, start time.Time, attempts int, err error, fields map[string]interface{}) {
+	if c.cfg.LogCall != nil {
+		c.cfg.LogCall(ctx, operation, start, attempts, err, fields)
+	}
+}
+
+// apiToken returns the API token to send, or "" to use the service key.
+func (c *httpClient) apiToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.token
+}
+
+// refreshAPIToken replaces rejected with a token read from RefreshAPIToken,
+// and reports whether the token is now different. Another request may have
+// refreshed it already, in which case it is not read again.
+func (c *httpClient) refreshAPIToken(ctx context.Context, rejected string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != rejected {
+		return true, nil
+	}
+	token, err := c.cfg.RefreshAPIToken(ctx)
+	if err != nil {
+		return false, err
+	}
+	if token == "" || token == rejected {
+		return false, nil
+	}
+	c.token = token
+	return true, nil
+}
+
+func (c *httpClient) send(ctx context.Context, method, endpoint, token string, body []byte) (int, http.Header, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	} else if c.cfg.ServiceKey != "" {
+		httpReq.Header.Set("X-Auth-User-Service-Key", c.cfg.ServiceKey)
+	} else {
+		return 0, nil, nil, fmt.Errorf("no Cloudflare API token provided")
+	}
+
+	httpResp, err := c.cfg.HTTPClient.Do(httpReq)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(httpResp.Body, maxResponseBytes))
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return httpResp.StatusCode, httpResp.Header, respBody, nil
+}
+
+// parseResponse decodes the result of an API response into result, or
+// returns the API's errors as an *APIError. Responses that are not API JSON,
+// such as HTML error pages from Cloudflare's edge, are reported with their
+// status and the start of the body. Every error names the response's ray ID.
+func parseResponse(status int, header http.Header, respBody []byte, result any) (*resultInfo, error) {
+	rayID := RayIDOrUnknown(header.Get("Cf-Ray"))
+	var cfResp response
+	if err := json.Unmarshal(respBody, &cfResp); err != nil {
+		if status < 200 || status > 299 {
+			return nil, fmt.Errorf("cloudflare API returned HTTP %d (ray ID %s): %s", status, rayID, truncateBody(respBody))
+		}
+		return nil, fmt.Errorf("failed to parse response (HTTP %d, ray ID %s): %w: %s", status, rayID, err, truncateBody(respBody))
+	}
+
+	if !cfResp.Success {
+		return nil, &APIError{StatusCode: status, RayID: header.Get("Cf-Ray"), Errors: cfResp.Errors}
+	}
+
+	if len(cfResp.Result) == 0 || result == nil {
+		return cfResp.ResultInfo, nil
+	}
+	if err := json.Unmarshal(cfResp.Result, result); err != nil {
+		return nil, fmt.Errorf("failed to parse result (HTTP %d, ray ID %s): %w: %s", status, rayID, err, truncateBody(respBody))
+	}
+	return cfResp.ResultInfo, nil
+}
+
+// isTransientNetworkError reports whether err is a failure worth retrying:
+// a dropped or refused connection, a timeout, or a temporary DNS failure.
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// truncateBody returns the start of a response body for an error message.
+func truncateBody(body []byte) string {
+	const maxLen = 512
+	text := strings.TrimSpace(string(body))
+	if text == "" {
+		return "(empty body)"
+	}
+	if len(text) > maxLen {
+		return strings.ToValidUTF8(text[:maxLen], "") + "..."
+	}
+	return text
+}
+
+// retryDelay returns how long to wait before retrying after the given
+// attempt: the server's Retry-After when it sends one, otherwise an
+// exponential backoff from one second with jitter, both capped at maxDelay.
+func retryDelay(attempt int, retryAfter string, maxDelay time.Duration) time.Duration {
+	if retryAfter != "" {
+		var delay time.Duration
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			delay = time.Duration(seconds) * time.Second
+		} else if at, err := http.ParseTime(retryAfter); err == nil {
+			delay = time.Until(at)
+		}
+		if delay > 0 {
+			return min(delay, maxDelay)
+		}
+	}
+
+	backoff := min(time.Second<<(attempt-1), maxDelay)
+	// Half fixed and half random, so concurrent applies spread out without
+	// retrying immediately.
+	return backoff/2 + mathrand.N(backoff/2+1)
+}
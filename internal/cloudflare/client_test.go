@@ -0,0 +1,228 @@
+package cloudflare_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+)
+
+// issueRequest returns a request for hostnames with a fresh key.
+func issueRequest(t *testing.T, hostnames ...string) cloudflare.OriginCertRequest {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: hostnames}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cloudflare.OriginCertRequest{
+		CSR:               string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})),
+		Hostnames:         hostnames,
+		RequestType:       cloudflare.RequestTypeECC,
+		RequestedValidity: 5475,
+	}
+}
+
+func TestIssueGetRevoke(t *testing.T) {
+	server := cloudflaretest.NewServer(t)
+	client := server.NewClient()
+	ctx := context.Background()
+
+	issued, err := client.Issue(ctx, issueRequest(t, "example.com"))
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if issued.ID == "" || issued.Certificate == "" || issued.RayID != cloudflaretest.RayID {
+		t.Errorf("Issue returned %+v", issued)
+	}
+
+	if err := client.Revoke(ctx, issued.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	cert, err := client.Get(ctx, issued.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if cert.ID != issued.ID || cert.RevokedAt == "" {
+		t.Errorf("got %+v, want revoked certificate %s", cert, issued.ID)
+	}
+
+	_, err = client.Get(ctx, "missing")
+	var apiErr *cloudflare.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Get(missing) error = %v, want a 404 APIError", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	server := cloudflaretest.NewServer(t)
+	zoneID := server.AddZone("example.com")
+	client := server.NewClient()
+	ctx := context.Background()
+
+	for _, hostname := range []string{"www.example.com", "example.org"} {
+		if _, err := client.Issue(ctx, issueRequest(t, hostname)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	certs, err := client.List(ctx, zoneID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(certs) != 1 || certs[0].Hostnames[0] != "www.example.com" {
+		t.Errorf("List = %+v, want only the www.example.com certificate", certs)
+	}
+}
+
+func TestZoneAccountScope(t *testing.T) {
+	server := cloudflaretest.NewServer(t)
+	server.AddAccountZone("account-a", "example.com")
+	wantID := server.AddAccountZone("account-b", "example.com")
+	ctx := context.Background()
+
+	client := cloudflare.New(cloudflare.Config{BaseURL: server.APIURL(), APIToken: "test-token", AccountID: "account-b", HTTPClient: server.Client()})
+	zone, found, err := client.Zone(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("Zone: %v", err)
+	}
+	if !found || zone.ID != wantID {
+		t.Errorf("Zone = %+v, %v, want %s in account-b", zone, found, wantID)
+	}
+
+	client = cloudflare.New(cloudflare.Config{BaseURL: server.APIURL(), APIToken: "test-token", AccountID: "account-c", HTTPClient: server.Client()})
+	if _, found, err := client.Zone(ctx, "example.com"); err != nil || found {
+		t.Errorf("Zone in another account = %v, %v, want not found", found, err)
+	}
+}
+
+func TestAPIErrorDetails(t *testing.T) {
+	server := cloudflaretest.NewServer(t)
+	server.FailNext(http.StatusBadRequest,
+		cloudflare.ErrorDetail{Code: 1010, Message: "Failed to validate requested hostname"},
+		cloudflare.ErrorDetail{Code: 1011, Message: "Second problem"},
+	)
+
+	_, err := server.NewClient().Issue(context.Background(), issueRequest(t, "example.com"))
+	var apiErr *cloudflare.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error %v is not an APIError", err)
+	}
+	if apiErr.RayID != cloudflaretest.RayID || len(apiErr.Errors) != 2 {
+		t.Errorf("got %+v, want ray ID %s and both errors", apiErr, cloudflaretest.RayID)
+	}
+	for _, want := range []string{"ray ID " + cloudflaretest.RayID, "(code 1010)", "Second problem (code 1011)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestRetriesServerErrors(t *testing.T) {
+	server := cloudflaretest.NewServer(t)
+	server.FailNext(http.StatusServiceUnavailable)
+
+	var attempts int
+	client := cloudflare.New(cloudflare.Config{
+		BaseURL:    server.APIURL(),
+		APIToken:   "test-token",
+		HTTPClient: server.Client(),
+		LogCall: func(_ context.Context, _ string, _ time.Time, n int, _ error, _ map[string]interface{}) {
+			attempts = n
+		},
+	})
+	if _, err := client.Issue(context.Background(), issueRequest(t, "example.com")); err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if got := len(server.RequestLog()); got != 2 || attempts != 2 {
+		t.Errorf("sent %d requests and logged %d attempts, want 2", got, attempts)
+	}
+}
+
+func TestRetrySummary(t *testing.T) {
+	server := cloudflaretest.NewServer(t)
+	server.FailNext(http.StatusTooManyRequests)
+	server.FailNext(http.StatusServiceUnavailable)
+
+	client := cloudflare.New(cloudflare.Config{
+		BaseURL:       server.APIURL(),
+		APIToken:      "test-token",
+		HTTPClient:    server.Client(),
+		MaxAttempts:   2,
+		MaxRetryDelay: time.Millisecond,
+	})
+	_, err := client.Issue(context.Background(), issueRequest(t, "example.com"))
+	var retryErr *cloudflare.RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("error %v is not a RetryError", err)
+	}
+	if retryErr.Attempts != 2 || strings.Join(retryErr.Responses, ",") != "429,503" {
+		t.Errorf("got %d attempts with responses %v, want 2 with 429 and 503", retryErr.Attempts, retryErr.Responses)
+	}
+	var apiErr *cloudflare.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("error %v does not wrap the final 503", err)
+	}
+	if !strings.Contains(err.Error(), "Responses: 429, 503") {
+		t.Errorf("error %q does not summarise the responses", err)
+	}
+}
+
+func TestRefreshAPIToken(t *testing.T) {
+	server := cloudflaretest.NewServer(t)
+	tokens := []string{"rotated-token"}
+	reads := 0
+	client := cloudflare.New(cloudflare.Config{
+		BaseURL:    server.APIURL(),
+		APIToken:   "expired-token",
+		HTTPClient: server.Client(),
+		RefreshAPIToken: func(context.Context) (string, error) {
+			reads++
+			return tokens[len(tokens)-1], nil
+		},
+	})
+	ctx := context.Background()
+
+	server.FailNext(http.StatusUnauthorized, cloudflare.ErrorDetail{Code: 10000, Message: "Authentication error"})
+	if _, err := client.Issue(ctx, issueRequest(t, "example.com")); err != nil {
+		t.Fatalf("Issue after the token was rotated: %v", err)
+	}
+	if reads != 1 {
+		t.Errorf("read the token %d times, want 1", reads)
+	}
+
+	// A token that is rejected again unchanged is not retried.
+	server.FailNext(http.StatusForbidden, cloudflare.ErrorDetail{Code: 10000, Message: "Authentication error"})
+	var apiErr *cloudflare.APIError
+	if _, err := client.Issue(ctx, issueRequest(t, "example.com")); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("Issue with an unchanged token = %v, want the 403", err)
+	}
+	if reads != 2 {
+		t.Errorf("read the token %d times, want 2", reads)
+	}
+}
+
+func TestMissingCredentials(t *testing.T) {
+	server := cloudflaretest.NewServer(t)
+	client := cloudflare.New(cloudflare.Config{BaseURL: server.APIURL(), HTTPClient: server.Client()})
+
+	_, err := client.Issue(context.Background(), issueRequest(t, "example.com"))
+	if err == nil || !strings.Contains(err.Error(), "no Cloudflare API token") {
+		t.Errorf("error = %v, want a missing token error", err)
+	}
+	if got := len(server.RequestLog()); got != 0 {
+		t.Errorf("sent %d requests without credentials", got)
+	}
+}
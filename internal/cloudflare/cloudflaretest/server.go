@@ -0,0 +1,307 @@
+// Package cloudflaretest provides a mock Cloudflare API for tests.
+package cloudflaretest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+)
+
+// RayID is the cf-ray header on every response.
+const RayID = "0123456789abcdef-SYD"
+
+// Server is an httptest server implementing the parts of the Cloudflare API
+// v4 the provider uses: Origin CA issuance, lookup, listing and revocation,
+// zone lookup and DNS record listing. Certificates are signed by a throwaway
+// CA, so they pass the provider's checks against the CSR.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	ca       *x509.Certificate
+	caKey    *ecdsa.PrivateKey
+	nextID   int
+	certs    map[string]*cloudflare.OriginCert
+	zones    []cloudflare.Zone
+	records  map[string][]cloudflare.DNSRecord
+	failures []failure
+	requests []string
+}
+
+// failure is a canned error response.
+type failure struct {
+	status int
+	errors []cloudflare.ErrorDetail
+}
+
+// NewServer starts a mock Cloudflare API, stopped when t ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		// Named like Cloudflare's ECC root, so certificates pass the
+		// provider's Origin CA issuer check.
+		Subject:               pkix.Name{Organization: []string{"CloudFlare, Inc."}, CommonName: "CloudFlare Origin ECC Certificate Authority"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(20 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{
+		ca:      ca,
+		caKey:   caKey,
+		certs:   map[string]*cloudflare.OriginCert{},
+		records: map[string][]cloudflare.DNSRecord{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// APIURL is the mock's equivalent of cloudflare.DefaultBaseURL.
+func (s *Server) APIURL() string {
+	return s.URL + "/client/v4"
+}
+
+// Root is the CA certificate the mock issues certificates from.
+func (s *Server) Root() *x509.Certificate {
+	return s.ca
+}
+
+// NewClient returns a cloudflare.Client for the mock, authenticated with a test
+// token.
+func (s *Server) NewClient() cloudflare.Client {
+	return cloudflare.New(cloudflare.Config{BaseURL: s.APIURL(), APIToken: "test-token", HTTPClient: s.Client()})
+}
+
+// AddZone adds a zone and its DNS records to the account, returning the
+// zone's ID.
+func (s *Server) AddZone(name string, records ...cloudflare.DNSRecord) string {
+	return s.AddAccountZone(DefaultAccountID, name, records...)
+}
+
+// DefaultAccountID is the account AddZone adds zones to.
+const DefaultAccountID = "account-1"
+
+// AddAccountZone adds a zone and its DNS records to accountID, returning the
+// zone's ID, to mock an API token that can access several accounts.
+func (s *Server) AddAccountZone(accountID, name string, records ...cloudflare.DNSRecord) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	zone := cloudflare.Zone{
+		ID:      fmt.Sprintf("zone-%d", len(s.zones)+1),
+		Name:    name,
+		Status:  "active",
+		Account: cloudflare.ZoneAccount{ID: accountID},
+	}
+	s.zones = append(s.zones, zone)
+	s.records[zone.ID] = records
+	return zone.ID
+}
+
+// FailNext makes the next request fail with status and errors.
+func (s *Server) FailNext(status int, errors ...cloudflare.ErrorDetail) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{status: status, errors: errors})
+}
+
+// Issued returns the certificates issued so far, oldest first.
+func (s *Server) Issued() []cloudflare.OriginCert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	certs := make([]cloudflare.OriginCert, 0, len(s.certs))
+	for _, cert := range s.certs {
+		certs = append(certs, *cert)
+	}
+	sort.Slice(certs, func(i, j int) bool { return certs[i].ID < certs[j].ID })
+	return certs
+}
+
+// RequestLog returns "METHOD path" for each request received.
+func (s *Server) RequestLog() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	w.Header().Set("Cf-Ray", RayID)
+
+	if len(s.failures) > 0 {
+		f := s.failures[0]
+		s.failures = s.failures[1:]
+		writeResponse(w, f.status, false, nil, f.errors)
+		return
+	}
+	if r.Header.Get("Authorization") == "" && r.Header.Get("X-Auth-User-Service-Key") == "" {
+		writeResponse(w, http.StatusForbidden, false, nil, []cloudflare.ErrorDetail{{Code: 10000, Message: "Authentication error"}})
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/client/v4")
+	switch {
+	case r.Method == http.MethodPost && path == "/certificates":
+		s.issue(w, r)
+	case r.Method == http.MethodGet && path == "/certificates":
+		s.list(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/certificates/"):
+		cert, ok := s.certs[strings.TrimPrefix(path, "/certificates/")]
+		if !ok {
+			writeResponse(w, http.StatusNotFound, false, nil, []cloudflare.ErrorDetail{{Code: 1001, Message: "Certificate not found"}})
+			return
+		}
+		writeResponse(w, http.StatusOK, true, cert, nil)
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "/certificates/"):
+		cert, ok := s.certs[strings.TrimPrefix(path, "/certificates/")]
+		if !ok {
+			writeResponse(w, http.StatusNotFound, false, nil, []cloudflare.ErrorDetail{{Code: 1001, Message: "Certificate not found"}})
+			return
+		}
+		cert.RevokedAt = time.Now().UTC().Format(time.RFC3339)
+		writeResponse(w, http.StatusOK, true, map[string]string{"id": cert.ID}, nil)
+	case r.Method == http.MethodGet && path == "/zones":
+		zones := []cloudflare.Zone{}
+		for _, zone := range s.zones {
+			accountID := r.URL.Query().Get("account.id")
+			if zone.Name == r.URL.Query().Get("name") && (accountID == "" || zone.Account.ID == accountID) {
+				zones = append(zones, zone)
+			}
+		}
+		writeResponse(w, http.StatusOK, true, zones, nil)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/zones/") && strings.HasSuffix(path, "/dns_records"):
+		records := []cloudflare.DNSRecord{}
+		for _, record := range s.records[strings.TrimSuffix(strings.TrimPrefix(path, "/zones/"), "/dns_records")] {
+			if record.Name == r.URL.Query().Get("name") {
+				records = append(records, record)
+			}
+		}
+		writeResponse(w, http.StatusOK, true, records, nil)
+	default:
+		writeResponse(w, http.StatusNotFound, false, nil, []cloudflare.ErrorDetail{{Code: 7003, Message: "No route for that URI"}})
+	}
+}
+
+// issue signs the request's CSR for its hostnames.
+func (s *Server) issue(w http.ResponseWriter, r *http.Request) {
+	var req cloudflare.OriginCertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, false, nil, []cloudflare.ErrorDetail{{Code: 1002, Message: "Invalid request body"}})
+		return
+	}
+	block, _ := pem.Decode([]byte(req.CSR))
+	if block == nil {
+		writeResponse(w, http.StatusBadRequest, false, nil, []cloudflare.ErrorDetail{{Code: 1005, Message: "Invalid CSR"}})
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, false, nil, []cloudflare.ErrorDetail{{Code: 1005, Message: "Invalid CSR"}})
+		return
+	}
+	if len(req.Hostnames) > cloudflare.MaxHostnames {
+		writeResponse(w, http.StatusBadRequest, false, nil, []cloudflare.ErrorDetail{{Code: 1004, Message: "Too many hostnames, the maximum is 100"}})
+		return
+	}
+
+	s.nextID++
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(1000 + s.nextID)),
+		Subject:      pkix.Name{Organization: []string{"CloudFlare, Inc."}, CommonName: "CloudFlare Origin Certificate"},
+		DNSNames:     req.Hostnames,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Duration(req.RequestedValidity) * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, s.ca, csr.PublicKey, s.caKey)
+	if err != nil {
+		writeResponse(w, http.StatusInternalServerError, false, nil, []cloudflare.ErrorDetail{{Code: 1100, Message: err.Error()}})
+		return
+	}
+	cert := &cloudflare.OriginCert{
+		ID:          fmt.Sprintf("%040d", s.nextID),
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		Hostnames:   req.Hostnames,
+		ExpiresOn:   template.NotAfter.UTC().Format(time.RFC3339),
+	}
+	s.certs[cert.ID] = cert
+	writeResponse(w, http.StatusOK, true, cert, nil)
+}
+
+// list returns every certificate with a hostname in the zone_id zone, in a
+// single page.
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	var zoneName string
+	for _, zone := range s.zones {
+		if zone.ID == r.URL.Query().Get("zone_id") {
+			zoneName = zone.Name
+		}
+	}
+	if zoneName == "" {
+		writeResponse(w, http.StatusBadRequest, false, nil, []cloudflare.ErrorDetail{{Code: 1400, Message: "Invalid zone_id"}})
+		return
+	}
+	certs := []cloudflare.OriginCert{}
+	for _, cert := range s.certs {
+		for _, hostname := range cert.Hostnames {
+			if name := strings.TrimPrefix(hostname, "*."); name == zoneName || strings.HasSuffix(name, "."+zoneName) {
+				certs = append(certs, *cert)
+				break
+			}
+		}
+	}
+	sort.Slice(certs, func(i, j int) bool { return certs[i].ID < certs[j].ID })
+	writeResponse(w, http.StatusOK, true, certs, nil)
+}
+
+func writeResponse(w http.ResponseWriter, status int, success bool, result any, errors []cloudflare.ErrorDetail) {
+	if errors == nil {
+		errors = []cloudflare.ErrorDetail{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	body := map[string]any{
+		"success":  success,
+		"result":   result,
+		"errors":   errors,
+		"messages": []any{},
+	}
+	if certs, ok := result.([]cloudflare.OriginCert); ok {
+		body["result_info"] = map[string]int{"page": 1, "per_page": len(certs), "total_pages": 1, "count": len(certs), "total_count": len(certs)}
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
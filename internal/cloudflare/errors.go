@@ -0,0 +1,75 @@
+package cloudflare
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrorDetail is one entry in a response's errors array.
+type ErrorDetail struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIError is returned when the Cloudflare API responds with an error. It
+// carries every error code the API sent and the response's ray ID, which
+// Cloudflare support asks for.
+type APIError struct {
+	StatusCode int
+	RayID      string
+	Errors     []ErrorDetail
+}
+
+func (e *APIError) Error() string {
+	details := "unknown error"
+	if len(e.Errors) > 0 {
+		messages := make([]string, len(e.Errors))
+		for i, detail := range e.Errors {
+			messages[i] = fmt.Sprintf("%s (code %d)", detail.Message, detail.Code)
+		}
+		details = strings.Join(messages, "; ")
+	}
+	return fmt.Sprintf("cloudflare API error (HTTP %d, ray ID %s): %s", e.StatusCode, RayIDOrUnknown(e.RayID), details)
+}
+
+// RetryError is returned when a request still fails after being retried. It
+// summarises every attempt, so rate limiting can be told apart from a hard
+// failure, and wraps the final attempt's error.
+type RetryError struct {
+	// Attempts is how many times the request was sent.
+	Attempts int
+	// Responses holds each attempt's HTTP status code, or "network error".
+	Responses []string
+	// Elapsed is the time from the first attempt to the last response.
+	Elapsed time.Duration
+	Err     error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%s\n\nAttempts: %d\nResponses: %s\nElapsed: %s",
+		e.Err, e.Attempts, strings.Join(e.Responses, ", "), e.Elapsed.Round(time.Millisecond))
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// wrap returns err with the retry summary, or unchanged if the request was
+// only sent once or did not fail.
+func (e *RetryError) wrap(err error) error {
+	if err == nil || e == nil || e.Attempts < 2 {
+		return err
+	}
+	summary := *e
+	summary.Err = err
+	return &summary
+}
+
+// RayIDOrUnknown formats a ray ID for an error message.
+func RayIDOrUnknown(rayID string) string {
+	if rayID == "" {
+		return "unknown"
+	}
+	return rayID
+}
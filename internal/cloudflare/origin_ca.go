@@ -0,0 +1,80 @@
+package cloudflare
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// ValidityDays are the certificate lifetimes, in days, that Origin CA
+// accepts.
+var ValidityDays = []int64{7, 30, 90, 365, 730, 1095, 5475}
+
+// MaxHostnames is the number of hostnames Origin CA accepts on a single
+// certificate.
+const MaxHostnames = 100
+
+// RequestTypeECC requests a certificate for an ECDSA key.
+const RequestTypeECC = "origin-ecc"
+
+// OriginCertRequest is the body of an Origin CA certificate request.
+type OriginCertRequest struct {
+	CSR               string   `json:"csr"`
+	Hostnames         []string `json:"hostnames"`
+	RequestType       string   `json:"request_type"`
+	RequestedValidity int      `json:"requested_validity"`
+}
+
+// OriginCert is an issued Origin CA certificate.
+type OriginCert struct {
+	ID          string   `json:"id"`
+	Certificate string   `json:"certificate"`
+	Hostnames   []string `json:"hostnames,omitempty"`
+	ExpiresOn   string   `json:"expires_on,omitempty"`
+	// RevokedAt is set once the certificate has been revoked.
+	RevokedAt string `json:"revoked_at,omitempty"`
+	// RayID is the Cloudflare request ID of the response, for support.
+	RayID string `json:"-"`
+}
+
+// listPageSize is how many certificates List asks for per page.
+const listPageSize = 50
+
+func (c *httpClient) Issue(ctx context.Context, req OriginCertRequest) (OriginCert, error) {
+	var cert OriginCert
+	rayID, _, err := c.call(ctx, "POST", "/certificates", req, &cert)
+	cert.RayID = rayID
+	return cert, err
+}
+
+func (c *httpClient) Get(ctx context.Context, id string) (OriginCert, error) {
+	var cert OriginCert
+	rayID, _, err := c.call(ctx, "GET", "/certificates/"+url.PathEscape(id), nil, &cert)
+	cert.RayID = rayID
+	return cert, err
+}
+
+func (c *httpClient) Revoke(ctx context.Context, id string) error {
+	_, _, err := c.call(ctx, "DELETE", "/certificates/"+url.PathEscape(id), nil, nil)
+	return err
+}
+
+func (c *httpClient) List(ctx context.Context, zoneID string) ([]OriginCert, error) {
+	var certs []OriginCert
+	for page := 1; ; page++ {
+		query := url.Values{
+			"zone_id":  {zoneID},
+			"page":     {strconv.Itoa(page)},
+			"per_page": {strconv.Itoa(listPageSize)},
+		}
+		var batch []OriginCert
+		_, info, err := c.call(ctx, "GET", "/certificates?"+query.Encode(), nil, &batch)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, batch...)
+		if info == nil || page >= info.TotalPages || len(batch) == 0 {
+			return certs, nil
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Zone is a zone in the authenticated Cloudflare account.
+type Zone struct {
+	ID      string      `json:"id"`
+	Name    string      `json:"name"`
+	Status  string      `json:"status"`
+	Account ZoneAccount `json:"account"`
+}
+
+// ZoneAccount is the Cloudflare account a zone belongs to.
+type ZoneAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// DNSRecord is a DNS record in a Cloudflare zone.
+type DNSRecord struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Proxied bool   `json:"proxied"`
+}
+
+func (c *httpClient) Zone(ctx context.Context, name string) (Zone, bool, error) {
+	query := url.Values{"name": {name}}
+	if c.cfg.AccountID != "" {
+		query.Set("account.id", c.cfg.AccountID)
+	}
+	var zones []Zone
+	if _, _, err := c.call(ctx, "GET", "/zones?"+query.Encode(), nil, &zones); err != nil {
+		return Zone{}, false, fmt.Errorf("failed to look up zone %s: %w", name, err)
+	}
+	for _, zone := range zones {
+		if zone.Name == name && (c.cfg.AccountID == "" || zone.Account.ID == c.cfg.AccountID) {
+			return zone, true, nil
+		}
+	}
+	return Zone{}, false, nil
+}
+
+func (c *httpClient) DNSRecords(ctx context.Context, zoneID, name string) ([]DNSRecord, error) {
+	var records []DNSRecord
+	path := "/zones/" + url.PathEscape(zoneID) + "/dns_records?name=" + url.QueryEscape(name)
+	if _, _, err := c.call(ctx, "GET", path, nil, &records); err != nil {
+		return nil, fmt.Errorf("failed to list DNS records for %s: %w", name, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testAccPreCheck skips acceptance tests unless TF_ACC is set and ACM is
+// pointed at a test endpoint, such as LocalStack with
+// AWS_ENDPOINT_URL_ACM=http://localhost:4566. Cloudflare is always mocked.
+func testAccPreCheck(t *testing.T) {
+	t.Helper()
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("set TF_ACC=1 to run acceptance tests")
+	}
+	if os.Getenv("AWS_ENDPOINT_URL_ACM") == "" {
+		t.Skip("set AWS_ENDPOINT_URL_ACM to an ACM test endpoint, such as LocalStack's http://localhost:4566")
+	}
+}
+
+// accHarness drives the provider through the Terraform plugin protocol the
+// way Terraform core does, against a mock Cloudflare API and the ACM
+// endpoint named by AWS_ENDPOINT_URL_ACM.
+type accHarness struct {
+	t          *testing.T
+	ctx        context.Context
+	server     tfprotov6.ProviderServer
+	schemas    map[string]*tfprotov6.Schema
+	cloudflare *cloudflaretest.Server
+}
+
+// accResource is a resource's state between harness calls.
+type accResource struct {
+	typeName string
+	state    tftypes.Value
+	private  []byte
+}
+
+// newAccHarness starts a mock Cloudflare API and configures the provider
+// against it.
+func newAccHarness(t *testing.T) *accHarness {
+	t.Helper()
+	testAccPreCheck(t)
+	return newProviderHarness(t, nil)
+}
+
+// newProviderHarness is newAccHarness without the acceptance test checks,
+// for resources that make no ACM calls. providerConfig is set alongside the
+// region and Cloudflare API token.
+func newProviderHarness(t *testing.T, providerConfig map[string]tftypes.Value) *accHarness {
+	t.Helper()
+	mock := cloudflaretest.NewServer(t)
+	t.Setenv("CFCERT_CLOUDFLARE_API_URL", mock.APIURL())
+	// LocalStack accepts any credentials.
+	if os.Getenv("AWS_ACCESS_KEY_ID") == "" && os.Getenv("AWS_PROFILE") == "" {
+		t.Setenv("AWS_ACCESS_KEY_ID", "test")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	}
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	h := &accHarness{
+		t:          t,
+		ctx:        context.Background(),
+		server:     providerserver.NewProtocol6(New("test")())(),
+		cloudflare: mock,
+	}
+	schemaResp, err := h.server.GetProviderSchema(h.ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.checkDiagnostics("GetProviderSchema", schemaResp.Diagnostics)
+	h.schemas = schemaResp.ResourceSchemas
+
+	values := map[string]tftypes.Value{
+		"region":               tftypes.NewValue(tftypes.String, region),
+		"cloudflare_api_token": tftypes.NewValue(tftypes.String, "test-token"),
+	}
+	for name, value := range providerConfig {
+		values[name] = value
+	}
+	config := objectValue(schemaResp.Provider.ValueType().(tftypes.Object), values)
+	configureResp, err := h.server.ConfigureProvider(h.ctx, &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.9.0",
+		Config:           h.dynamicValue(config),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.checkDiagnostics("ConfigureProvider", configureResp.Diagnostics)
+	return h
+}
+
+// objectValue returns an object of typ with the given attributes and every
+// other attribute null, as Terraform sends unset configuration.
+func objectValue(typ tftypes.Object, values map[string]tftypes.Value) tftypes.Value {
+	attributes := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for name, attrType := range typ.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		} else {
+			attributes[name] = tftypes.NewValue(attrType, nil)
+		}
+	}
+	return tftypes.NewValue(typ, attributes)
+}
+
+// config returns resource configuration for typeName with the given
+// attributes set.
+func (h *accHarness) config(typeName string, values map[string]tftypes.Value) tftypes.Value {
+	return objectValue(h.resourceType(typeName), values)
+}
+
+func (h *accHarness) resourceType(typeName string) tftypes.Object {
+	schema, ok := h.schemas[typeName]
+	if !ok {
+		h.t.Fatalf("provider has no resource %s", typeName)
+	}
+	return schema.ValueType().(tftypes.Object)
+}
+
+func (h *accHarness) dynamicValue(value tftypes.Value) *tfprotov6.DynamicValue {
+	dv, err := tfprotov6.NewDynamicValue(value.Type(), value)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return &dv
+}
+
+func (h *accHarness) value(typeName string, dv *tfprotov6.DynamicValue) tftypes.Value {
+	if dv == nil {
+		return tftypes.NewValue(h.resourceType(typeName), nil)
+	}
+	value, err := dv.Unmarshal(h.resourceType(typeName))
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return value
+}
+
+// checkDiagnostics fails the test on error diagnostics and logs warnings.
+func (h *accHarness) checkDiagnostics(operation string, diags []*tfprotov6.Diagnostic) {
+	h.t.Helper()
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			h.t.Fatalf("%s: %s: %s", operation, d.Summary, d.Detail)
+		}
+		h.t.Logf("%s warning: %s: %s", operation, d.Summary, d.Detail)
+	}
+}
+
+// proposedNewState merges config into prior the way Terraform proposes a new
+// state: configured values win, and computed attributes left unset keep their
+// prior value.
+func (h *accHarness) proposedNewState(typeName string, prior, config tftypes.Value) tftypes.Value {
+	if prior.IsNull() {
+		return config
+	}
+	var priorAttrs, configAttrs map[string]tftypes.Value
+	if err := prior.As(&priorAttrs); err != nil {
+		h.t.Fatal(err)
+	}
+	if err := config.As(&configAttrs); err != nil {
+		h.t.Fatal(err)
+	}
+	proposed := map[string]tftypes.Value{}
+	for _, attr := range h.schemas[typeName].Block.Attributes {
+		proposed[attr.Name] = configAttrs[attr.Name]
+		if attr.Computed && configAttrs[attr.Name].IsNull() {
+			proposed[attr.Name] = priorAttrs[attr.Name]
+		}
+	}
+	return tftypes.NewValue(h.resourceType(typeName), proposed)
+}
+
+// plan plans the change from res to config, which is null to destroy.
+func (h *accHarness) plan(res *accResource, config tftypes.Value) *tfprotov6.PlanResourceChangeResponse {
+	h.t.Helper()
+	proposed := config
+	if !config.IsNull() {
+		proposed = h.proposedNewState(res.typeName, res.state, config)
+	}
+	resp, err := h.server.PlanResourceChange(h.ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         res.typeName,
+		PriorState:       h.dynamicValue(res.state),
+		ProposedNewState: h.dynamicValue(proposed),
+		Config:           h.dynamicValue(config),
+		PriorPrivate:     res.private,
+	})
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	h.checkDiagnostics("PlanResourceChange", resp.Diagnostics)
+	return resp
+}
+
+// apply plans and applies config to res, as terraform apply does.
+func (h *accHarness) apply(res *accResource, config tftypes.Value) *tfprotov6.PlanResourceChangeResponse {
+	h.t.Helper()
+	plan := h.plan(res, config)
+	resp, err := h.server.ApplyResourceChange(h.ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       res.typeName,
+		PriorState:     h.dynamicValue(res.state),
+		PlannedState:   plan.PlannedState,
+		Config:         h.dynamicValue(config),
+		PlannedPrivate: plan.PlannedPrivate,
+	})
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	h.checkDiagnostics("ApplyResourceChange", resp.Diagnostics)
+	res.state = h.value(res.typeName, resp.NewState)
+	res.private = resp.Private
+	return plan
+}
+
+// create applies config to a new resource of typeName.
+func (h *accHarness) create(typeName string, config tftypes.Value) *accResource {
+	h.t.Helper()
+	res := &accResource{typeName: typeName, state: tftypes.NewValue(h.resourceType(typeName), nil)}
+	h.apply(res, config)
+	return res
+}
+
+// replace replaces res with a new instance for config, creating it before
+// destroying res as create_before_destroy does. Terraform plans the new
+// instance with the private state from the plan that required replacement.
+func (h *accHarness) replace(res *accResource, config tftypes.Value) *accResource {
+	h.t.Helper()
+	plan := h.plan(res, config)
+	replacement := &accResource{
+		typeName: res.typeName,
+		state:    tftypes.NewValue(h.resourceType(res.typeName), nil),
+		private:  plan.PlannedPrivate,
+	}
+	h.apply(replacement, config)
+	h.destroy(res)
+	return replacement
+}
+
+// destroy destroys res.
+func (h *accHarness) destroy(res *accResource) {
+	h.t.Helper()
+	h.apply(res, tftypes.NewValue(h.resourceType(res.typeName), nil))
+}
+
+// refresh reads res, as terraform refresh does. Its state is null if the
+// provider found it gone.
+func (h *accHarness) refresh(res *accResource) {
+	h.t.Helper()
+	resp, err := h.server.ReadResource(h.ctx, &tfprotov6.ReadResourceRequest{
+		TypeName:     res.typeName,
+		CurrentState: h.dynamicValue(res.state),
+		Private:      res.private,
+	})
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	h.checkDiagnostics("ReadResource", resp.Diagnostics)
+	res.state = h.value(res.typeName, resp.NewState)
+	res.private = resp.Private
+}
+
+// attribute returns the named attribute of res's state.
+func (r *accResource) attribute(t *testing.T, name string) tftypes.Value {
+	t.Helper()
+	var attrs map[string]tftypes.Value
+	if err := r.state.As(&attrs); err != nil {
+		t.Fatal(err)
+	}
+	value, ok := attrs[name]
+	if !ok {
+		t.Fatalf("%s has no attribute %s", r.typeName, name)
+	}
+	return value
+}
+
+// stringAttribute returns the named string attribute of res's state, or ""
+// when it is null.
+func (r *accResource) stringAttribute(t *testing.T, name string) string {
+	t.Helper()
+	var s *string
+	if err := r.attribute(t, name).As(&s); err != nil {
+		t.Fatal(err)
+	}
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// stringListAttribute returns the named list of strings attribute of res's
+// state, which is empty when it is null.
+func (r *accResource) stringListAttribute(t *testing.T, name string) []string {
+	t.Helper()
+	var elements []tftypes.Value
+	if err := r.attribute(t, name).As(&elements); err != nil {
+		t.Fatal(err)
+	}
+	values := make([]string, 0, len(elements))
+	for _, element := range elements {
+		var s string
+		if err := element.As(&s); err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, s)
+	}
+	return values
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/smithy-go"
+)
+
+// fakeACM is an in-memory ACMAPI. Operations it does not implement panic
+// through the nil embedded interface.
+type fakeACM struct {
+	ACMAPI
+
+	summaries []types.CertificateSummary
+	pageSize  int
+	listCalls int
+	tags      map[string]map[string]string
+}
+
+func (f *fakeACM) ListCertificates(_ context.Context, params *acm.ListCertificatesInput, _ ...func(*acm.Options)) (*acm.ListCertificatesOutput, error) {
+	f.listCalls++
+	start := 0
+	if params.NextToken != nil {
+		for i, summary := range f.summaries {
+			if aws.ToString(summary.CertificateArn) == *params.NextToken {
+				start = i
+			}
+		}
+	}
+	pageSize := f.pageSize
+	if params.MaxItems != nil {
+		pageSize = int(*params.MaxItems)
+	}
+	end := min(start+pageSize, len(f.summaries))
+	out := &acm.ListCertificatesOutput{CertificateSummaryList: f.summaries[start:end]}
+	if end < len(f.summaries) {
+		out.NextToken = f.summaries[end].CertificateArn
+	}
+	return out, nil
+}
+
+func (f *fakeACM) AddTagsToCertificate(_ context.Context, params *acm.AddTagsToCertificateInput, _ ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error) {
+	tags := f.tags[*params.CertificateArn]
+	if tags == nil {
+		tags = map[string]string{}
+		f.tags[*params.CertificateArn] = tags
+	}
+	for _, tag := range params.Tags {
+		tags[*tag.Key] = aws.ToString(tag.Value)
+	}
+	return &acm.AddTagsToCertificateOutput{}, nil
+}
+
+func (f *fakeACM) ListTagsForCertificate(_ context.Context, params *acm.ListTagsForCertificateInput, _ ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error) {
+	out := &acm.ListTagsForCertificateOutput{}
+	for key, value := range f.tags[*params.CertificateArn] {
+		out.Tags = append(out.Tags, types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return out, nil
+}
+
+func (f *fakeACM) RemoveTagsFromCertificate(_ context.Context, params *acm.RemoveTagsFromCertificateInput, _ ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error) {
+	for _, tag := range params.Tags {
+		delete(f.tags[*params.CertificateArn], *tag.Key)
+	}
+	return &acm.RemoveTagsFromCertificateOutput{}, nil
+}
+
+func TestCertificateListCacheWithFakeACM(t *testing.T) {
+	fake := &fakeACM{pageSize: 2}
+	for _, arn := range []string{"arn:1", "arn:2", "arn:3"} {
+		fake.summaries = append(fake.summaries, types.CertificateSummary{CertificateArn: aws.String(arn)})
+	}
+	cache := newCertificateListCache()
+	ctx := context.Background()
+
+	summaries, err := cache.issuedP256(ctx, fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 3 || fake.listCalls != 2 {
+		t.Fatalf("got %d certificates in %d calls, want 3 in 2 pages", len(summaries), fake.listCalls)
+	}
+	if _, err := cache.issuedP256(ctx, fake); err != nil || fake.listCalls != 2 {
+		t.Errorf("second scan made %d calls (err %v), want the cached result", fake.listCalls, err)
+	}
+	cache.invalidate()
+	if _, err := cache.issuedP256(ctx, fake); err != nil || fake.listCalls != 4 {
+		t.Errorf("scan after invalidate made %d calls (err %v), want a fresh listing", fake.listCalls, err)
+	}
+}
+
+// sortDeniedACM denies sorted ListCertificates calls, as an IAM condition on
+// the request's parameters would.
+type sortDeniedACM struct {
+	*fakeACM
+}
+
+func (f sortDeniedACM) ListCertificates(ctx context.Context, params *acm.ListCertificatesInput, optFns ...func(*acm.Options)) (*acm.ListCertificatesOutput, error) {
+	if params.SortBy != "" {
+		f.listCalls++
+		return nil, &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized"}
+	}
+	return f.fakeACM.ListCertificates(ctx, params, optFns...)
+}
+
+func TestCertificateListEmptyAccount(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeACM{pageSize: 100}
+	summaries, err := newCertificateListCache().issuedP256(ctx, sortDeniedACM{fake})
+	if err != nil || len(summaries) != 0 {
+		t.Fatalf("got %d certificates, err %v; want an empty account", len(summaries), err)
+	}
+	if fake.listCalls != 2 {
+		t.Errorf("made %d list calls, want the denied listing and one check", fake.listCalls)
+	}
+
+	fake.summaries = []types.CertificateSummary{{CertificateArn: aws.String("arn:1")}}
+	_, err = newCertificateListCache().issuedP256(ctx, sortDeniedACM{fake})
+	if err == nil || !strings.Contains(err.Error(), "cannot call acm:ListCertificates") {
+		t.Errorf("err = %v, want access denied explained for an account with certificates", err)
+	}
+}
+
+func TestACMTagsWithFakeACM(t *testing.T) {
+	fake := &fakeACM{tags: map[string]map[string]string{}}
+	ctx := context.Background()
+
+	if err := addACMTags(ctx, fake, "arn:1", map[string]string{"team": "web", "env": "prod"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeACMTags(ctx, fake, "arn:1", map[string]string{"env": "changed outside Terraform"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"team": "web"}; !maps.Equal(fake.tags["arn:1"], want) {
+		t.Errorf("tags = %v, want %v", fake.tags["arn:1"], want)
+	}
+}
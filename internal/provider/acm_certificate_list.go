@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/smithy-go"
+)
+
+// maxListCertificatesPageSize is the most certificates ListCertificates
+// returns per call.
+const maxListCertificatesPageSize = 1000
+
+// certificateListTTL is how long one ListCertificates scan is reused.
+const certificateListTTL = time.Minute
+
+// certificateListCache shares one paginated scan of the account's issued
+// P-256 certificates between every resource and data source in an
+// operation, instead of each adoption lookup listing the whole account. It
+// is invalidated whenever the provider imports or deletes a certificate.
+type certificateListCache struct {
+	mu        sync.Mutex
+	summaries []types.CertificateSummary
+	fetchedAt time.Time
+	// populated is set once the account is known to hold certificates, after
+	// which listings no longer check whether it is empty.
+	populated atomic.Bool
+}
+
+func newCertificateListCache() *certificateListCache {
+	return &certificateListCache{}
+}
+
+// issuedP256 returns the issued EC_prime256v1 certificates in the account,
+// newest first. Concurrent callers wait for a single scan.
+func (c *certificateListCache) issuedP256(ctx context.Context, client ACMAPI) ([]types.CertificateSummary, error) {
+	return c.issuedP256WithPageSize(ctx, client, 0)
+}
+
+// issuedP256WithPageSize is issuedP256, listing pageSize certificates per
+// call if it has to scan. Zero leaves the page size to ACM.
+func (c *certificateListCache) issuedP256WithPageSize(ctx context.Context, client ACMAPI, pageSize int32) ([]types.CertificateSummary, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.summaries != nil && time.Since(c.fetchedAt) < certificateListTTL {
+		return c.summaries, nil
+	}
+
+	paginator := newIssuedP256Paginator(client, pageSize)
+	summaries := []types.CertificateSummary{}
+	for first := true; paginator.HasMorePages(); first = false {
+		page, empty, err := c.nextPage(ctx, client, paginator, first)
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			break
+		}
+		summaries = append(summaries, page.CertificateSummaryList...)
+	}
+	if len(summaries) > 0 {
+		c.populated.Store(true)
+	}
+	c.summaries = summaries
+	c.fetchedAt = time.Now()
+	return summaries, nil
+}
+
+// nextPage returns paginator's next page. When the first page fails with
+// access denied, or comes back empty with more pages to follow as ACM's
+// filtered listing can, it checks whether the account holds any certificates
+// at all and reports an empty account instead. A first run in a new account
+// then neither pages through empty results nor fails on the listing's
+// filters.
+func (c *certificateListCache) nextPage(ctx context.Context, client ACMAPI, paginator *acm.ListCertificatesPaginator, first bool) (page *acm.ListCertificatesOutput, empty bool, err error) {
+	page, err = paginator.NextPage(ctx)
+	suspect := isAccessDeniedError(err) || (err == nil && len(page.CertificateSummaryList) == 0 && page.NextToken != nil)
+	if first && suspect && !c.populated.Load() {
+		empty, probeErr := c.accountEmpty(ctx, client)
+		if probeErr == nil && empty {
+			return nil, true, nil
+		}
+	}
+	if err != nil {
+		return nil, false, listCertificatesError(err)
+	}
+	return page, false, nil
+}
+
+// accountEmpty reports whether the account and region hold no certificates,
+// asking ACM for one certificate of any key type or status, unsorted.
+func (c *certificateListCache) accountEmpty(ctx context.Context, client ACMAPI) (bool, error) {
+	if c.populated.Load() {
+		return false, nil
+	}
+	output, err := client.ListCertificates(ctx, &acm.ListCertificatesInput{
+		Includes: &types.Filters{KeyTypes: types.KeyAlgorithm("").Values()},
+		MaxItems: aws.Int32(1),
+	})
+	if err != nil {
+		return false, listCertificatesError(err)
+	}
+	if len(output.CertificateSummaryList) > 0 || output.NextToken != nil {
+		c.populated.Store(true)
+		return false, nil
+	}
+	return true, nil
+}
+
+func isAccessDeniedError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDeniedException"
+}
+
+// listCertificatesError explains a failed ListCertificates call. Access
+// denied is the usual first-run failure in a new account, before its IAM
+// policies cover ACM.
+func listCertificatesError(err error) error {
+	if isAccessDeniedError(err) {
+		return fmt.Errorf("the provider's AWS credentials cannot call acm:ListCertificates, which finding existing "+
+			"certificates needs; grant it without conditions on the key type or status filters: %w", err)
+	}
+	return fmt.Errorf("failed to list ACM certificates: %w", err)
+}
+
+// cached returns the last scan if it is still fresh, or nil.
+func (c *certificateListCache) cached() []types.CertificateSummary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.fetchedAt) >= certificateListTTL {
+		return nil
+	}
+	return c.summaries
+}
+
+// newIssuedP256Paginator pages through the account's issued EC_prime256v1
+// certificates, newest first.
+func newIssuedP256Paginator(client ACMAPI, pageSize int32) *acm.ListCertificatesPaginator {
+	input := &acm.ListCertificatesInput{
+		CertificateStatuses: []types.CertificateStatus{types.CertificateStatusIssued},
+		Includes: &types.Filters{
+			KeyTypes: []types.KeyAlgorithm{types.KeyAlgorithmEcPrime256v1},
+		},
+		SortBy:    types.SortByCreatedAt,
+		SortOrder: types.SortOrderDescending,
+	}
+	if pageSize > 0 {
+		input.MaxItems = aws.Int32(pageSize)
+	}
+	return acm.NewListCertificatesPaginator(client, input)
+}
+
+// invalidate drops the cached scan after the account's certificates change.
+func (c *certificateListCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.summaries = nil
+}
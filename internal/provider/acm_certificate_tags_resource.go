@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &ACMCertificateTagsResource{}
+var _ resource.ResourceWithConfigure = &ACMCertificateTagsResource{}
+
+// ACMCertificateTagsResource manages a set of tags on an ACM certificate it
+// does not own. Tags not listed in its configuration are left alone.
+type ACMCertificateTagsResource struct {
+	clients *ProviderClients
+}
+
+type ACMCertificateTagsResourceModel struct {
+	CertificateArn tfTypes.String `tfsdk:"certificate_arn"`
+	Tags           tfTypes.Map    `tfsdk:"tags"`
+	ID             tfTypes.String `tfsdk:"id"`
+}
+
+func NewACMCertificateTagsResource() resource.Resource {
+	return &ACMCertificateTagsResource{}
+}
+
+func (r *ACMCertificateTagsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_acm_certificate_tags"
+}
+
+func (r *ACMCertificateTagsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages tags on an existing ACM certificate, such as one created in another workspace. " +
+			"Only the tags listed here are managed; other tags on the certificate are left unchanged.",
+		Attributes: map[string]schema.Attribute{
+			"certificate_arn": schema.StringAttribute{
+				Description: "The ARN of the ACM certificate to tag.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"tags": schema.MapAttribute{
+				Description: "Tags to set on the certificate.",
+				ElementType: tfTypes.StringType,
+				Required:    true,
+			},
+			"id": schema.StringAttribute{
+				Description: "Resource identifier (same as certificate_arn).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ACMCertificateTagsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*ProviderClients)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderClients, got: %T", req.ProviderData),
+		)
+		return
+	}
+	r.clients = clients
+}
+
+func (r *ACMCertificateTagsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data ACMCertificateTagsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tags := map[string]string{}
+	resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &tags, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := addACMTags(ctx, r.clients.ACMClient, data.CertificateArn.ValueString(), tags); err != nil {
+		resp.Diagnostics.AddError("Failed to tag certificate", err.Error())
+		return
+	}
+
+	data.ID = data.CertificateArn
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ACMCertificateTagsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data ACMCertificateTagsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed := map[string]string{}
+	resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &managed, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	output, err := r.clients.ACMClient.ListTagsForCertificate(ctx, &acm.ListTagsForCertificateInput{
+		CertificateArn: aws.String(data.CertificateArn.ValueString()),
+	})
+	if err != nil && isResourceNotFoundError(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list certificate tags", err.Error())
+		return
+	}
+
+	// Only report tags this resource manages, so tags owned elsewhere never
+	// show up as drift.
+	current := map[string]string{}
+	for _, tag := range output.Tags {
+		key := aws.ToString(tag.Key)
+		if _, ok := managed[key]; ok {
+			current[key] = aws.ToString(tag.Value)
+		}
+	}
+
+	tags, diags := tfTypes.MapValueFrom(ctx, tfTypes.StringType, current)
+	resp.Diagnostics.Append(diags...)
+	data.Tags = tags
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ACMCertificateTagsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state ACMCertificateTagsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned := map[string]string{}
+	previous := map[string]string{}
+	resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &planned, false)...)
+	resp.Diagnostics.Append(state.Tags.ElementsAs(ctx, &previous, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	removed := map[string]string{}
+	for key, value := range previous {
+		if _, ok := planned[key]; !ok {
+			removed[key] = value
+		}
+	}
+
+	arn := data.CertificateArn.ValueString()
+	if err := removeACMTags(ctx, r.clients.ACMClient, arn, removed); err != nil {
+		resp.Diagnostics.AddError("Failed to remove certificate tags", err.Error())
+		return
+	}
+	if err := addACMTags(ctx, r.clients.ACMClient, arn, planned); err != nil {
+		resp.Diagnostics.AddError("Failed to tag certificate", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ACMCertificateTagsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data ACMCertificateTagsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tags := map[string]string{}
+	resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &tags, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := removeACMTags(ctx, r.clients.ACMClient, data.CertificateArn.ValueString(), tags)
+	if err != nil && !isResourceNotFoundError(err) {
+		resp.Diagnostics.AddError("Failed to remove certificate tags", err.Error())
+	}
+}
+
+// addACMTags sets tags on the certificate, overwriting existing values for the
+// same keys.
+func addACMTags(ctx context.Context, client ACMAPI, arn string, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	_, err := client.AddTagsToCertificate(ctx, &acm.AddTagsToCertificateInput{
+		CertificateArn: aws.String(arn),
+		Tags:           acmTags(tags),
+	})
+	return err
+}
+
+func removeACMTags(ctx context.Context, client ACMAPI, arn string, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	// Removing by key alone, so tags changed outside Terraform are still
+	// removed.
+	keys := make([]types.Tag, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, types.Tag{Key: aws.String(key)})
+	}
+	_, err := client.RemoveTagsFromCertificate(ctx, &acm.RemoveTagsFromCertificateInput{
+		CertificateArn: aws.String(arn),
+		Tags:           keys,
+	})
+	return err
+}
+
+func acmTags(tags map[string]string) []types.Tag {
+	result := make([]types.Tag, 0, len(tags))
+	for key, value := range tags {
+		result = append(result, types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	// defaultImportedCertificateQuota is ACM's default limit on imported
+	// certificates per account and region, used when Service Quotas cannot
+	// be read.
+	defaultImportedCertificateQuota = 2500
+	// importHeadroomWarningFraction is the share of the quota left below
+	// which creating a certificate warns.
+	importHeadroomWarningFraction = 0.1
+)
+
+// serviceQuotasClient reads quotas from the Service Quotas JSON API.
+type serviceQuotasClient struct {
+	api *awsJSONClient
+}
+
+// importedCertificateQuota returns the account's ACM "Imported certificates"
+// quota, or the AWS default when it cannot be read.
+func (c *serviceQuotasClient) importedCertificateQuota(ctx context.Context) int {
+	input := map[string]interface{}{"ServiceCode": "acm"}
+	for {
+		var out struct {
+			Quotas []struct {
+				QuotaName string  `json:"QuotaName"`
+				Value     float64 `json:"Value"`
+			} `json:"Quotas"`
+			NextToken string `json:"NextToken"`
+		}
+		if err := c.api.call(ctx, "ListServiceQuotas", input, &out); err != nil {
+			return defaultImportedCertificateQuota
+		}
+		for _, quota := range out.Quotas {
+			if strings.EqualFold(quota.QuotaName, "Imported certificates") {
+				return int(quota.Value)
+			}
+		}
+		if out.NextToken == "" {
+			return defaultImportedCertificateQuota
+		}
+		input["NextToken"] = out.NextToken
+	}
+}
+
+// acmImportUsage counts imported certificates against the quota.
+type acmImportUsage struct {
+	imported int
+	unused   int
+	quota    int
+}
+
+func (c *ProviderClients) acmImportUsage(ctx context.Context) (acmImportUsage, error) {
+	usage := acmImportUsage{quota: c.ServiceQuotasClient.importedCertificateQuota(ctx)}
+	paginator := acm.NewListCertificatesPaginator(c.ACMClient, &acm.ListCertificatesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return usage, err
+		}
+		for _, cert := range page.CertificateSummaryList {
+			if cert.Type != types.CertificateTypeImported {
+				continue
+			}
+			usage.imported++
+			if !aws.ToBool(cert.InUse) {
+				usage.unused++
+			}
+		}
+	}
+	return usage, nil
+}
+
+func (u acmImportUsage) String() string {
+	return fmt.Sprintf("%d of %d imported certificates are in use in this account and region, %d of them not attached "+
+		"to any AWS service. Reuse an existing certificate (leave hostnames and the key store attributes unset so one can be adopted), "+
+		"delete unattached imported certificates, or request a quota increase in Service Quotas.", u.imported, u.quota, u.unused)
+}
+
+// warnImportHeadroom warns when the account is close to its imported
+// certificate quota, while there is still time to act.
+func (c *ProviderClients) warnImportHeadroom(ctx context.Context, diags *diag.Diagnostics) {
+	usage, err := c.acmImportUsage(ctx)
+	if err != nil || float64(usage.quota-usage.imported) >= float64(usage.quota)*importHeadroomWarningFraction {
+		return
+	}
+	diags.AddWarning(
+		"ACM Imported Certificate Quota Nearly Reached",
+		fmt.Sprintf("%d imports left. %s", max(usage.quota-usage.imported, 0), usage),
+	)
+}
+
+// importQuotaAdvice explains an import refused with LimitExceededException,
+// telling the imported certificate quota apart from the yearly import limit.
+func (c *ProviderClients) importQuotaAdvice(ctx context.Context) string {
+	usage, err := c.acmImportUsage(ctx)
+	if err != nil {
+		return ""
+	}
+	if usage.imported >= usage.quota {
+		return "The imported certificate quota is full. " + usage.String()
+	}
+	return "The imported certificate quota is not full, so the yearly import limit (by default twice the imported " +
+		"certificate quota per rolling 365 days) was reached; re-imports into existing ARNs count towards it too. " + usage.String()
+}
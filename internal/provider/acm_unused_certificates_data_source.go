@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &ACMUnusedCertificatesDataSource{}
+var _ datasource.DataSourceWithConfigure = &ACMUnusedCertificatesDataSource{}
+
+// defaultUnusedDays is older_than_days when it is not set.
+const defaultUnusedDays = 30
+
+type ACMUnusedCertificatesDataSource struct {
+	clients *ProviderClients
+}
+
+type ACMUnusedCertificatesDataSourceModel struct {
+	OlderThanDays   tfTypes.Int64  `tfsdk:"older_than_days"`
+	Certificates    tfTypes.List   `tfsdk:"certificates"`
+	CertificateArns tfTypes.List   `tfsdk:"certificate_arns"`
+	ID              tfTypes.String `tfsdk:"id"`
+}
+
+// ACMUnusedCertificateModel is one element of certificates.
+type ACMUnusedCertificateModel struct {
+	CertificateArn tfTypes.String `tfsdk:"certificate_arn"`
+	DomainName     tfTypes.String `tfsdk:"domain_name"`
+	SerialNumber   tfTypes.String `tfsdk:"serial_number"`
+	ImportedAt     tfTypes.String `tfsdk:"imported_at"`
+	ExpiresAt      tfTypes.String `tfsdk:"expires_at"`
+	Managed        tfTypes.Bool   `tfsdk:"managed"`
+}
+
+func acmUnusedCertificateAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"certificate_arn": tfTypes.StringType,
+		"domain_name":     tfTypes.StringType,
+		"serial_number":   tfTypes.StringType,
+		"imported_at":     tfTypes.StringType,
+		"expires_at":      tfTypes.StringType,
+		"managed":         tfTypes.BoolType,
+	}
+}
+
+func NewACMUnusedCertificatesDataSource() datasource.DataSource {
+	return &ACMUnusedCertificatesDataSource{}
+}
+
+func (d *ACMUnusedCertificatesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_acm_unused_certificates"
+}
+
+func (d *ACMUnusedCertificatesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the Cloudflare Origin CA certificates imported into ACM in the provider's account and region that " +
+			"no AWS resource uses and that were imported more than older_than_days ago, for cleanup workflows. Each " +
+			"candidate is fetched to check its issuer and tags, so the read makes two ACM calls per unused imported certificate.",
+		Attributes: map[string]schema.Attribute{
+			"older_than_days": schema.Int64Attribute{
+				Description: fmt.Sprintf("Only list certificates imported at least this many days ago, so a certificate "+
+					"imported moments ago and not yet attached is left alone. Defaults to %d.", defaultUnusedDays),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64Between(0, 36500),
+				},
+			},
+			"certificates": schema.ListNestedAttribute{
+				Description: "The unused certificates, oldest import first.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"certificate_arn": schema.StringAttribute{
+							Description: "The ACM certificate ARN.",
+							Computed:    true,
+						},
+						"domain_name": schema.StringAttribute{
+							Description: "The certificate's domain name.",
+							Computed:    true,
+						},
+						"serial_number": schema.StringAttribute{
+							Description: "The certificate's serial number, in lower case hex.",
+							Computed:    true,
+						},
+						"imported_at": schema.StringAttribute{
+							Description: "When the certificate was imported (RFC 3339).",
+							Computed:    true,
+						},
+						"expires_at": schema.StringAttribute{
+							Description: "When the certificate expires (RFC 3339).",
+							Computed:    true,
+						},
+						"managed": schema.BoolAttribute{
+							Description: "Whether the certificate carries the provider's ownership tags, meaning a " +
+								"cfcert_origin_certificate imported it.",
+							Computed: true,
+						},
+					},
+				},
+			},
+			"certificate_arns": schema.ListAttribute{
+				Description: "The ARNs of certificates, in the same order.",
+				ElementType: tfTypes.StringType,
+				Computed:    true,
+			},
+			"id": schema.StringAttribute{
+				Description: "Data source identifier.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *ACMUnusedCertificatesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*ProviderClients)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderClients, got: %T", req.ProviderData),
+		)
+		return
+	}
+	d.clients = clients
+}
+
+func (d *ACMUnusedCertificatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ACMUnusedCertificatesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	days := int64(defaultUnusedDays)
+	if !data.OlderThanDays.IsNull() {
+		days = data.OlderThanDays.ValueInt64()
+	}
+
+	unused, err := d.unusedOriginImports(ctx, time.Now().AddDate(0, 0, -int(days)))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list unused certificates", err.Error())
+		return
+	}
+
+	arns := make([]string, len(unused))
+	for i, cert := range unused {
+		arns[i] = cert.CertificateArn.ValueString()
+	}
+	certificates, diags := tfTypes.ListValueFrom(ctx, tfTypes.ObjectType{AttrTypes: acmUnusedCertificateAttrTypes()}, unused)
+	resp.Diagnostics.Append(diags...)
+	data.OlderThanDays = tfTypes.Int64Value(days)
+	data.Certificates = certificates
+	data.CertificateArns = stringList(arns)
+	data.ID = tfTypes.StringValue(fmt.Sprintf("%s/%d", d.clients.Region, days))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// unusedOriginImports returns the imported Origin CA certificates that are
+// not in use and were imported before cutoff, oldest first. ListCertificates
+// returns summaries in no particular order and without the issuer, so only
+// certificates that pass the cheaper checks are fetched.
+func (d *ACMUnusedCertificatesDataSource) unusedOriginImports(ctx context.Context, cutoff time.Time) ([]ACMUnusedCertificateModel, error) {
+	paginator := acm.NewListCertificatesPaginator(d.clients.ACMClient, &acm.ListCertificatesInput{
+		CertificateStatuses: []types.CertificateStatus{types.CertificateStatusIssued, types.CertificateStatusExpired},
+		Includes: &types.Filters{
+			KeyTypes: []types.KeyAlgorithm{types.KeyAlgorithmRsa2048, types.KeyAlgorithmEcPrime256v1},
+		},
+	})
+	var unused []ACMUnusedCertificateModel
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ACM certificates: %w", err)
+		}
+		for _, summary := range page.CertificateSummaryList {
+			if summary.Type != types.CertificateTypeImported || aws.ToBool(summary.InUse) ||
+				summary.ImportedAt == nil || summary.ImportedAt.After(cutoff) {
+				continue
+			}
+			arn := aws.ToString(summary.CertificateArn)
+			output, err := d.clients.ACMClient.GetCertificate(ctx, &acm.GetCertificateInput{CertificateArn: aws.String(arn)})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s: %w", arn, err)
+			}
+			parsed, err := parseCertificatePEM(aws.ToString(output.Certificate))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", arn, err)
+			}
+			if !isCloudflareOriginCA(parsed) {
+				continue
+			}
+			managed, err := d.clients.hasOwnershipMarker(ctx, arn)
+			if err != nil {
+				return nil, err
+			}
+			unused = append(unused, ACMUnusedCertificateModel{
+				CertificateArn: tfTypes.StringValue(arn),
+				DomainName:     tfTypes.StringValue(aws.ToString(summary.DomainName)),
+				SerialNumber:   tfTypes.StringValue(serialHex(parsed.SerialNumber)),
+				ImportedAt:     tfTypes.StringValue(summary.ImportedAt.UTC().Format(time.RFC3339)),
+				ExpiresAt:      tfTypes.StringValue(parsed.NotAfter.UTC().Format(time.RFC3339)),
+				Managed:        tfTypes.BoolValue(managed),
+			})
+		}
+	}
+	// RFC 3339 times in UTC sort chronologically as strings.
+	slices.SortStableFunc(unused, func(a, b ACMUnusedCertificateModel) int {
+		return strings.Compare(a.ImportedAt.ValueString(), b.ImportedAt.ValueString())
+	})
+	return unused, nil
+}
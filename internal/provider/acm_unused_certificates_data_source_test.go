@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+)
+
+func TestUnusedOriginImports(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	clients := newTestClients(mock)
+	ctx := context.Background()
+
+	fake := &pemACM{certificates: map[string]string{}}
+	fake.pageSize = 100
+	fake.tags = map[string]map[string]string{"arn:managed": {ownershipMarkerTag: "true"}}
+	now := time.Now()
+	for _, summary := range []struct {
+		arn        string
+		importedAt time.Time
+		inUse      bool
+	}{
+		{"arn:managed", now.AddDate(0, 0, -40), false},
+		{"arn:oldest", now.AddDate(0, 0, -90), false},
+		{"arn:in-use", now.AddDate(0, 0, -90), true},
+		{"arn:recent", now.AddDate(0, 0, -1), false},
+	} {
+		cert, _, err := clients.issueWithLocalKey(ctx, []string{"example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fake.certificates[summary.arn] = cert.Certificate
+		fake.summaries = append(fake.summaries, types.CertificateSummary{
+			CertificateArn: aws.String(summary.arn),
+			DomainName:     aws.String("example.com"),
+			Type:           types.CertificateTypeImported,
+			ImportedAt:     aws.Time(summary.importedAt),
+			InUse:          aws.Bool(summary.inUse),
+		})
+	}
+	clients.ACMClient = fake
+	d := &ACMUnusedCertificatesDataSource{clients: clients}
+
+	unused, err := d.unusedOriginImports(ctx, now.AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("unusedOriginImports: %v", err)
+	}
+	var got []string
+	for _, cert := range unused {
+		got = append(got, fmt.Sprintf("%s managed=%v", cert.CertificateArn.ValueString(), cert.Managed.ValueBool()))
+	}
+	if want := []string{"arn:oldest managed=false", "arn:managed managed=true"}; !slices.Equal(got, want) {
+		t.Errorf("unused = %v, want %v", got, want)
+	}
+}
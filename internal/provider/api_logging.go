@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Log subsystems for API calls. Each logs at the provider's level, set with
+// TF_LOG_PROVIDER_CFCERT, unless overridden with
+// TF_LOG_PROVIDER_CFCERT_<SUBSYSTEM>, e.g. TF_LOG_PROVIDER_CFCERT_ACM=off.
+const (
+	logSubsystemCloudflare = "cloudflare"
+	logSubsystemACM        = "acm"
+)
+
+// withDomainLogField records domain on every API call logged from ctx.
+func withDomainLogField(ctx context.Context, domain string) context.Context {
+	return tflog.SetField(ctx, "domain_name", domain)
+}
+
+// logAPICall logs a completed API call to subsystem: at DEBUG when it
+// succeeded first time, and at INFO when it was retried or failed. The call
+// is also counted for telemetry, when enabled.
+func logAPICall(ctx context.Context, subsystem, operation string, start time.Time, attempts int, err error, fields map[string]interface{}) {
+	telemetry.record(subsystem, operation, err)
+	ctx = tflog.NewSubsystem(ctx, subsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_CFCERT", subsystem), tflog.WithRootFields())
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	fields["operation"] = operation
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	fields["attempts"] = attempts
+	if err != nil {
+		fields["result"] = "error"
+		fields["error"] = err.Error()
+	} else {
+		fields["result"] = "success"
+	}
+
+	if err != nil || attempts > 1 {
+		tflog.SubsystemInfo(ctx, subsystem, "API call completed", fields)
+		return
+	}
+	tflog.SubsystemDebug(ctx, subsystem, "API call completed", fields)
+}
+
+// addACMCallLogging logs each ACM operation, after the SDK's retries, with
+// logAPICall.
+func addACMCallLogging(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("CfcertCallLogging", func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		start := time.Now()
+		out, metadata, err := next.HandleInitialize(ctx, in)
+		attempts := 1
+		if results, ok := retry.GetAttemptResults(metadata); ok && len(results.Results) > 0 {
+			attempts = len(results.Results)
+		}
+		logAPICall(ctx, logSubsystemACM, awsmiddleware.GetOperationName(ctx), start, attempts, err, nil)
+		if err != nil && attempts > 1 {
+			results, _ := retry.GetAttemptResults(metadata)
+			err = newACMRetryError(err, results.Results, time.Since(start))
+		}
+		return out, metadata, err
+	}), middleware.After)
+}
+
+// acmRetryError summarises the attempts of an ACM call that still failed
+// after the SDK retried it, like cloudflare.RetryError does for Cloudflare.
+type acmRetryError struct {
+	responses []string
+	elapsed   time.Duration
+	err       error
+}
+
+func newACMRetryError(err error, results []retry.AttemptResult, elapsed time.Duration) error {
+	responses := make([]string, len(results))
+	for i, result := range results {
+		var statusErr interface{ HTTPStatusCode() int }
+		if errors.As(result.Err, &statusErr) {
+			responses[i] = strconv.Itoa(statusErr.HTTPStatusCode())
+		} else {
+			responses[i] = "network error"
+		}
+	}
+	return &acmRetryError{responses: responses, elapsed: elapsed, err: err}
+}
+
+func (e *acmRetryError) Error() string {
+	return fmt.Sprintf("%s\n\nAttempts: %d\nResponses: %s\nElapsed: %s",
+		e.err, len(e.responses), strings.Join(e.responses, ", "), e.elapsed.Round(time.Millisecond))
+}
+
+func (e *acmRetryError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// defaultAssumeRoleSessionName names assume_role sessions that do not set
+// session_name, so CloudTrail shows which tool made the calls.
+const defaultAssumeRoleSessionName = "terraform-provider-cfcert"
+
+// AssumeRoleModel is a certificate resource's assume_role attribute.
+type AssumeRoleModel struct {
+	RoleArn     tfTypes.String `tfsdk:"role_arn"`
+	SessionName tfTypes.String `tfsdk:"session_name"`
+	ExternalID  tfTypes.String `tfsdk:"external_id"`
+}
+
+func assumeRoleAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"role_arn":     tfTypes.StringType,
+		"session_name": tfTypes.StringType,
+		"external_id":  tfTypes.StringType,
+	}
+}
+
+// assumeRoleProvider retrieves credentials for a role with STS AssumeRole,
+// calling STS with the provider's own credentials.
+type assumeRoleProvider struct {
+	sts         *awsQueryClient
+	region      string
+	roleArn     string
+	sessionName string
+	externalID  string
+}
+
+func (p *assumeRoleProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	params := url.Values{
+		"RoleArn":         {p.roleArn},
+		"RoleSessionName": {p.sessionName},
+	}
+	if p.externalID != "" {
+		params.Set("ExternalId", p.externalID)
+	}
+	var out struct {
+		AccessKeyID     string    `xml:"AssumeRoleResult>Credentials>AccessKeyId"`
+		SecretAccessKey string    `xml:"AssumeRoleResult>Credentials>SecretAccessKey"`
+		SessionToken    string    `xml:"AssumeRoleResult>Credentials>SessionToken"`
+		Expiration      time.Time `xml:"AssumeRoleResult>Credentials>Expiration"`
+	}
+	if err := p.sts.call(ctx, p.region, "AssumeRole", params, &out); err != nil {
+		return aws.Credentials{}, fmt.Errorf("failed to assume role %s: %w", p.roleArn, err)
+	}
+	return aws.Credentials{
+		AccessKeyID:     out.AccessKeyID,
+		SecretAccessKey: out.SecretAccessKey,
+		SessionToken:    out.SessionToken,
+		Source:          "AssumeRole",
+		CanExpire:       true,
+		Expires:         out.Expiration,
+	}, nil
+}
+
+// assumedRoleClients shares the clients for each assumed role between every
+// resource using it, so credentials and the certificate listing are cached
+// per account rather than per resource.
+type assumedRoleClients struct {
+	mu      sync.Mutex
+	clients map[AssumeRoleModel]*ProviderClients
+}
+
+func newAssumedRoleClients() *assumedRoleClients {
+	return &assumedRoleClients{clients: map[AssumeRoleModel]*ProviderClients{}}
+}
+
+// forRole returns a copy of c whose ACM, Service Quotas and AWS key store
+// calls are made as role, in the provider's region. It has its own issuance
+// locks, since the certificates it imports are in role's account; everything
+// else, including Vault and the Cloudflare client, is shared with c.
+func (c *ProviderClients) forRole(role AssumeRoleModel) *ProviderClients {
+	if role.SessionName.IsNull() || role.SessionName.ValueString() == "" {
+		role.SessionName = tfTypes.StringValue(defaultAssumeRoleSessionName)
+	}
+	if role.ExternalID.IsNull() {
+		role.ExternalID = tfTypes.StringValue("")
+	}
+
+	c.AssumedRoles.mu.Lock()
+	defer c.AssumedRoles.mu.Unlock()
+	if clients, ok := c.AssumedRoles.clients[role]; ok {
+		return clients
+	}
+
+	cfg := c.AWSConfig.Copy()
+	cfg.Credentials = aws.NewCredentialsCache(&assumeRoleProvider{
+		sts:         newAWSQueryClient(c.AWSConfig, "sts", "2011-06-15"),
+		region:      c.Region,
+		roleArn:     role.RoleArn.ValueString(),
+		sessionName: role.SessionName.ValueString(),
+		externalID:  role.ExternalID.ValueString(),
+	})
+	clients := *c
+	clients.AWSConfig = cfg
+	clients.ACMClient = newACMClient(cfg)
+	clients.ServiceQuotasClient = &serviceQuotasClient{api: newAWSJSONClient(cfg, "servicequotas", "ServiceQuotasV20190624")}
+	clients.SecretsManagerClient = &secretsManagerClient{api: newAWSJSONClient(cfg, "secretsmanager", "secretsmanager")}
+	clients.SSMClient = &ssmClient{api: newAWSJSONClient(cfg, "ssm", "AmazonSSM")}
+	clients.S3Client = newS3Client(cfg)
+	clients.CertificateList = newCertificateListCache()
+	clients.Issuances = newIssuanceLocks()
+	c.AssumedRoles.clients[role] = &clients
+	return &clients
+}
+
+// withAssumeRole returns r, or a copy of r that makes its AWS calls as the
+// role in assumeRole when it is set.
+func (r *CertificateResource) withAssumeRole(ctx context.Context, assumeRole tfTypes.Object, diags *diag.Diagnostics) *CertificateResource {
+	if assumeRole.IsNull() || assumeRole.IsUnknown() {
+		return r
+	}
+	var role AssumeRoleModel
+	diags.Append(assumeRole.As(ctx, &role, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return r
+	}
+	return &CertificateResource{clients: r.clients.forRole(role)}
+}
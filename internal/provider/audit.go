@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// auditLog writes an audit record to S3 for each certificate issued, renewed
+// or deleted. Every record is a new object, written so it cannot replace an
+// existing one, so the log does not depend on Terraform state.
+type auditLog struct {
+	s3     *s3Client
+	sts    *awsQueryClient
+	region string
+	bucket string
+	prefix string
+
+	identityOnce sync.Once
+	identity     string
+	identityErr  error
+}
+
+// auditRecord is one audit object. Field names are part of the record's
+// contract, so only add to them.
+type auditRecord struct {
+	Time                     string   `json:"time"`
+	Actor                    string   `json:"actor"`
+	Action                   string   `json:"action"`
+	DomainName               string   `json:"domain_name"`
+	CertificateArns          []string `json:"certificate_arns,omitempty"`
+	CloudflareCertificateIDs []string `json:"cloudflare_certificate_ids,omitempty"`
+	SerialNumber             string   `json:"serial_number,omitempty"`
+	Workspace                string   `json:"workspace,omitempty"`
+}
+
+// parseS3URI splits an s3://bucket/prefix URI. The prefix, if any, ends in a
+// slash.
+func parseS3URI(uri string) (bucket, prefix string, err error) {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "s3" || parsed.Host == "" {
+		return "", "", fmt.Errorf("%q is not an s3://bucket/prefix URI", uri)
+	}
+	prefix = strings.TrimPrefix(parsed.Path, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return parsed.Host, prefix, nil
+}
+
+// actor returns the ARN of the AWS identity the provider runs as, looked up
+// once.
+func (l *auditLog) actor(ctx context.Context) (string, error) {
+	l.identityOnce.Do(func() {
+		var out struct {
+			Arn string `xml:"GetCallerIdentityResult>Arn"`
+		}
+		l.identityErr = l.sts.call(ctx, l.region, "GetCallerIdentity", url.Values{}, &out)
+		l.identity = out.Arn
+	})
+	return l.identity, l.identityErr
+}
+
+// write stores record under the log's prefix, keyed by date so records list
+// in the order they were written.
+func (l *auditLog) write(ctx context.Context, record auditRecord) error {
+	actor, err := l.actor(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to identify the AWS caller: %w", err)
+	}
+	now := time.Now().UTC()
+	record.Time = now.Format(time.RFC3339Nano)
+	record.Actor = actor
+
+	body, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("failed to name audit record: %w", err)
+	}
+	key := fmt.Sprintf("%s%s/%s-%s-%s-%s.json", l.prefix, now.Format("2006/01/02"), now.Format("150405.000000000"),
+		record.Action, strings.ReplaceAll(record.DomainName, "*", "wildcard"), hex.EncodeToString(suffix))
+	return l.s3.putNewObject(ctx, l.bucket, key, "application/json", body)
+}
@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// awsJSONClient calls AWS services that speak the JSON 1.1 protocol (KMS,
+// EventBridge, ...) using SigV4-signed HTTP requests, for services whose SDK
+// modules are not part of this provider's dependency set.
+type awsJSONClient struct {
+	cfg          aws.Config
+	signingName  string
+	targetPrefix string
+	endpoint     string
+	httpClient   *http.Client
+}
+
+// awsAPIError is returned when an AWS JSON service responds with an error.
+type awsAPIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *awsAPIError) Error() string {
+	return fmt.Sprintf("%s: %s (status %d)", e.Code, e.Message, e.StatusCode)
+}
+
+func newAWSJSONClient(cfg aws.Config, signingName, targetPrefix string) *awsJSONClient {
+	return &awsJSONClient{
+		cfg:          cfg,
+		signingName:  signingName,
+		targetPrefix: targetPrefix,
+		endpoint:     awsEndpoint(cfg, signingName, cfg.Region),
+		httpClient:   newLoggingHTTPClient("AWS "+signingName, nil),
+	}
+}
+
+// awsServiceIDs maps the signing names of the services called through
+// awsJSONClient and awsQueryClient to their SDK service IDs, which name their
+// AWS_ENDPOINT_URL_<SERVICE> variables and services sections in the shared
+// config file.
+var awsServiceIDs = map[string]string{
+	"events":         "EventBridge",
+	"kms":            "KMS",
+	"secretsmanager": "Secrets Manager",
+	"servicequotas":  "Service Quotas",
+	"sns":            "SNS",
+	"ssm":            "SSM",
+	"sts":            "STS",
+}
+
+// awsEndpoint returns the endpoint for signingName in region, resolved the way
+// the SDK's own service clients resolve theirs: a service specific endpoint
+// URL, then cfg.BaseEndpoint (AWS_ENDPOINT_URL), then the regional endpoint
+// in region's partition.
+func awsEndpoint(cfg aws.Config, signingName, region string) string {
+	ctx := context.Background()
+	sdkID := awsServiceIDs[signingName]
+	// As in the SDK, AWS_ENDPOINT_URL takes precedence over a service endpoint
+	// from the shared config file, but not over the service's own variable.
+	_, global := os.LookupEnv("AWS_ENDPOINT_URL")
+	_, service := os.LookupEnv("AWS_ENDPOINT_URL_" + strings.ToUpper(strings.ReplaceAll(sdkID, " ", "_")))
+	ignore, _, _ := config.GetIgnoreConfiguredEndpoints(ctx, cfg.ConfigSources)
+	if !ignore && (!global || service) {
+		for _, source := range cfg.ConfigSources {
+			p, ok := source.(interface {
+				GetServiceBaseEndpoint(ctx context.Context, sdkID string) (string, bool, error)
+			})
+			if !ok {
+				continue
+			}
+			if endpoint, found, err := p.GetServiceBaseEndpoint(ctx, sdkID); err == nil && found {
+				return endpoint
+			}
+		}
+	}
+	if cfg.BaseEndpoint != nil {
+		return *cfg.BaseEndpoint
+	}
+	return fmt.Sprintf("https://%s.%s.%s/", signingName, region, awsDNSSuffix(region))
+}
+
+// awsDNSSuffix returns the DNS suffix of region's partition. GovCloud regions
+// share the commercial suffix.
+func awsDNSSuffix(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "amazonaws.com.cn"
+	case strings.HasPrefix(region, "us-iso-"):
+		return "c2s.ic.gov"
+	case strings.HasPrefix(region, "us-isob-"):
+		return "sc2s.sgov.gov"
+	case strings.HasPrefix(region, "us-isof-"):
+		return "csp.hci.ic.gov"
+	case strings.HasPrefix(region, "eu-isoe-"):
+		return "cloud.adc-e.uk"
+	default:
+		return "amazonaws.com"
+	}
+}
+
+func (c *awsJSONClient) call(ctx context.Context, action string, input, output interface{}) error {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", action, err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", action, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	httpReq.Header.Set("X-Amz-Target", c.targetPrefix+"."+action)
+
+	creds, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	err = v4.NewSigner().SignHTTP(ctx, creds, httpReq, hex.EncodeToString(sum[:]), c.signingName, c.cfg.Region, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to sign %s request: %w", action, err)
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send %s request: %w", action, err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", action, err)
+	}
+
+	if httpResp.StatusCode >= 300 {
+		var errResp struct {
+			Type         string `json:"__type"`
+			Message      string `json:"message"`
+			MessageUpper string `json:"Message"`
+		}
+		_ = json.Unmarshal(body, &errResp)
+		apiErr := &awsAPIError{
+			StatusCode: httpResp.StatusCode,
+			Code:       errResp.Type[strings.LastIndex(errResp.Type, "#")+1:],
+			Message:    errResp.Message,
+		}
+		if apiErr.Message == "" {
+			apiErr.Message = errResp.MessageUpper
+		}
+		if apiErr.Code == "" {
+			apiErr.Code = http.StatusText(httpResp.StatusCode)
+		}
+		return apiErr
+	}
+
+	if output == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, output); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", action, err)
+	}
+	return nil
+}
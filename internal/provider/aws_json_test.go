@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+func TestAWSEndpoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		region      string
+		env         map[string]string
+		signingName string
+		want        string
+	}{
+		{name: "commercial", region: "us-east-1", signingName: "kms", want: "https://kms.us-east-1.amazonaws.com/"},
+		{name: "GovCloud", region: "us-gov-west-1", signingName: "ssm", want: "https://ssm.us-gov-west-1.amazonaws.com/"},
+		{name: "China", region: "cn-north-1", signingName: "secretsmanager", want: "https://secretsmanager.cn-north-1.amazonaws.com.cn/"},
+		{name: "ISO", region: "us-iso-east-1", signingName: "events", want: "https://events.us-iso-east-1.c2s.ic.gov/"},
+		{
+			name: "global endpoint URL", region: "us-east-1", signingName: "servicequotas",
+			env:  map[string]string{"AWS_ENDPOINT_URL": "http://localhost:4566"},
+			want: "http://localhost:4566",
+		},
+		{
+			name: "service endpoint URL", region: "us-east-1", signingName: "secretsmanager",
+			env: map[string]string{
+				"AWS_ENDPOINT_URL":                 "http://localhost:4566",
+				"AWS_ENDPOINT_URL_SECRETS_MANAGER": "http://localhost:4567",
+			},
+			want: "http://localhost:4567",
+		},
+		{
+			name: "another service's endpoint URL", region: "us-east-1", signingName: "kms",
+			env:  map[string]string{"AWS_ENDPOINT_URL_SECRETS_MANAGER": "http://localhost:4567"},
+			want: "https://kms.us-east-1.amazonaws.com/",
+		},
+		{
+			name: "configured endpoints ignored", region: "us-east-1", signingName: "kms",
+			env: map[string]string{
+				"AWS_ENDPOINT_URL":                    "http://localhost:4566",
+				"AWS_ENDPOINT_URL_KMS":                "http://localhost:4567",
+				"AWS_IGNORE_CONFIGURED_ENDPOINT_URLS": "true",
+			},
+			want: "https://kms.us-east-1.amazonaws.com/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+			for _, name := range []string{"AWS_ENDPOINT_URL", "AWS_ENDPOINT_URL_KMS", "AWS_ENDPOINT_URL_SECRETS_MANAGER", "AWS_IGNORE_CONFIGURED_ENDPOINT_URLS"} {
+				// Setenv restores the variable after the test; unset the
+				// ones this case leaves out, as LookupEnv sees empty values.
+				t.Setenv(name, tt.env[name])
+				if _, ok := tt.env[name]; !ok {
+					os.Unsetenv(name)
+				}
+			}
+			cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(tt.region))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := newAWSJSONClient(cfg, tt.signingName, "").endpoint; got != tt.want {
+				t.Errorf("endpoint = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// awsQueryClient calls AWS services that speak the Query protocol (SNS, ...)
+// using SigV4-signed HTTP requests.
+type awsQueryClient struct {
+	cfg         aws.Config
+	signingName string
+	apiVersion  string
+	httpClient  *http.Client
+}
+
+func newAWSQueryClient(cfg aws.Config, signingName, apiVersion string) *awsQueryClient {
+	return &awsQueryClient{
+		cfg:         cfg,
+		signingName: signingName,
+		apiVersion:  apiVersion,
+		httpClient:  newLoggingHTTPClient("AWS "+signingName, nil),
+	}
+}
+
+// call invokes action in region, decoding the XML response into output when
+// it is non-nil.
+func (c *awsQueryClient) call(ctx context.Context, region, action string, params url.Values, output interface{}) error {
+	form := url.Values{}
+	for k, v := range params {
+		form[k] = v
+	}
+	form.Set("Action", action)
+	form.Set("Version", c.apiVersion)
+	payload := form.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", awsEndpoint(c.cfg, c.signingName, region), strings.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", action, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	creds, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	sum := sha256.Sum256([]byte(payload))
+	err = v4.NewSigner().SignHTTP(ctx, creds, httpReq, hex.EncodeToString(sum[:]), c.signingName, region, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to sign %s request: %w", action, err)
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send %s request: %w", action, err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", action, err)
+	}
+
+	if httpResp.StatusCode >= 300 {
+		var errResp struct {
+			Error struct {
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			} `xml:"Error"`
+		}
+		_ = xml.Unmarshal(body, &errResp)
+		apiErr := &awsAPIError{
+			StatusCode: httpResp.StatusCode,
+			Code:       errResp.Error.Code,
+			Message:    errResp.Error.Message,
+		}
+		if apiErr.Code == "" {
+			apiErr.Code = http.StatusText(httpResp.StatusCode)
+		}
+		return apiErr
+	}
+
+	if output == nil {
+		return nil
+	}
+	if err := xml.Unmarshal(body, output); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", action, err)
+	}
+	return nil
+}
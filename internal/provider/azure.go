@@ -0,0 +1,252 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	azureKeyVaultAPIVersion = "7.4"
+	azureKeyVaultResource   = "https://vault.azure.net"
+	azureIMDSTokenURL       = "http://169.254.169.254/metadata/identity/oauth2/token"
+)
+
+// errAzureNotFound is returned when an Azure API responds with 404.
+var errAzureNotFound = errors.New("not found")
+
+// azureClient calls the Azure Key Vault REST API. Access tokens are taken, in
+// order, from an explicit token, a service principal client secret, or the
+// managed identity endpoint.
+type azureClient struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	AccessToken  string
+	httpClient   *http.Client
+	// pollInterval overrides the 2 second wait between polls in tests.
+	pollInterval time.Duration
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func (c *azureClient) client() *http.Client {
+	if c.httpClient == nil {
+		return newLoggingHTTPClient("Azure", nil)
+	}
+	return c.httpClient
+}
+
+func (c *azureClient) accessToken(ctx context.Context) (string, error) {
+	if c.AccessToken != "" {
+		return c.AccessToken, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Now().Before(c.tokenExpiry.Add(-time.Minute)) {
+		return c.token, nil
+	}
+
+	var httpReq *http.Request
+	var err error
+	if c.ClientSecret != "" {
+		form := url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {c.ClientID},
+			"client_secret": {c.ClientSecret},
+			"scope":         {azureKeyVaultResource + "/.default"},
+		}
+		tokenURL := fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", url.PathEscape(c.TenantID))
+		httpReq, err = http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		query := url.Values{"api-version": {"2018-02-01"}, "resource": {azureKeyVaultResource}}
+		if c.ClientID != "" {
+			query.Set("client_id", c.ClientID)
+		}
+		httpReq, err = http.NewRequestWithContext(ctx, "GET", azureIMDSTokenURL+"?"+query.Encode(), nil)
+		if err != nil {
+			return "", err
+		}
+		httpReq.Header.Set("Metadata", "true")
+	}
+
+	httpResp, err := c.client().Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to request Azure access token: %w", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("azure token endpoint returned status %d", httpResp.StatusCode)
+	}
+
+	var tokenResp struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	if err := json.NewDecoder(httpResp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to parse Azure access token: %w", err)
+	}
+	expiresIn, _ := tokenResp.ExpiresIn.Int64()
+	c.token = tokenResp.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	return c.token, nil
+}
+
+func (c *azureClient) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResp, err := c.client().Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode == http.StatusNotFound {
+		return errAzureNotFound
+	}
+	if httpResp.StatusCode >= 300 {
+		var azErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &azErr) == nil && azErr.Error.Message != "" {
+			return fmt.Errorf("azure API error (%s): %s", azErr.Error.Code, azErr.Error.Message)
+		}
+		return fmt.Errorf("azure API error (status %d)", httpResp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+type azureKeyVaultCertificate struct {
+	ID         string `json:"id"`
+	Thumbprint string `json:"x5t"`
+	Attributes struct {
+		Expires int64 `json:"exp"`
+	} `json:"attributes"`
+}
+
+func azureCertificateURL(vaultURL, name string) string {
+	return fmt.Sprintf("%s/certificates/%s", strings.TrimSuffix(vaultURL, "/"), url.PathEscape(name))
+}
+
+// importCertificate imports a PEM bundle (certificate followed by a PKCS#8
+// private key) as a Key Vault certificate.
+func (c *azureClient) importCertificate(ctx context.Context, vaultURL, name, pemBundle string) (azureKeyVaultCertificate, error) {
+	var cert azureKeyVaultCertificate
+	err := c.do(ctx, "POST", azureCertificateURL(vaultURL, name)+"/import?api-version="+azureKeyVaultAPIVersion, map[string]interface{}{
+		"value": pemBundle,
+		"policy": map[string]interface{}{
+			"key_props":    map[string]interface{}{"kty": "EC", "crv": "P-256", "exportable": true},
+			"secret_props": map[string]interface{}{"contentType": "application/x-pem-file"},
+		},
+	}, &cert)
+	return cert, err
+}
+
+func (c *azureClient) getCertificate(ctx context.Context, vaultURL, name string) (azureKeyVaultCertificate, error) {
+	var cert azureKeyVaultCertificate
+	err := c.do(ctx, "GET", azureCertificateURL(vaultURL, name)+"?api-version="+azureKeyVaultAPIVersion, nil, &cert)
+	return cert, err
+}
+
+func (c *azureClient) deleteCertificate(ctx context.Context, vaultURL, name string) error {
+	return c.do(ctx, "DELETE", azureCertificateURL(vaultURL, name)+"?api-version="+azureKeyVaultAPIVersion, nil, nil)
+}
+
+// azurePurgeWait is how long purgeDeletedCertificate waits for Key Vault to
+// finish a deletion, and then the purge, before giving up.
+const azurePurgeWait = 2 * time.Minute
+
+// purgeDeletedCertificate permanently deletes a soft-deleted certificate, so
+// its name can be used again. Key Vault deletes and purges asynchronously: a
+// deleted certificate can only be purged once it is listed as deleted, and
+// its name is only free once it is no longer listed.
+func (c *azureClient) purgeDeletedCertificate(ctx context.Context, vaultURL, name string) error {
+	deletedURL := fmt.Sprintf("%s/deletedcertificates/%s?api-version=%s", strings.TrimSuffix(vaultURL, "/"), url.PathEscape(name), azureKeyVaultAPIVersion)
+	if err := c.waitForDeleted(ctx, deletedURL, false); err != nil {
+		return err
+	}
+	if err := c.do(ctx, "DELETE", deletedURL, nil, nil); err != nil && !errors.Is(err, errAzureNotFound) {
+		return err
+	}
+	return c.waitForDeleted(ctx, deletedURL, true)
+}
+
+// waitForDeleted polls deletedURL until the deleted certificate is listed,
+// or when purged is set, until it no longer is.
+func (c *azureClient) waitForDeleted(ctx context.Context, deletedURL string, purged bool) error {
+	interval := c.pollInterval
+	if interval == 0 {
+		interval = 2 * time.Second
+	}
+	deadline := time.Now().Add(azurePurgeWait)
+	for {
+		err := c.do(ctx, "GET", deletedURL, nil, nil)
+		if err != nil && !errors.Is(err, errAzureNotFound) {
+			return err
+		}
+		if purged == (err != nil) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if purged {
+				return fmt.Errorf("deleted certificate was still listed %s after it was purged", azurePurgeWait)
+			}
+			return fmt.Errorf("certificate was not listed as deleted within %s", azurePurgeWait)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &AzureKeyVaultCertificateResource{}
+var _ resource.ResourceWithConfigure = &AzureKeyVaultCertificateResource{}
+
+type AzureKeyVaultCertificateResource struct {
+	clients *ProviderClients
+}
+
+type AzureKeyVaultCertificateResourceModel struct {
+	DomainName     tfTypes.String `tfsdk:"domain_name"`
+	KeyVaultURL    tfTypes.String `tfsdk:"key_vault_url"`
+	Name           tfTypes.String `tfsdk:"name"`
+	CertificatePEM tfTypes.String `tfsdk:"certificate_pem"`
+	Thumbprint     tfTypes.String `tfsdk:"thumbprint"`
+	Expires        tfTypes.String `tfsdk:"expires"`
+	ID             tfTypes.String `tfsdk:"id"`
+}
+
+func NewAzureKeyVaultCertificateResource() resource.Resource {
+	return &AzureKeyVaultCertificateResource{}
+}
+
+func (r *AzureKeyVaultCertificateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_azure_key_vault_certificate"
+}
+
+func (r *AzureKeyVaultCertificateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Issues a Cloudflare Origin Certificate and imports the certificate and key into Azure Key Vault.",
+		Attributes: map[string]schema.Attribute{
+			"domain_name": schema.StringAttribute{
+				Description: "The domain name for the certificate.",
+				Required:    true,
+				Validators: []validator.String{
+					validHostname(),
+				},
+				PlanModifiers: []planmodifier.String{
+					equivalentHostname(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"key_vault_url": schema.StringAttribute{
+				Description: "The Key Vault URL, e.g. https://my-vault.vault.azure.net.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The Key Vault certificate name.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"certificate_pem": schema.StringAttribute{
+				Description: "The issued certificate in PEM format.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"thumbprint": schema.StringAttribute{
+				Description: "The base64url SHA-1 thumbprint (x5t) reported by Key Vault.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"expires": schema.StringAttribute{
+				Description: "The certificate expiry time reported by Key Vault (RFC 3339).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Description: "The versioned Key Vault certificate ID.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AzureKeyVaultCertificateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*ProviderClients)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderClients, got: %T", req.ProviderData),
+		)
+		return
+	}
+	r.clients = clients
+}
+
+func (r *AzureKeyVaultCertificateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AzureKeyVaultCertificateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withDomainLogField(ctx, data.DomainName.ValueString())
+	issued, keyPEM, err := r.clients.issueWithLocalKey(ctx, []string{data.DomainName.ValueString()})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to issue certificate", err.Error())
+		return
+	}
+	defer zeroize(keyPEM)
+	certPEM := issued.Certificate
+
+	// Key Vault only accepts PEM private keys in PKCS#8 form.
+	pkcs8PEM, err := ecKeyPEMToPKCS8(keyPEM)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to convert private key", err.Error())
+		return
+	}
+	defer zeroize(pkcs8PEM)
+
+	cert, err := r.clients.AzureClient.importCertificate(ctx, data.KeyVaultURL.ValueString(), data.Name.ValueString(), certPEM+string(pkcs8PEM))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to import certificate to Key Vault", err.Error())
+		return
+	}
+
+	data.ID = tfTypes.StringValue(cert.ID)
+	data.CertificatePEM = tfTypes.StringValue(certPEM)
+	data.Thumbprint = tfTypes.StringValue(cert.Thumbprint)
+	data.Expires = tfTypes.StringValue(time.Unix(cert.Attributes.Expires, 0).UTC().Format(time.RFC3339))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AzureKeyVaultCertificateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AzureKeyVaultCertificateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cert, err := r.clients.AzureClient.getCertificate(ctx, data.KeyVaultURL.ValueString(), data.Name.ValueString())
+	if errors.Is(err, errAzureNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read Key Vault certificate", err.Error())
+		return
+	}
+
+	// A different latest version means the certificate was replaced outside
+	// Terraform; dropping it from state plans a fresh import.
+	if cert.ID != data.ID.ValueString() {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	r.clients.warnIfExpiring(&resp.Diagnostics, data.DomainName.ValueString(), data.Expires.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AzureKeyVaultCertificateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All configurable attributes force replacement, so Update is a no-op
+	var data AzureKeyVaultCertificateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AzureKeyVaultCertificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data AzureKeyVaultCertificateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.clients.AzureClient.deleteCertificate(ctx, data.KeyVaultURL.ValueString(), data.Name.ValueString())
+	if err != nil && !errors.Is(err, errAzureNotFound) {
+		resp.Diagnostics.AddError("Failed to delete Key Vault certificate", err.Error())
+		return
+	}
+
+	// Soft-delete keeps the name taken, so a replacement with the same name
+	// would fail to import with a conflict until the certificate is purged.
+	err = r.clients.AzureClient.purgeDeletedCertificate(ctx, data.KeyVaultURL.ValueString(), data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Key Vault Certificate Not Purged",
+			fmt.Sprintf("%s was deleted, but purging it failed: %s. It stays recoverable, and a new certificate named %q "+
+				"cannot be imported until it is purged or recovered. Purging needs the certificates purge permission and is "+
+				"refused while the vault has purge protection.", data.Name.ValueString(), err, data.Name.ValueString()),
+		)
+	}
+}
+
+func ecKeyPEMToPKCS8(keyPEM []byte) ([]byte, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("invalid private key PEM")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	zeroize(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroizeECKey(key)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroize(der)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// fakeKeyVault is a Key Vault with soft-delete: a deleted certificate keeps
+// its name until it is purged, and is only listed as deleted after the first
+// poll, as deletion finishes asynchronously.
+type fakeKeyVault struct {
+	mu           sync.Mutex
+	versions     map[string]int
+	deleted      map[string]int
+	refusePurges bool
+}
+
+func (v *fakeKeyVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	name := parts[1]
+	writeError := func(status int, code string) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"code": code, "message": code}})
+	}
+	switch {
+	case r.Method == http.MethodPost && parts[0] == "certificates" && len(parts) == 3 && parts[2] == "import":
+		if _, ok := v.deleted[name]; ok {
+			writeError(http.StatusConflict, "ObjectIsDeletedButRecoverable")
+			return
+		}
+		if _, ok := v.versions[name]; ok {
+			writeError(http.StatusConflict, "Conflict")
+			return
+		}
+		v.versions[name]++
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":         fmt.Sprintf("https://%s/certificates/%s/v%d", r.Host, name, v.versions[name]),
+			"x5t":        "thumbprint",
+			"attributes": map[string]int64{"exp": 2000000000},
+		})
+	case r.Method == http.MethodDelete && parts[0] == "certificates":
+		if _, ok := v.versions[name]; !ok {
+			writeError(http.StatusNotFound, "CertificateNotFound")
+			return
+		}
+		delete(v.versions, name)
+		v.deleted[name] = 1
+	case r.Method == http.MethodGet && parts[0] == "deletedcertificates":
+		pending, ok := v.deleted[name]
+		if !ok {
+			writeError(http.StatusNotFound, "CertificateNotFound")
+			return
+		}
+		if pending > 0 {
+			v.deleted[name]--
+			writeError(http.StatusNotFound, "CertificateNotFound")
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"recoveryId": r.URL.Path})
+	case r.Method == http.MethodDelete && parts[0] == "deletedcertificates":
+		if v.refusePurges {
+			writeError(http.StatusForbidden, "Forbidden")
+			return
+		}
+		delete(v.deleted, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestAzureKeyVaultCertificateReplace(t *testing.T) {
+	tests := []struct {
+		name         string
+		refusePurges bool
+	}{
+		{name: "purged"},
+		{name: "purge refused", refusePurges: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := &fakeKeyVault{versions: map[string]int{}, deleted: map[string]int{}, refusePurges: tt.refusePurges}
+			server := httptest.NewServer(vault)
+			t.Cleanup(server.Close)
+			clients := newTestClients(cloudflaretest.NewServer(t))
+			clients.AzureClient = &azureClient{AccessToken: "test-token", httpClient: server.Client(), pollInterval: 1}
+			r := &AzureKeyVaultCertificateResource{clients: clients}
+			values := map[string]tftypes.Value{
+				"domain_name":   tftypes.NewValue(tftypes.String, "example.com"),
+				"key_vault_url": tftypes.NewValue(tftypes.String, server.URL),
+				"name":          tftypes.NewValue(tftypes.String, "example"),
+			}
+
+			created := createResource(t, r, values)
+			if created.Diagnostics.HasError() {
+				t.Fatalf("Create: %v", created.Diagnostics)
+			}
+			deleteResp := &resource.DeleteResponse{State: created.State}
+			r.Delete(context.Background(), resource.DeleteRequest{State: created.State}, deleteResp)
+			if deleteResp.Diagnostics.HasError() {
+				t.Fatalf("Delete: %v", deleteResp.Diagnostics)
+			}
+
+			replaced := createResource(t, r, values)
+			if tt.refusePurges {
+				if deleteResp.Diagnostics.WarningsCount() != 1 {
+					t.Errorf("Delete diagnostics = %v, want a warning that the certificate was not purged", deleteResp.Diagnostics)
+				}
+				if !replaced.Diagnostics.HasError() {
+					t.Error("replacement imported over a certificate that was not purged")
+				}
+				return
+			}
+			if len(deleteResp.Diagnostics) != 0 {
+				t.Errorf("Delete diagnostics = %v, want none", deleteResp.Diagnostics)
+			}
+			if replaced.Diagnostics.HasError() {
+				t.Fatalf("replacement Create: %v", replaced.Diagnostics)
+			}
+			if _, ok := vault.deleted["example"]; ok {
+				t.Error("the deleted certificate was not purged")
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ ephemeral.EphemeralResource = &CertificateBundleEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &CertificateBundleEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigValidators = &CertificateBundleEphemeralResource{}
+
+// CertificateBundleEphemeralResource reads a certificate, its private key and
+// its Origin CA root from the key store a cfcert_origin_certificate wrote
+// them to, at apply time only, for other providers' write-only arguments.
+type CertificateBundleEphemeralResource struct {
+	clients *ProviderClients
+}
+
+type CertificateBundleEphemeralResourceModel struct {
+	VaultKVPath            tfTypes.String `tfsdk:"vault_kv_path"`
+	SecretsManagerSecretID tfTypes.String `tfsdk:"secrets_manager_secret_id"`
+	SSMParameterName       tfTypes.String `tfsdk:"ssm_parameter_name"`
+	S3ObjectURI            tfTypes.String `tfsdk:"s3_object_uri"`
+	Index                  tfTypes.Int64  `tfsdk:"index"`
+	DomainName             tfTypes.String `tfsdk:"domain_name"`
+	CertificateArn         tfTypes.String `tfsdk:"certificate_arn"`
+	CertificatePEM         tfTypes.String `tfsdk:"certificate_pem"`
+	PrivateKeyPEM          tfTypes.String `tfsdk:"private_key_pem"`
+	CACertificatePEM       tfTypes.String `tfsdk:"ca_certificate_pem"`
+	FullChainPEM           tfTypes.String `tfsdk:"full_chain_pem"`
+	ExpiresAt              tfTypes.String `tfsdk:"expires_at"`
+}
+
+func NewCertificateBundleEphemeralResource() ephemeral.EphemeralResource {
+	return &CertificateBundleEphemeralResource{}
+}
+
+func (e *CertificateBundleEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certificate_bundle"
+}
+
+func (e *CertificateBundleEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads the certificate and private key a cfcert_origin_certificate stored in a key store, together with the " +
+			"Cloudflare Origin CA root that issued it, at apply time only. Pass the results to other providers' write-only " +
+			"arguments, such as a database's TLS configuration, so the private key is never written to plan or state. Set " +
+			"exactly one key store attribute.",
+		Attributes: map[string]schema.Attribute{
+			"vault_kv_path": schema.StringAttribute{
+				Description: "Read the bundle stored at this Vault KV version 2 path, as \"<mount>/<path>\".",
+				Optional:    true,
+			},
+			"secrets_manager_secret_id": schema.StringAttribute{
+				Description: "Read the bundle stored in this Secrets Manager secret.",
+				Optional:    true,
+			},
+			"ssm_parameter_name": schema.StringAttribute{
+				Description: "Read the bundle stored in this SSM Parameter Store parameter.",
+				Optional:    true,
+			},
+			"s3_object_uri": schema.StringAttribute{
+				Description: "Read the bundle stored in this s3://bucket/key object.",
+				Optional:    true,
+			},
+			"index": schema.Int64Attribute{
+				Description: "Which certificate to read when the resource stored several, one per entry in its certificate_arns. " +
+					"Defaults to 0, the first.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64Between(0, 99),
+				},
+			},
+			"domain_name": schema.StringAttribute{
+				Description: "The domain name the certificate was issued for.",
+				Computed:    true,
+			},
+			"certificate_arn": schema.StringAttribute{
+				Description: "The ARN the certificate was imported into ACM as.",
+				Computed:    true,
+			},
+			"certificate_pem": schema.StringAttribute{
+				Description: "The certificate in PEM format.",
+				Computed:    true,
+			},
+			"private_key_pem": schema.StringAttribute{
+				Description: "The private key in PEM format.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"ca_certificate_pem": schema.StringAttribute{
+				Description: "The Cloudflare Origin CA root certificate that issued the certificate, in PEM format, for " +
+					"clients that verify the origin. Null when it does not chain to one of the provider's Origin CA roots.",
+				Computed: true,
+			},
+			"full_chain_pem": schema.StringAttribute{
+				Description: "certificate_pem followed by ca_certificate_pem, for servers that take the chain in one file.",
+				Computed:    true,
+			},
+			"expires_at": schema.StringAttribute{
+				Description: "When the certificate expires (RFC 3339).",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (e *CertificateBundleEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*ProviderClients)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderClients, got: %T", req.ProviderData),
+		)
+		return
+	}
+	e.clients = clients
+}
+
+func (e *CertificateBundleEphemeralResource) ConfigValidators(ctx context.Context) []ephemeral.ConfigValidator {
+	return []ephemeral.ConfigValidator{
+		exactlyOneOf(keyStoreAttributes()...),
+	}
+}
+
+func (e *CertificateBundleEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data CertificateBundleEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// keyStoreData suffixes every certificate after the first with its index.
+	suffix := ""
+	if index := data.Index.ValueInt64(); index > 0 {
+		suffix = fmt.Sprintf("_%d", index)
+	}
+	secret := e.clients.readStoredCertificate(ctx, data.keyStoreLocations(), suffix, &resp.Diagnostics)
+	if secret == nil {
+		return
+	}
+	certPEM, keyPEM := secret["certificate"+suffix], secret["private_key"+suffix]
+	if _, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM)); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Certificate Bundle",
+			fmt.Sprintf("The stored certificate%s and private_key%s do not form a usable key pair: %s", suffix, suffix, err),
+		)
+		return
+	}
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Certificate Bundle", err.Error())
+		return
+	}
+
+	data.DomainName = optionalString(secret["domain_name"])
+	data.CertificateArn = optionalString(secret["certificate_arn"+suffix])
+	data.CertificatePEM = tfTypes.StringValue(certPEM)
+	data.PrivateKeyPEM = tfTypes.StringValue(keyPEM)
+	data.CACertificatePEM = tfTypes.StringNull()
+	data.FullChainPEM = tfTypes.StringValue(certPEM)
+	if root := e.clients.originCARootPEM(cert, certPEM); root != "" {
+		data.CACertificatePEM = tfTypes.StringValue(root)
+		data.FullChainPEM = tfTypes.StringValue(strings.TrimRight(certPEM, "\n") + "\n" + root)
+	}
+	data.ExpiresAt = expiryFromPEM(certPEM)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// optionalString is value, or null when it is empty, for key store entries
+// that earlier versions of the provider did not write.
+func optionalString(value string) tfTypes.String {
+	if value == "" {
+		return tfTypes.StringNull()
+	}
+	return tfTypes.StringValue(value)
+}
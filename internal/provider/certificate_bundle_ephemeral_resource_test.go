@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// openCertificateBundle opens a cfcert_certificate_bundle reading vault_kv_path
+// secret/example from a Vault that holds secret.
+func openCertificateBundle(t *testing.T, clients *ProviderClients, secret map[string]string) CertificateBundleEphemeralResourceModel {
+	t.Helper()
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/example" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": secret}})
+	}))
+	t.Cleanup(vault.Close)
+	clients.VaultClient = &vaultClient{Address: vault.URL, Token: "test-token", httpClient: vault.Client()}
+
+	ctx := context.Background()
+	e := &CertificateBundleEphemeralResource{clients: clients}
+	var schemaResp ephemeral.SchemaResponse
+	e.Schema(ctx, ephemeral.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	config := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		config[name] = tftypes.NewValue(attributeType, nil)
+	}
+	config["vault_kv_path"] = tftypes.NewValue(tftypes.String, "secret/example")
+
+	req := ephemeral.OpenRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, config)}}
+	resp := &ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+	e.Open(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Open: %v", resp.Diagnostics)
+	}
+	var data CertificateBundleEphemeralResourceModel
+	if diags := resp.Result.Get(ctx, &data); diags.HasError() {
+		t.Fatalf("Result.Get: %v", diags)
+	}
+	return data
+}
+
+func TestCertificateBundleOpen(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	clients := newTestClients(mock)
+	cert, keyPEM, err := clients.issueWithLocalKey(context.Background(), []string{"example.com"})
+	if err != nil {
+		t.Fatalf("issueWithLocalKey: %v", err)
+	}
+	secret := map[string]string{
+		"domain_name":     "example.com",
+		"certificate_arn": "arn:aws:acm:us-east-1:123456789012:certificate/example",
+		"certificate":     cert.Certificate,
+		"private_key":     string(keyPEM),
+	}
+	rootPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: mock.Root().Raw}))
+
+	clients.OriginCARoots = x509.NewCertPool()
+	clients.OriginCARoots.AddCert(cloudflaretest.NewServer(t).Root())
+	clients.OriginCARoots.AddCert(mock.Root())
+	data := openCertificateBundle(t, clients, secret)
+	if got := data.CACertificatePEM.ValueString(); got != rootPEM {
+		t.Errorf("ca_certificate_pem = %q, want the issuing root", got)
+	}
+	var chain []*x509.Certificate
+	for rest := []byte(data.FullChainPEM.ValueString()); ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("full_chain_pem: %v", err)
+		}
+		chain = append(chain, c)
+	}
+	if len(chain) != 2 || !chain[0].Equal(mustParseCertificatePEM(t, cert.Certificate)) || !chain[1].Equal(mock.Root()) {
+		t.Errorf("full_chain_pem holds %d certificates, want the certificate followed by its root", len(chain))
+	}
+
+	clients.OriginCARoots = x509.NewCertPool()
+	clients.OriginCARoots.AddCert(cloudflaretest.NewServer(t).Root())
+	data = openCertificateBundle(t, clients, secret)
+	if !data.CACertificatePEM.IsNull() {
+		t.Errorf("ca_certificate_pem = %q without the issuing root, want null", data.CACertificatePEM.ValueString())
+	}
+	if got := data.FullChainPEM.ValueString(); got != cert.Certificate {
+		t.Error("full_chain_pem without the issuing root is not just the certificate")
+	}
+}
+
+func mustParseCertificatePEM(t *testing.T, certPEM string) *x509.Certificate {
+	t.Helper()
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
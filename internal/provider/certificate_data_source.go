@@ -9,20 +9,24 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/acm/types"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var _ datasource.DataSource = &CertificateDataSource{}
 var _ datasource.DataSourceWithConfigure = &CertificateDataSource{}
+var _ datasource.DataSourceWithConfigValidators = &CertificateDataSource{}
 
 type CertificateDataSource struct {
 	clients *ProviderClients
 }
 
 type CertificateDataSourceModel struct {
-	DomainName     tfTypes.String `tfsdk:"domain_name"`
-	CertificateArn tfTypes.String `tfsdk:"certificate_arn"`
-	ID             tfTypes.String `tfsdk:"id"`
+	DomainName       tfTypes.String `tfsdk:"domain_name"`
+	CertificateArn   tfTypes.String `tfsdk:"certificate_arn"`
+	PageSize         tfTypes.Int64  `tfsdk:"page_size"`
+	StopAtFirstMatch tfTypes.Bool   `tfsdk:"stop_at_first_match"`
+	ID               tfTypes.String `tfsdk:"id"`
 }
 
 func NewCertificateDataSource() datasource.DataSource {
@@ -35,15 +39,33 @@ func (d *CertificateDataSource) Metadata(ctx context.Context, req datasource.Met
 
 func (d *CertificateDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Look up an existing Cloudflare Origin Certificate in AWS ACM by domain name.",
+		Description: "Look up an existing Cloudflare Origin Certificate in AWS ACM by domain name or ARN.",
 		Attributes: map[string]schema.Attribute{
 			"domain_name": schema.StringAttribute{
-				Description: "The domain name to search for.",
-				Required:    true,
+				Description: "The domain name to search for. Conflicts with certificate_arn. When looking up by ARN, " +
+					"the certificate's domain name.",
+				Optional: true,
+				Computed: true,
 			},
 			"certificate_arn": schema.StringAttribute{
-				Description: "The ARN of the ACM certificate, if found.",
-				Computed:    true,
+				Description: "The ARN of the ACM certificate. When set, the certificate is described directly instead of " +
+					"searching the account's certificates. Conflicts with domain_name.",
+				Optional: true,
+				Computed: true,
+			},
+			"page_size": schema.Int64Attribute{
+				Description: fmt.Sprintf("How many certificates to request per ListCertificates call when searching by "+
+					"domain_name, from 1 to %d. Defaults to ACM's page size.", maxListCertificatesPageSize),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64Between(1, maxListCertificatesPageSize),
+				},
+			},
+			"stop_at_first_match": schema.BoolAttribute{
+				Description: "Page through the account's certificates, newest first, and stop at the first match instead " +
+					"of listing every certificate. The full listing is shared with every other lookup in the run, so this " +
+					"is faster for a single lookup in an account with many certificates, and slower for many. Defaults to false.",
+				Optional: true,
 			},
 			"id": schema.StringAttribute{
 				Description: "Data source identifier.",
@@ -53,6 +75,12 @@ func (d *CertificateDataSource) Schema(ctx context.Context, req datasource.Schem
 	}
 }
 
+func (d *CertificateDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		exactlyOneOf("domain_name", "certificate_arn"),
+	}
+}
+
 func (d *CertificateDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -75,19 +103,31 @@ func (d *CertificateDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
+	if !data.CertificateArn.IsNull() {
+		d.readByArn(ctx, data, resp)
+		return
+	}
+
 	domainName := data.DomainName.ValueString()
 
-	arn, err := d.findExistingCertificate(ctx, domainName)
+	var pageSize int32
+	if !data.PageSize.IsNull() {
+		pageSize = int32(data.PageSize.ValueInt64())
+	}
+	arn, err := d.findExistingCertificate(ctx, domainName, pageSize, data.StopAtFirstMatch.ValueBool())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to search for certificates", err.Error())
 		return
 	}
 
 	if arn == "" {
-		resp.Diagnostics.AddError(
-			"Certificate Not Found",
-			fmt.Sprintf("No issued EC_prime256v1 certificate found for domain: %s", domainName),
-		)
+		detail := fmt.Sprintf("No issued EC_prime256v1 certificate imported by this provider found for domain: %s", domainName)
+		if empty, err := d.clients.CertificateList.accountEmpty(ctx, d.clients.ACMClient); err == nil && empty {
+			detail = fmt.Sprintf("ACM has no certificates at all in %s for this account, so none was found for domain %s. "+
+				"Create a cfcert_origin_certificate for it first, or check the provider's region and credentials.",
+				d.clients.Region, domainName)
+		}
+		resp.Diagnostics.AddError("Certificate Not Found", detail)
 		return
 	}
 
@@ -97,25 +137,69 @@ func (d *CertificateDataSource) Read(ctx context.Context, req datasource.ReadReq
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (d *CertificateDataSource) findExistingCertificate(ctx context.Context, domainName string) (string, error) {
-	paginator := acm.NewListCertificatesPaginator(d.clients.ACMClient, &acm.ListCertificatesInput{
-		CertificateStatuses: []types.CertificateStatus{types.CertificateStatusIssued},
-		Includes: &types.Filters{
-			KeyTypes: []types.KeyAlgorithm{types.KeyAlgorithmEcPrime256v1},
-		},
-		SortBy:    types.SortByCreatedAt,
-		SortOrder: types.SortOrderDescending,
+// readByArn describes a certificate given by ARN, without listing the
+// account's certificates.
+func (d *CertificateDataSource) readByArn(ctx context.Context, data CertificateDataSourceModel, resp *datasource.ReadResponse) {
+	arn := data.CertificateArn.ValueString()
+	output, err := d.clients.ACMClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+		CertificateArn: aws.String(arn),
 	})
+	if isResourceNotFoundError(err) {
+		resp.Diagnostics.AddError("Certificate Not Found", fmt.Sprintf("No ACM certificate %s was found.", arn))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to describe certificate", err.Error())
+		return
+	}
 
-	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(ctx)
+	data.DomainName = tfTypes.StringValue(aws.ToString(output.Certificate.DomainName))
+	data.ID = tfTypes.StringValue(arn)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// findExistingCertificate returns the newest issued P-256 certificate for
+// domainName carrying the provider's ownership marker, or "" if there is none.
+// With stopAtFirstMatch, and no fresh shared listing to search, it pages
+// through the account itself and stops at the first match.
+func (d *CertificateDataSource) findExistingCertificate(ctx context.Context, domainName string, pageSize int32, stopAtFirstMatch bool) (string, error) {
+	domainName = normalizeHostname(domainName)
+	if stopAtFirstMatch && d.clients.CertificateList.cached() == nil {
+		paginator := newIssuedP256Paginator(d.clients.ACMClient, pageSize)
+		for first := true; paginator.HasMorePages(); first = false {
+			page, empty, err := d.clients.CertificateList.nextPage(ctx, d.clients.ACMClient, paginator, first)
+			if err != nil || empty {
+				return "", err
+			}
+			arn, err := d.firstOwned(ctx, page.CertificateSummaryList, domainName)
+			if arn != "" || err != nil {
+				return arn, err
+			}
+		}
+		return "", nil
+	}
+
+	summaries, err := d.clients.CertificateList.issuedP256WithPageSize(ctx, d.clients.ACMClient, pageSize)
+	if err != nil {
+		return "", err
+	}
+	return d.firstOwned(ctx, summaries, domainName)
+}
+
+// firstOwned returns the first of summaries for domainName that carries the
+// provider's ownership marker, or "".
+func (d *CertificateDataSource) firstOwned(ctx context.Context, summaries []types.CertificateSummary, domainName string) (string, error) {
+	for _, cert := range summaries {
+		if aws.ToString(cert.DomainName) != domainName {
+			continue
+		}
+		arn := aws.ToString(cert.CertificateArn)
+		owned, err := d.clients.hasOwnershipMarker(ctx, arn)
 		if err != nil {
 			return "", err
 		}
-		for _, cert := range page.CertificateSummaryList {
-			if aws.ToString(cert.DomainName) == domainName {
-				return aws.ToString(cert.CertificateArn), nil
-			}
+		if owned {
+			return arn, nil
 		}
 	}
 	return "", nil
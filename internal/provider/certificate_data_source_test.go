@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+func TestDataSourceStopAtFirstMatch(t *testing.T) {
+	fake := &fakeACM{pageSize: 100, tags: map[string]map[string]string{"arn:2": {ownershipMarkerTag: "true"}}}
+	for _, arn := range []string{"arn:1", "arn:2", "arn:3", "arn:4"} {
+		fake.summaries = append(fake.summaries, types.CertificateSummary{CertificateArn: aws.String(arn), DomainName: aws.String("example.com")})
+	}
+	d := &CertificateDataSource{clients: &ProviderClients{ACMClient: fake, CertificateList: newCertificateListCache()}}
+	ctx := context.Background()
+
+	arn, err := d.findExistingCertificate(ctx, "example.com", 1, true)
+	if err != nil || arn != "arn:2" {
+		t.Fatalf("findExistingCertificate = %q, %v, want arn:2", arn, err)
+	}
+	if fake.listCalls != 2 {
+		t.Errorf("made %d list calls, want 2 pages of 1 up to the match", fake.listCalls)
+	}
+
+	fake.listCalls = 0
+	if arn, err := d.findExistingCertificate(ctx, "example.com", 3, false); err != nil || arn != "arn:2" {
+		t.Fatalf("findExistingCertificate = %q, %v, want arn:2", arn, err)
+	}
+	if fake.listCalls != 2 {
+		t.Errorf("full scan made %d list calls, want 2 pages of 3", fake.listCalls)
+	}
+}
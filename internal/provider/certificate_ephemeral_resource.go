@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ ephemeral.EphemeralResource = &CertificateEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &CertificateEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigValidators = &CertificateEphemeralResource{}
+
+// CertificateEphemeralResource hands out certificate material for the
+// duration of a Terraform run without persisting it to plan or state.
+type CertificateEphemeralResource struct {
+	clients *ProviderClients
+}
+
+type CertificateEphemeralResourceModel struct {
+	DomainName             tfTypes.String `tfsdk:"domain_name"`
+	VaultKVPath            tfTypes.String `tfsdk:"vault_kv_path"`
+	SecretsManagerSecretID tfTypes.String `tfsdk:"secrets_manager_secret_id"`
+	SSMParameterName       tfTypes.String `tfsdk:"ssm_parameter_name"`
+	S3ObjectURI            tfTypes.String `tfsdk:"s3_object_uri"`
+	CertificatePEM         tfTypes.String `tfsdk:"certificate_pem"`
+	PrivateKeyPEM          tfTypes.String `tfsdk:"private_key_pem"`
+	ExpiresAt              tfTypes.String `tfsdk:"expires_at"`
+	KeystorePassword       tfTypes.String `tfsdk:"keystore_password"`
+	KeystoreAlias          tfTypes.String `tfsdk:"keystore_alias"`
+	KeystoreJKSBase64      tfTypes.String `tfsdk:"keystore_jks_base64"`
+}
+
+// defaultKeystoreAlias names the key entry in keystore_jks_base64 when
+// keystore_alias is not set.
+const defaultKeystoreAlias = "origin"
+
+func NewCertificateEphemeralResource() ephemeral.EphemeralResource {
+	return &CertificateEphemeralResource{}
+}
+
+func (e *CertificateEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_origin_certificate"
+}
+
+func (e *CertificateEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Issues a new Cloudflare Origin Certificate, or fetches one stored in a key store by cfcert_origin_certificate, " +
+			"for use during a single Terraform run. The certificate and private key are never written to plan or state.",
+		Attributes: map[string]schema.Attribute{
+			"domain_name": schema.StringAttribute{
+				Description: "Issue a new certificate for this domain name. Conflicts with the key store attributes.",
+				Optional:    true,
+				Validators: []validator.String{
+					validHostname(),
+				},
+			},
+			"vault_kv_path": schema.StringAttribute{
+				Description: "Fetch the certificate and key stored at this Vault KV version 2 path, as \"<mount>/<path>\", " +
+					"instead of issuing one. Conflicts with domain_name.",
+				Optional: true,
+			},
+			"secrets_manager_secret_id": schema.StringAttribute{
+				Description: "Fetch the certificate and key stored in this Secrets Manager secret instead of issuing one. " +
+					"Conflicts with domain_name.",
+				Optional: true,
+			},
+			"ssm_parameter_name": schema.StringAttribute{
+				Description: "Fetch the certificate and key stored in this SSM Parameter Store parameter instead of issuing one. " +
+					"Conflicts with domain_name.",
+				Optional: true,
+			},
+			"s3_object_uri": schema.StringAttribute{
+				Description: "Fetch the certificate and key stored in this s3://bucket/key object instead of issuing one. " +
+					"Conflicts with domain_name.",
+				Optional: true,
+			},
+			"certificate_pem": schema.StringAttribute{
+				Description: "The certificate in PEM format.",
+				Computed:    true,
+			},
+			"private_key_pem": schema.StringAttribute{
+				Description: "The private key in PEM format.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"expires_at": schema.StringAttribute{
+				Description: "When the certificate expires (RFC 3339).",
+				Computed:    true,
+			},
+			"keystore_password": schema.StringAttribute{
+				Description: "Also return the certificate and key as a JKS keystore, protected with this password, in keystore_jks_base64.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"keystore_alias": schema.StringAttribute{
+				Description: "The alias of the key entry in keystore_jks_base64. Defaults to \"" + defaultKeystoreAlias + "\".",
+				Optional:    true,
+			},
+			"keystore_jks_base64": schema.StringAttribute{
+				Description: "A base64 encoded JKS keystore holding the private key and every certificate in certificate_pem as " +
+					"its chain, for JVM services that can only load JKS. The keystore and key entry share keystore_password. " +
+					"Null when keystore_password is not set.",
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func (e *CertificateEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*ProviderClients)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderClients, got: %T", req.ProviderData),
+		)
+		return
+	}
+	e.clients = clients
+}
+
+func (e *CertificateEphemeralResource) ConfigValidators(ctx context.Context) []ephemeral.ConfigValidator {
+	return []ephemeral.ConfigValidator{
+		exactlyOneOf(append([]string{"domain_name"}, keyStoreAttributes()...)...),
+	}
+}
+
+func (e *CertificateEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data CertificateEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if keyStore, _ := e.clients.keyStore(data.keyStoreLocations()); keyStore != nil {
+		secret := e.clients.readStoredCertificate(ctx, data.keyStoreLocations(), "", &resp.Diagnostics)
+		if secret == nil {
+			return
+		}
+		data.CertificatePEM = tfTypes.StringValue(secret["certificate"])
+		data.PrivateKeyPEM = tfTypes.StringValue(secret["private_key"])
+	} else {
+		ctx = withDomainLogField(ctx, data.DomainName.ValueString())
+		cert, keyPEM, err := e.clients.issueWithLocalKey(ctx, []string{data.DomainName.ValueString()})
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to issue certificate", err.Error())
+			return
+		}
+		data.CertificatePEM = tfTypes.StringValue(cert.Certificate)
+		data.PrivateKeyPEM = tfTypes.StringValue(string(keyPEM))
+		zeroize(keyPEM)
+	}
+	data.ExpiresAt = expiryFromPEM(data.CertificatePEM.ValueString())
+	data.KeystoreJKSBase64 = tfTypes.StringNull()
+	if !data.KeystorePassword.IsNull() {
+		alias := defaultKeystoreAlias
+		if !data.KeystoreAlias.IsNull() {
+			alias = data.KeystoreAlias.ValueString()
+		}
+		keyPEM := []byte(data.PrivateKeyPEM.ValueString())
+		keystore, err := encodeJKS(alias, data.KeystorePassword.ValueString(), data.CertificatePEM.ValueString(), keyPEM, time.Now())
+		zeroize(keyPEM)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to build JKS keystore", err.Error())
+			return
+		}
+		data.KeystoreJKSBase64 = tfTypes.StringValue(base64.StdEncoding.EncodeToString(keystore))
+		zeroize(keystore)
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
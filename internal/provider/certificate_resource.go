@@ -1,42 +1,118 @@
 package provider
 
 import (
-	"bytes"
 	"context"
-	"crypto/ecdsa"
-	"crypto/elliptic"
-	"crypto/rand"
-	"crypto/x509"
-	"crypto/x509/pkix"
-	"encoding/json"
-	"encoding/pem"
+	"crypto"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"maps"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsarn "github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
 	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
 
 var _ resource.Resource = &CertificateResource{}
 var _ resource.ResourceWithConfigure = &CertificateResource{}
+var _ resource.ResourceWithValidateConfig = &CertificateResource{}
+var _ resource.ResourceWithConfigValidators = &CertificateResource{}
+var _ resource.ResourceWithModifyPlan = &CertificateResource{}
+var _ resource.ResourceWithImportState = &CertificateResource{}
+
+// defaultDeleteWaitForUnused is how long delete retries a certificate that
+// is still in use when delete_wait_for_unused is not set.
+const defaultDeleteWaitForUnused = "5m"
+
+// Adoption strategies choose between several existing certificates that
+// could be adopted for the same domain.
+const (
+	adoptionStrategyNewest = "newest"
+	adoptionStrategyOldest = "oldest"
+	adoptionStrategyError  = "error"
+)
+
+// Values of origin, recording where a certificate came from.
+const (
+	originIssued   = "issued"
+	originAdopted  = "adopted"
+	originImported = "imported"
+)
+
+const (
+	keyBackendACM    = "acm"
+	keyBackendKMS    = "kms"
+	keyBackendPKCS11 = "pkcs11"
+)
 
 type CertificateResource struct {
 	clients *ProviderClients
 }
 
 type CertificateResourceModel struct {
-	DomainName     tfTypes.String `tfsdk:"domain_name"`
-	CertificateArn tfTypes.String `tfsdk:"certificate_arn"`
-	ID             tfTypes.String `tfsdk:"id"`
+	DomainName              tfTypes.String `tfsdk:"domain_name"`
+	Hostnames               tfTypes.List   `tfsdk:"hostnames"`
+	KeyBackend              tfTypes.String `tfsdk:"key_backend"`
+	CertificateArn          tfTypes.String `tfsdk:"certificate_arn"`
+	CertificateArns         tfTypes.List   `tfsdk:"certificate_arns"`
+	ReplacedCertificateArns tfTypes.List   `tfsdk:"replaced_certificate_arns"`
+	CertificatePEM          tfTypes.String `tfsdk:"certificate_pem"`
+	MetadataJSON            tfTypes.String `tfsdk:"metadata_json"`
+	PublicKeyPEM            tfTypes.String `tfsdk:"public_key_pem"`
+	CertificateDERBase64    tfTypes.String `tfsdk:"certificate_der_base64"`
+	KeyFingerprintSHA256    tfTypes.String `tfsdk:"key_fingerprint_sha256"`
+	SerialNumber            tfTypes.String `tfsdk:"serial_number"`
+	KeyAlgorithm            tfTypes.String `tfsdk:"key_algorithm"`
+	ReplaceOnDrift          tfTypes.Bool   `tfsdk:"replace_on_drift"`
+	ReimportIfDeleted       tfTypes.Bool   `tfsdk:"reimport_if_deleted"`
+	CheckRevocation         tfTypes.Bool   `tfsdk:"check_revocation"`
+	RenewalTags             tfTypes.Bool   `tfsdk:"renewal_tags"`
+	VerifyZone              tfTypes.Bool   `tfsdk:"verify_zone"`
+	CheckDNSRecords         tfTypes.Bool   `tfsdk:"check_dns_records"`
+	NotificationTopicArn    tfTypes.String `tfsdk:"notification_topic_arn"`
+	CertificateStatus       tfTypes.String `tfsdk:"certificate_status"`
+	Origin                  tfTypes.String `tfsdk:"origin"`
+	CloudflareStatus        tfTypes.String `tfsdk:"cloudflare_status"`
+	KMSKeyArn               tfTypes.String `tfsdk:"kms_key_arn"`
+	PKCS11KeyID             tfTypes.String `tfsdk:"pkcs11_key_id"`
+	VaultKVPath             tfTypes.String `tfsdk:"vault_kv_path"`
+	SecretsManagerSecretID  tfTypes.String `tfsdk:"secrets_manager_secret_id"`
+	SSMParameterName        tfTypes.String `tfsdk:"ssm_parameter_name"`
+	S3ObjectURI             tfTypes.String `tfsdk:"s3_object_uri"`
+	RotationPolicy          tfTypes.Object `tfsdk:"rotation_policy"`
+	Tags                    tfTypes.Map    `tfsdk:"tags"`
+	TagsAll                 tfTypes.Map    `tfsdk:"tags_all"`
+	DeleteWaitForUnused     tfTypes.String `tfsdk:"delete_wait_for_unused"`
+	WaitForInUse            tfTypes.String `tfsdk:"wait_for_in_use"`
+	AdoptionStrategy        tfTypes.String `tfsdk:"adoption_strategy"`
+	Alias                   tfTypes.String `tfsdk:"alias"`
+	AccountID               tfTypes.String `tfsdk:"account_id"`
+	AssumeRole              tfTypes.Object `tfsdk:"assume_role"`
+	Retry                   tfTypes.Object `tfsdk:"retry"`
+	ExpiresAt               tfTypes.String `tfsdk:"expires_at"`
+	ImportedAt              tfTypes.String `tfsdk:"imported_at"`
+	CreatedAt               tfTypes.String `tfsdk:"created_at"`
+	ACMType                 tfTypes.String `tfsdk:"acm_type"`
+	RenewalEligibility      tfTypes.String `tfsdk:"renewal_eligibility"`
+	ID                      tfTypes.String `tfsdk:"id"`
 }
 
 func NewCertificateResource() resource.Resource {
@@ -54,17 +130,404 @@ func (r *CertificateResource) Schema(ctx context.Context, req resource.SchemaReq
 			"domain_name": schema.StringAttribute{
 				Description: "The domain name for the certificate.",
 				Required:    true,
+				Validators: []validator.String{
+					validHostname(),
+				},
+				PlanModifiers: []planmodifier.String{
+					equivalentHostname(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"hostnames": schema.ListAttribute{
+				Description: fmt.Sprintf("Additional hostnames to cover alongside domain_name. Cloudflare accepts at most %d hostnames "+
+					"per certificate; longer lists are split across several ACM certificates, listed in certificate_arns. "+
+					"Splitting is only supported with key_backend \"acm\". An existing ACM certificate is never reused when set.", cloudflare.MaxHostnames),
+				ElementType: tfTypes.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					validHostname(),
+				},
+				PlanModifiers: []planmodifier.List{
+					equivalentHostnames(),
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"key_backend": schema.StringAttribute{
+				Description: "Where the private key is held: \"acm\" generates the key in the provider and imports the certificate into ACM, " +
+					"\"kms\" creates an asymmetric KMS key and signs the CSR with kms:Sign so the key never leaves KMS, " +
+					"\"pkcs11\" generates the key on the provider's PKCS#11 token (e.g. CloudHSM). " +
+					"Certificates backed by KMS or PKCS#11 are not imported into ACM. Defaults to \"acm\".",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(keyBackendACM),
+				Validators: []validator.String{
+					stringOneOf(keyBackendACM, keyBackendKMS, keyBackendPKCS11),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"vault_kv_path": schema.StringAttribute{
+				Description: "Optional Vault KV version 2 path, as \"<mount>/<path>\", to which the issued certificate and private key are written. " +
+					"Requires key_backend \"acm\" and the provider's Vault settings. An existing ACM certificate is never reused when set, " +
+					"since its private key is not available. At most one of vault_kv_path, secrets_manager_secret_id, " +
+					"ssm_parameter_name and s3_object_uri can be set.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"secrets_manager_secret_id": schema.StringAttribute{
+				Description: "Optional name or ARN of a Secrets Manager secret to which the issued certificate and private key are " +
+					"written as JSON, with the same keys as vault_kv_path. The secret is created if it does not exist, and deleted " +
+					"without recovery on destroy. Requires key_backend \"acm\"; like vault_kv_path, disables adoption.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ssm_parameter_name": schema.StringAttribute{
+				Description: "Optional name of an SSM Parameter Store SecureString parameter to which the issued certificate and " +
+					"private key are written as JSON, with the same keys as vault_kv_path. Requires key_backend \"acm\"; like " +
+					"vault_kv_path, disables adoption.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"s3_object_uri": schema.StringAttribute{
+				Description: "Optional s3://bucket/key URI of an object to which the issued certificate and private key are " +
+					"written as JSON, with the same keys as vault_kv_path, using the bucket's default encryption. Requires " +
+					"key_backend \"acm\"; like vault_kv_path, disables adoption.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"rotation_policy": schema.SingleNestedAttribute{
+				Description: "Renewal settings, usually the policy attribute of a cfcert_rotation_policy. When the certificate " +
+					"is within the renewal window, the next plan renews it in place, re-importing into the same ACM ARN.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Description: "The policy name.",
+						Optional:    true,
+					},
+					"renew_before_days": schema.Int64Attribute{
+						Description: "Renew this many days before expiry. Defaults to 30.",
+						Optional:    true,
+					},
+					"renew_before_percent": schema.Int64Attribute{
+						Description: "Also renew once this percentage of the certificate's lifetime remains.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64Between(1, 99),
+						},
+					},
+					"rotate_key_on_renew": schema.BoolAttribute{
+						Description: "Generate a new key pair when renewing KMS or PKCS#11 backed certificates. Defaults to true.",
+						Optional:    true,
+					},
+					"notification_targets": schema.ListAttribute{
+						Description: "SNS topic ARNs notified on renewal.",
+						ElementType: tfTypes.StringType,
+						Optional:    true,
+					},
+				},
+			},
+			"tags": schema.MapAttribute{
+				Description: "Tags to apply to the ACM certificate. Requires key_backend \"acm\". Changes are applied in place.",
+				ElementType: tfTypes.StringType,
+				Optional:    true,
+			},
+			"tags_all": schema.MapAttribute{
+				Description: "All tags on the ACM certificate, including the provider's default_tags.",
+				ElementType: tfTypes.StringType,
+				Computed:    true,
+			},
+			"key_algorithm": schema.StringAttribute{
+				Description: "The certificate's key algorithm as ACM names it, such as \"" + issuedKeyAlgorithm + "\". " +
+					"Certificates this provider issues always use " + issuedKeyAlgorithm + "; an adopted or moved certificate " +
+					"with any other key is planned for replacement.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"replace_on_drift": schema.BoolAttribute{
+				Description: "Plan a replacement when the ACM certificate no longer has the serial number this resource issued, " +
+					"because it was re-imported or rotated outside Terraform. Without it, drift is only reported as a warning.",
+				Optional: true,
+			},
+			"reimport_if_deleted": schema.BoolAttribute{
+				Description: "When the ACM certificate is deleted outside Terraform, import the certificate and key stored at " +
+					"vault_kv_path, or whichever key store is set, again on the next apply instead of dropping the resource from state " +
+					"and issuing a new one. Skipped once the certificate has expired or been revoked. Requires a key store.",
+				Optional: true,
+			},
+			"check_revocation": schema.BoolAttribute{
+				Description: "Check the certificate against the CRLs it names on every refresh and warn if it has been revoked, " +
+					"which ACM does not detect. Defaults to false.",
+				Optional: true,
+			},
+			"renewal_tags": schema.BoolAttribute{
+				Description: "Maintain cfcert:expires-at and cfcert:renew-after tags, as RFC 3339 times, on the imported " +
+					"certificates, so schedulers can act on them without querying Cloudflare. renew-after is when the " +
+					"rotation_policy's renewal window opens, or 30 days before expiry without one. Requires key_backend " +
+					"\"acm\". Defaults to false.",
+				Optional: true,
+			},
+			"verify_zone": schema.BoolAttribute{
+				Description: "Before issuing, check that every hostname belongs to a zone the Cloudflare API token can access, " +
+					"so a typo fails with the hostname named instead of an Origin CA validation error. Requires cloudflare_api_token " +
+					"with Zone Read permission. Defaults to false.",
+				Optional: true,
+			},
+			"check_dns_records": schema.BoolAttribute{
+				Description: "Before issuing, warn about hostnames with no DNS record in Cloudflare, or whose records are not proxied, " +
+					"since origin certificates are only trusted by Cloudflare's proxy. Requires cloudflare_api_token with Zone Read " +
+					"and DNS Read permissions. Defaults to false.",
+				Optional: true,
+			},
+			"notification_topic_arn": schema.StringAttribute{
+				Description: "SNS topic ARN that receives a message whenever the certificate is issued, adopted, renewed or deleted. " +
+					"The message is JSON with the action, domain name, certificate ARNs and expiry.",
+				Optional: true,
+			},
+			"delete_wait_for_unused": schema.StringAttribute{
+				Description: "How long delete waits for ACM to report the certificate unused, for example after a load balancer " +
+					"listener or CloudFront distribution moves to a replacement, as a Go duration. Defaults to \"" + defaultDeleteWaitForUnused + "\".",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(defaultDeleteWaitForUnused),
+			},
+			"wait_for_in_use": schema.StringAttribute{
+				Description: "After creating the resource, wait up to this long, as a Go duration, for ACM to report every certificate " +
+					"in use by at least one AWS resource, and fail if it is not. Only useful when the certificate is attached " +
+					"outside this configuration, since resources that reference it cannot be created until this one is.",
+				Optional: true,
+			},
+			"alias": schema.StringAttribute{
+				Description: "Name for this certificate, such as \"blue\" or \"staging-clone\", recorded in the " +
+					"cfcert:alias tag. A resource only adopts certificates imported with the same alias, or with none when " +
+					"unset, so several resources can keep separate certificates for the same domain. Changing it replaces " +
+					"the certificate.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"adoption_strategy": schema.StringAttribute{
+				Description: "Which certificate to adopt when several existing ACM certificates match domain_name: " +
+					"\"newest\", \"oldest\", or \"error\" to fail instead of choosing. Every candidate is listed in a diagnostic " +
+					"whenever more than one matches. Defaults to \"" + adoptionStrategyNewest + "\".",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(adoptionStrategyNewest),
+				Validators: []validator.String{
+					stringOneOf(adoptionStrategyNewest, adoptionStrategyOldest, adoptionStrategyError),
+				},
+			},
+			"assume_role": schema.SingleNestedAttribute{
+				Description: "Import the certificate into another AWS account by assuming this role with the provider's " +
+					"credentials, instead of configuring a provider alias per account. Requires key_backend \"acm\". " +
+					"Changing it replaces the certificate.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"role_arn": schema.StringAttribute{
+						Description: "ARN of the role to assume.",
+						Required:    true,
+					},
+					"session_name": schema.StringAttribute{
+						Description: "Session name for the assumed role. Defaults to \"" + defaultAssumeRoleSessionName + "\".",
+						Optional:    true,
+					},
+					"external_id": schema.StringAttribute{
+						Description: "External ID the role's trust policy requires, if any.",
+						Optional:    true,
+					},
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+			},
+			"account_id": schema.StringAttribute{
+				Description: "Cloudflare account ID to look up this certificate's zones in, overriding the provider's " +
+					"account_id, for API tokens that can access several accounts. Only used by verify_zone and check_dns_records.",
+				Optional: true,
+			},
+			"retry": schema.SingleNestedAttribute{
+				Description: "Override the provider's retry settings for this resource's Cloudflare and ACM calls, for " +
+					"example to retry longer in an account that is often throttled. Unset values keep the defaults: " +
+					fmt.Sprintf("%d Cloudflare attempts and %d ACM attempts.", cloudflare.DefaultMaxAttempts, acmMaxAttempts),
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						Description: "How many times a throttled or failed request is sent before giving up.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64Between(1, 25),
+						},
+					},
+					"max_backoff": schema.StringAttribute{
+						Description: "The longest to wait between attempts, as a Go duration.",
+						Optional:    true,
+					},
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				Description: "When the certificate expires (RFC 3339).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"imported_at": schema.StringAttribute{
+				Description: "When the certificate was last imported into ACM (RFC 3339). Null for other key backends.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Description: "When the ACM certificate was created (RFC 3339); unlike imported_at, renewals do not change it. Null for other key backends.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"acm_type": schema.StringAttribute{
+				Description: "The certificate's type as ACM reports it, such as \"IMPORTED\". Null for other key backends.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"renewal_eligibility": schema.StringAttribute{
+				Description: "Whether ACM can renew the certificate itself, as ACM reports it. Imported certificates are always \"INELIGIBLE\", since this provider renews them. Null for other key backends.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"certificate_arn": schema.StringAttribute{
-				Description: "The ARN of the ACM certificate.",
+				Description: "The ARN of the ACM certificate. Null unless key_backend is \"acm\".",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"certificate_arns": schema.ListAttribute{
+				Description: "The ARNs of every ACM certificate issued for domain_name and hostnames, starting with certificate_arn. " +
+					"Null unless key_backend is \"acm\".",
+				ElementType: tfTypes.StringType,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"replaced_certificate_arns": schema.ListAttribute{
+				Description: "When this resource was created to replace another instance, the ARNs that instance held. They are " +
+					"never adopted, so with create_before_destroy the replacement gets its own certificate before the old one is deleted. " +
+					"Null otherwise.",
+				ElementType: tfTypes.StringType,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"certificate_pem": schema.StringAttribute{
+				Description: "The issued certificate in PEM format, for the first certificate when hostnames were split. " +
+					"Null when an existing ACM certificate was reused.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"metadata_json": schema.StringAttribute{
+				Description: "The serial number, subject, issuer, SANs, validity and fingerprints of certificate_pem as a JSON object. " +
+					"Null when certificate_pem is null.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"public_key_pem": schema.StringAttribute{
+				Description: "The public key of certificate_pem, as a PEM encoded SubjectPublicKeyInfo. Null when certificate_pem is null.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"certificate_der_base64": schema.StringAttribute{
+				Description: "The first certificate in certificate_pem, DER encoded and then base64 encoded, for consumers that " +
+					"need DER rather than PEM. Null when certificate_pem is null.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key_fingerprint_sha256": schema.StringAttribute{
+				Description: "The SHA-256 of public_key_pem's DER encoding, in lowercase hex, for pinning the key without exporting it. " +
+					"Null when certificate_pem is null.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"serial_number": schema.StringAttribute{
+				Description: "The serial number of the certificate (the first certificate when hostnames were split), in lowercase hex. " +
+					"Refreshed from ACM, so a certificate re-imported outside Terraform shows up as a change.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"certificate_status": schema.StringAttribute{
+				Description: "The ACM status of the certificate, such as ISSUED, EXPIRED or REVOKED. When hostnames were split, " +
+					"the status of the first certificate that is not ISSUED. Null unless key_backend is \"acm\".",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"origin": schema.StringAttribute{
+				Description: "Where the certificate came from: \"" + originIssued + "\" when this resource issued it and generated its key, " +
+					"\"" + originAdopted + "\" when an existing ACM certificate was adopted, or \"" + originImported + "\" when it was " +
+					"brought in with terraform import or moved from aws_acm_certificate. Renewal makes it \"" + originIssued + "\".",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cloudflare_status": schema.StringAttribute{
+				Description: "\"active\", or \"revoked\" once any certificate issued for this resource has been revoked in Cloudflare. " +
+					"Null when an existing ACM certificate was reused.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"kms_key_arn": schema.StringAttribute{
+				Description: "The ARN of the KMS key holding the private key when key_backend is \"kms\".",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"pkcs11_key_id": schema.StringAttribute{
+				Description: "The hex CKA_ID of the key pair on the PKCS#11 token when key_backend is \"pkcs11\".",
 				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"id": schema.StringAttribute{
-				Description: "Resource identifier (same as certificate_arn).",
+				Description: "Resource identifier (certificate_arn, kms_key_arn, or \"pkcs11:<pkcs11_key_id>\" depending on key_backend).",
 				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 		},
 	}
@@ -85,73 +548,606 @@ func (r *CertificateResource) Configure(ctx context.Context, req resource.Config
 	r.clients = clients
 }
 
+func (r *CertificateResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		atMostOneOf(keyStoreAttributes()...),
+		keyBackendRequired("vault_kv_path", "KMS and PKCS#11 keys cannot be exported.", keyBackendACM),
+		keyBackendRequired("secrets_manager_secret_id", "KMS and PKCS#11 keys cannot be exported.", keyBackendACM),
+		keyBackendRequired("ssm_parameter_name", "KMS and PKCS#11 keys cannot be exported.", keyBackendACM),
+		keyBackendRequired("s3_object_uri", "KMS and PKCS#11 keys cannot be exported.", keyBackendACM),
+		keyBackendRequired("tags", "only ACM certificates can be tagged.", keyBackendACM),
+		keyBackendRequired("assume_role", "only ACM certificates are imported into an AWS account.", keyBackendACM),
+		keyBackendRequired("renewal_tags", "only ACM certificates carry tags.", keyBackendACM),
+		keyBackendRequired("wait_for_in_use", "only ACM certificates are attached to AWS resources.", keyBackendACM),
+	}
+}
+
+func (r *CertificateResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data CertificateResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.DeleteWaitForUnused.IsNull() && !data.DeleteWaitForUnused.IsUnknown() {
+		if _, err := time.ParseDuration(data.DeleteWaitForUnused.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("delete_wait_for_unused"), "Invalid Duration", err.Error())
+		}
+	}
+	if !data.WaitForInUse.IsNull() && !data.WaitForInUse.IsUnknown() {
+		if _, err := time.ParseDuration(data.WaitForInUse.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("wait_for_in_use"), "Invalid Duration", err.Error())
+		}
+	}
+
+	if !data.Retry.IsNull() && !data.Retry.IsUnknown() {
+		var retrySettings RetryModel
+		resp.Diagnostics.Append(data.Retry.As(ctx, &retrySettings, basetypes.ObjectAsOptions{})...)
+		if !retrySettings.MaxBackoff.IsNull() && !retrySettings.MaxBackoff.IsUnknown() {
+			if _, err := time.ParseDuration(retrySettings.MaxBackoff.ValueString()); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("retry").AtName("max_backoff"), "Invalid Duration", err.Error())
+			}
+		}
+	}
+
+	if data.ReimportIfDeleted.ValueBool() && !slices.ContainsFunc(keyStoreAttributes(), func(attribute string) bool {
+		return !data.keyStoreLocations()[attribute].IsNull()
+	}) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("reimport_if_deleted"),
+			"Missing Key Store",
+			fmt.Sprintf("reimport_if_deleted re-imports the stored certificate and key, so one of %s must be set.",
+				strings.Join(keyStoreAttributes(), ", ")),
+		)
+	}
+
+	if !data.S3ObjectURI.IsNull() && !data.S3ObjectURI.IsUnknown() {
+		if _, _, err := parseS3ObjectURI(data.S3ObjectURI.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("s3_object_uri"), "Invalid S3 Object URI", err.Error())
+		}
+	}
+
+	if alias := data.Alias.ValueString(); !data.Alias.IsNull() && (alias == "" || len(alias) > maxACMTagValueLength) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("alias"),
+			"Invalid Alias",
+			fmt.Sprintf("alias must be between 1 and %d characters long, as it is stored in an ACM tag.", maxACMTagValueLength),
+		)
+	}
+
+	for key := range data.Tags.Elements() {
+		if isOwnershipTag(key) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("tags"),
+				"Reserved Tag Key",
+				fmt.Sprintf("Tag keys starting with %q are managed by the provider.", ownershipTagPrefix),
+			)
+		}
+	}
+
+	if data.Hostnames.IsUnknown() || data.KeyBackend.IsUnknown() || data.DomainName.IsUnknown() {
+		return
+	}
+	for _, hostname := range data.Hostnames.Elements() {
+		if hostname.IsUnknown() {
+			return
+		}
+	}
+	chunks, diags := r.hostnameChunks(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if len(chunks) > 1 && !data.KeyBackend.IsNull() && data.KeyBackend.ValueString() != keyBackendACM {
+		overflow := slices.Concat(chunks[1:]...)
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hostnames"),
+			"Too Many Hostnames",
+			fmt.Sprintf("Cloudflare accepts at most %d hostnames per certificate, including domain_name, and %d were given. "+
+				"Only key_backend \"acm\" can split hostnames across several certificates. These hostnames do not fit: %s",
+				cloudflare.MaxHostnames, cloudflare.MaxHostnames+len(overflow), strings.Join(overflow, ", ")),
+		)
+	}
+}
+
+// hostnameChunks groups domain_name and hostnames into the certificates
+// needed to cover them.
+func (r *CertificateResource) hostnameChunks(ctx context.Context, data CertificateResourceModel) ([][]string, diag.Diagnostics) {
+	var hostnames []string
+	diags := data.Hostnames.ElementsAs(ctx, &hostnames, false)
+	return hostnameChunks(data.DomainName.ValueString(), hostnames), diags
+}
+
 func (r *CertificateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data CertificateResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	r = r.withAssumeRole(ctx, data.AssumeRole, &resp.Diagnostics)
+	r = r.withCloudflareAccount(data.AccountID)
+	r = r.withRetry(ctx, data.Retry, &resp.Diagnostics)
 
 	domainName := data.DomainName.ValueString()
+	ctx = withDomainLogField(ctx, domainName)
+	data.CertificateArns = tfTypes.ListNull(tfTypes.StringType)
+	if data.ReplacedCertificateArns.IsUnknown() {
+		data.ReplacedCertificateArns = tfTypes.ListNull(tfTypes.StringType)
+	}
+	data.TagsAll = tfTypes.MapNull(tfTypes.StringType)
+	data.KeyAlgorithm = tfTypes.StringValue(issuedKeyAlgorithm)
+	data.CertificatePEM = tfTypes.StringNull()
+	data.MetadataJSON = tfTypes.StringNull()
+	data.PublicKeyPEM = tfTypes.StringNull()
+	data.CertificateDERBase64 = tfTypes.StringNull()
+	data.KeyFingerprintSHA256 = tfTypes.StringNull()
+	data.SerialNumber = tfTypes.StringNull()
+	data.CertificateStatus = tfTypes.StringNull()
+	data.Origin = tfTypes.StringValue(originIssued)
+	data.CloudflareStatus = tfTypes.StringNull()
+	data.KMSKeyArn = tfTypes.StringNull()
+	data.PKCS11KeyID = tfTypes.StringNull()
+	data.ExpiresAt = tfTypes.StringNull()
+	setACMDetail(&data, nil)
 
-	existingArn, err := r.findExistingCertificate(ctx, domainName)
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to check existing certificates", err.Error())
+	if data.VerifyZone.ValueBool() || data.CheckDNSRecords.ValueBool() {
+		chunks, diags := r.hostnameChunks(ctx, data)
+		resp.Diagnostics.Append(diags...)
+		if data.VerifyZone.ValueBool() {
+			r.clients.verifyZones(ctx, slices.Concat(chunks...), &resp.Diagnostics)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if data.CheckDNSRecords.ValueBool() {
+			r.clients.checkDNSRecords(ctx, slices.Concat(chunks...), &resp.Diagnostics)
+		}
+	}
+
+	action := lifecycleIssued
+	defer func() {
+		if !resp.Diagnostics.HasError() {
+			r.publishLifecycleEvent(ctx, action, data, resp.Private, &resp.Diagnostics)
+		}
+	}()
+
+	switch data.KeyBackend.ValueString() {
+	case keyBackendKMS:
+		r.createKMSBacked(ctx, &data, resp)
+		return
+	case keyBackendPKCS11:
+		r.createPKCS11Backed(ctx, &data, resp)
+		return
+	}
+
+	keyStore, keyStoreLocation := r.clients.keyStore(data.keyStoreLocations())
+	if keyStore != nil {
+		if err := keyStore.Check(); err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Missing %s Configuration", keyStore.Name()), err.Error())
+			return
+		}
+	}
+
+	chunks, diags := r.hostnameChunks(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	data.TagsAll, diags = r.tagsAll(ctx, data.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	tags := map[string]string{}
+	resp.Diagnostics.Append(data.TagsAll.ElementsAs(ctx, &tags, false)...)
+
+	existingArn := ""
+	var issuance *domainIssuance
+	if keyStore == nil && data.Hostnames.IsNull() && r.clients.Features.Adoption {
+		// Held until the certificate is imported, so another resource for
+		// the same domain and alias in this run adopts it instead of
+		// issuing its own.
+		issuance = r.clients.Issuances.lock(domainName, data.Alias.ValueString())
+		defer issuance.Unlock()
+		existingArn = issuance.arn
+	}
+	if issuance != nil && existingArn == "" {
+		candidates, err := r.findExistingCertificates(ctx, domainName, data.Alias.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to check existing certificates", err.Error())
+			return
+		}
+		// The instance being replaced still holds its certificates under
+		// create_before_destroy, and deletes them once this one exists.
+		var replaced []string
+		resp.Diagnostics.Append(data.ReplacedCertificateArns.ElementsAs(ctx, &replaced, false)...)
+		candidates = slices.DeleteFunc(candidates, func(arn string) bool { return slices.Contains(replaced, arn) })
+		existingArn, diags = adoptionCandidate(domainName, data.AdoptionStrategy.ValueString(), candidates)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	if existingArn != "" {
+		describeOutput, err := r.clients.ACMClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+			CertificateArn: aws.String(existingArn),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to describe existing certificate", err.Error())
+			return
+		}
 		data.CertificateArn = tfTypes.StringValue(existingArn)
+		data.CertificateArns = certificateArnList([]string{existingArn})
+		data.ExpiresAt = expiryFromDetail(describeOutput.Certificate)
+		data.SerialNumber = serialFromDetail(describeOutput.Certificate)
+		data.KeyAlgorithm = keyAlgorithmFromDetails([]*types.CertificateDetail{describeOutput.Certificate})
+		data.CertificateStatus = acmStatus([]*types.CertificateDetail{describeOutput.Certificate})
+		setACMDetail(&data, describeOutput.Certificate)
 		data.ID = tfTypes.StringValue(existingArn)
+		if err := addACMTags(ctx, r.clients.ACMClient, existingArn, tags); err != nil {
+			resp.Diagnostics.AddError("Failed to tag existing certificate", err.Error())
+		}
+		resp.Diagnostics.AddWarning("Existing Certificate Adopted", adoptionDetail(domainName, describeOutput.Certificate))
+		action = lifecycleAdopted
+		data.Origin = tfTypes.StringValue(originAdopted)
 		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		r.syncRenewalTags(ctx, data, nil, resp.Private, &resp.Diagnostics)
+		r.waitForInUse(ctx, data, &resp.Diagnostics)
 		return
 	}
 
-	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	importTags := r.clients.ownershipTags(domainName, data.Alias.ValueString())
+	maps.Copy(importTags, tags)
+	issued, err := r.importChunks(ctx, chunks, nil, importTags)
+	defer zeroizeIssued(issued)
+	if isLimitExceededError(err) {
+		if advice := r.clients.importQuotaAdvice(ctx); advice != "" {
+			err = fmt.Errorf("%w\n\n%s", err, advice)
+		}
+	}
+	if len(issued) == 0 {
+		resp.Diagnostics.AddError("Failed to issue certificate", err.Error())
+		return
+	}
+
+	arns := make([]string, len(issued))
+	cloudflareIDs := make([]string, len(issued))
+	for i, cert := range issued {
+		arns[i] = cert.arn
+		cloudflareIDs[i] = cert.cloudflareID
+	}
+	resp.Diagnostics.Append(setIssuanceMetadata(ctx, resp.Private, newIssuanceMetadata(issued[0].certPEM, cloudflareIDs))...)
+	data.CertificateArn = tfTypes.StringValue(arns[0])
+	data.CertificateArns = certificateArnList(arns)
+	data.CertificatePEM = tfTypes.StringValue(issued[0].certPEM)
+	data.MetadataJSON = metadataFromPEM(issued[0].certPEM)
+	data.PublicKeyPEM, data.KeyFingerprintSHA256 = publicKeyFromPEM(issued[0].certPEM)
+	data.CertificateDERBase64 = derBase64FromPEM(issued[0].certPEM)
+	data.ExpiresAt = expiryFromPEM(issued[0].certPEM)
+	data.SerialNumber = serialFromPEM(issued[0].certPEM)
+	data.CertificateStatus = tfTypes.StringValue(string(types.CertificateStatusIssued))
+	data.CloudflareStatus = tfTypes.StringValue(cloudflareStatusActive)
+	data.ID = tfTypes.StringValue(arns[0])
+	r.readACMDetail(ctx, &data, &resp.Diagnostics)
+	if issuance != nil && err == nil {
+		issuance.arn = arns[0]
+	}
+
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to generate private key", err.Error())
+		// The certificates imported so far are saved so Delete can clean
+		// them up; the resource is tainted and will be replaced.
+		resp.Diagnostics.AddError(
+			"Failed to issue certificate",
+			fmt.Sprintf("Issued %d of %d certificates: %s", len(issued), len(chunks), err),
+		)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 
-	csrTemplate := x509.CertificateRequest{
-		Subject:  pkix.Name{CommonName: domainName},
-		DNSNames: []string{domainName},
+	r.clients.warnImportHeadroom(ctx, &resp.Diagnostics)
+
+	if keyStore != nil {
+		if err := keyStore.Write(ctx, keyStoreLocation, keyStoreData(domainName, issued)); err != nil {
+			// State is still saved so the imported certificate is tracked;
+			// the resource is tainted and will be replaced on the next apply.
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to write certificate to %s", keyStore.Name()), err.Error())
+		}
 	}
-	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &csrTemplate, privateKey)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	r.syncRenewalTags(ctx, data, nil, resp.Private, &resp.Diagnostics)
+	r.waitForInUse(ctx, data, &resp.Diagnostics)
+}
+
+// waitForInUse blocks until ACM reports every certificate in use by at least
+// one resource, for up to wait_for_in_use. State is set before waiting, so a
+// certificate that is never attached is still tracked, and the resource is
+// tainted.
+func (r *CertificateResource) waitForInUse(ctx context.Context, data CertificateResourceModel, diags *diag.Diagnostics) {
+	if data.WaitForInUse.IsNull() || diags.HasError() {
+		return
+	}
+	maxWait, err := time.ParseDuration(data.WaitForInUse.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create CSR", err.Error())
 		return
 	}
+	var pending []string
+	diags.Append(data.CertificateArns.ElementsAs(ctx, &pending, false)...)
+	deadline := time.Now().Add(maxWait)
+	backoff := 5 * time.Second
+
+	for {
+		var unused []string
+		for _, arn := range pending {
+			output, err := r.clients.ACMClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+				CertificateArn: aws.String(arn),
+			})
+			if err != nil {
+				diags.AddError("Failed to check whether the certificate is in use", err.Error())
+				return
+			}
+			if len(output.Certificate.InUseBy) == 0 {
+				unused = append(unused, arn)
+			}
+		}
+		pending = unused
+		if len(pending) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			diags.AddError(
+				"Certificate Not In Use",
+				fmt.Sprintf("ACM did not report %s in use by any resource within wait_for_in_use (%s). "+
+					"The resource is tainted and will be replaced on the next apply.", strings.Join(pending, ", "), maxWait),
+			)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			diags.AddError("Certificate Not In Use", fmt.Sprintf("Stopped waiting for %s to be in use: %s", strings.Join(pending, ", "), ctx.Err()))
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 15*time.Second {
+			backoff += 5 * time.Second
+		}
+	}
+}
 
-	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+// createKMSBacked issues a certificate whose private key is an asymmetric KMS
+// key. The CSR is signed with kms:Sign, so no key material reaches the
+// provider or state, and the certificate is not imported into ACM.
+func (r *CertificateResource) createKMSBacked(ctx context.Context, data *CertificateResourceModel, resp *resource.CreateResponse) {
+	domainName := data.DomainName.ValueString()
 
-	certPEM, err := r.requestCloudflareOriginCert(domainName, string(csrPEM))
+	key, err := r.clients.KMSClient.createSigningKey(ctx, "Cloudflare Origin Certificate key for "+domainName)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to request Cloudflare Origin Certificate", err.Error())
+		resp.Diagnostics.AddError("Failed to create KMS key", err.Error())
 		return
 	}
 
-	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	signer, err := r.clients.KMSClient.signer(ctx, key.Arn)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to marshal private key", err.Error())
+		resp.Diagnostics.AddError("Failed to load KMS public key", err.Error())
+		r.discardKMSKey(ctx, key.Arn, &resp.Diagnostics)
 		return
 	}
-	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
 
-	importOutput, err := r.clients.ACMClient.ImportCertificate(ctx, &acm.ImportCertificateInput{
-		Certificate: []byte(certPEM),
-		PrivateKey:  keyPEM,
-	})
+	cert, ok := r.issueWithSigner(ctx, *data, signer, &resp.Diagnostics)
+	if !ok {
+		r.discardKMSKey(ctx, key.Arn, &resp.Diagnostics)
+		return
+	}
+
+	data.CertificateArn = tfTypes.StringNull()
+	data.CertificatePEM = tfTypes.StringValue(cert.Certificate)
+	data.MetadataJSON = metadataFromPEM(cert.Certificate)
+	data.PublicKeyPEM, data.KeyFingerprintSHA256 = publicKeyFromPEM(cert.Certificate)
+	data.CertificateDERBase64 = derBase64FromPEM(cert.Certificate)
+	data.ExpiresAt = expiryFromPEM(cert.Certificate)
+	data.SerialNumber = serialFromPEM(cert.Certificate)
+	data.CloudflareStatus = tfTypes.StringValue(cloudflareStatusActive)
+	data.KMSKeyArn = tfTypes.StringValue(key.Arn)
+	data.ID = tfTypes.StringValue(key.Arn)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+	resp.Diagnostics.Append(setIssuanceMetadata(ctx, resp.Private, newIssuanceMetadata(cert.Certificate, []string{cert.ID}))...)
+}
+
+// createPKCS11Backed issues a certificate whose key pair is generated on the
+// configured PKCS#11 token. Only the CSR and certificate pass through the
+// provider.
+func (r *CertificateResource) createPKCS11Backed(ctx context.Context, data *CertificateResourceModel, resp *resource.CreateResponse) {
+	if !r.clients.PKCS11Client.configured() {
+		resp.Diagnostics.AddError(
+			"Missing PKCS#11 Configuration",
+			"key_backend \"pkcs11\" requires the provider pkcs11_module_path attribute or PKCS11_MODULE_PATH environment variable.",
+		)
+		return
+	}
+
+	domainName := data.DomainName.ValueString()
+
+	keyID, err := r.clients.PKCS11Client.generateKey(ctx, "cfcert-"+domainName)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to import certificate to ACM", err.Error())
+		resp.Diagnostics.AddError("Failed to generate PKCS#11 key pair", err.Error())
 		return
 	}
 
-	arn := aws.ToString(importOutput.CertificateArn)
-	data.CertificateArn = tfTypes.StringValue(arn)
-	data.ID = tfTypes.StringValue(arn)
+	signer, err := r.clients.PKCS11Client.signer(ctx, keyID)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to load PKCS#11 public key", err.Error())
+		r.discardPKCS11Key(ctx, keyID, &resp.Diagnostics)
+		return
+	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	cert, ok := r.issueWithSigner(ctx, *data, signer, &resp.Diagnostics)
+	if !ok {
+		r.discardPKCS11Key(ctx, keyID, &resp.Diagnostics)
+		return
+	}
+
+	data.CertificateArn = tfTypes.StringNull()
+	data.CertificatePEM = tfTypes.StringValue(cert.Certificate)
+	data.MetadataJSON = metadataFromPEM(cert.Certificate)
+	data.PublicKeyPEM, data.KeyFingerprintSHA256 = publicKeyFromPEM(cert.Certificate)
+	data.CertificateDERBase64 = derBase64FromPEM(cert.Certificate)
+	data.ExpiresAt = expiryFromPEM(cert.Certificate)
+	data.SerialNumber = serialFromPEM(cert.Certificate)
+	data.CloudflareStatus = tfTypes.StringValue(cloudflareStatusActive)
+	data.PKCS11KeyID = tfTypes.StringValue(keyID)
+	data.ID = tfTypes.StringValue("pkcs11:" + keyID)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+	resp.Diagnostics.Append(setIssuanceMetadata(ctx, resp.Private, newIssuanceMetadata(cert.Certificate, []string{cert.ID}))...)
+}
+
+// issuedCertificate is one certificate of a possibly split hostname list.
+type issuedCertificate struct {
+	arn          string
+	cloudflareID string
+	certPEM      string
+	keyPEM       []byte
+}
+
+// importParallelism bounds how many hostname chunks are issued and imported
+// at once.
+const importParallelism = 4
+
+// importChunks issues a certificate for each hostname chunk and imports it
+// into ACM, re-importing into arns[i] when arns is given. New imports are
+// tagged with tags; re-imports keep their existing tags. Chunks are imported
+// concurrently, up to importParallelism at a time. It returns the
+// certificates that were imported, in chunk order, with every chunk's error
+// joined. Callers zeroize the returned keys once they are stored.
+func (r *CertificateResource) importChunks(ctx context.Context, chunks [][]string, arns []string, tags map[string]string) ([]issuedCertificate, error) {
+	results := make([]issuedCertificate, len(chunks))
+	errs := make([]error, len(chunks))
+	slots := make(chan struct{}, importParallelism)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			arn := ""
+			if i < len(arns) {
+				arn = arns[i]
+			}
+			results[i], errs[i] = r.importChunk(ctx, chunk, arn, tags)
+		}()
+	}
+	wg.Wait()
+
+	var issued []issuedCertificate
+	for i := range chunks {
+		if errs[i] == nil {
+			issued = append(issued, results[i])
+			continue
+		}
+		if len(chunks) > 1 {
+			errs[i] = fmt.Errorf("certificate %d of %d (%s): %w", i+1, len(chunks), strings.Join(chunks[i], ", "), errs[i])
+		}
+	}
+	return issued, errors.Join(errs...)
+}
+
+// importChunk issues a certificate for hostnames and imports it into ACM,
+// re-importing into arn when it is not "".
+func (r *CertificateResource) importChunk(ctx context.Context, hostnames []string, arn string, tags map[string]string) (issuedCertificate, error) {
+	cert, keyPEM, err := r.clients.issueWithLocalKey(ctx, hostnames)
+	if err != nil {
+		return issuedCertificate{}, err
+	}
+	if err := verifyKeyPair(cert.Certificate, keyPEM); err != nil {
+		zeroize(keyPEM)
+		return issuedCertificate{}, fmt.Errorf("not importing certificate %s to ACM: %w", cert.ID, err)
+	}
+
+	input := &acm.ImportCertificateInput{
+		Certificate: []byte(cert.Certificate),
+		PrivateKey:  keyPEM,
+	}
+	if arn != "" {
+		input.CertificateArn = aws.String(arn)
+	} else {
+		input.Tags = acmTags(tags)
+	}
+	importOutput, err := r.clients.ACMClient.ImportCertificate(ctx, input)
+	if err != nil {
+		zeroize(keyPEM)
+	}
+	if isLimitExceededError(err) {
+		return issuedCertificate{}, fmt.Errorf("importing certificate to ACM: an ACM quota was reached, which retrying will not fix. "+
+			"ACM limits both the number of imported certificates and how many can be imported per year; "+
+			"delete unused certificates or request a quota increase in Service Quotas: %w", err)
+	}
+	if err != nil {
+		return issuedCertificate{}, fmt.Errorf("importing certificate to ACM: %w", err)
+	}
+	if arn == "" {
+		r.clients.CertificateList.invalidate()
+	}
+
+	return issuedCertificate{
+		arn:          aws.ToString(importOutput.CertificateArn),
+		cloudflareID: cert.ID,
+		certPEM:      cert.Certificate,
+		keyPEM:       keyPEM,
+	}, nil
+}
+
+func certificateArnList(arns []string) tfTypes.List {
+	values := make([]attr.Value, len(arns))
+	for i, arn := range arns {
+		values[i] = tfTypes.StringValue(arn)
+	}
+	return tfTypes.ListValueMust(tfTypes.StringType, values)
+}
+
+// issueWithSigner builds a CSR with an externally held key and requests the
+// origin certificate for it. Externally held keys get a single certificate,
+// so the hostnames must fit within Cloudflare's limit.
+func (r *CertificateResource) issueWithSigner(ctx context.Context, data CertificateResourceModel, signer crypto.Signer, diags *diag.Diagnostics) (cloudflare.OriginCert, bool) {
+	chunks, d := r.hostnameChunks(ctx, data)
+	diags.Append(d...)
+	if diags.HasError() {
+		return cloudflare.OriginCert{}, false
+	}
+	if len(chunks) > 1 {
+		diags.AddError(
+			"Too Many Hostnames",
+			fmt.Sprintf("Cloudflare accepts at most %d hostnames per certificate.", cloudflare.MaxHostnames),
+		)
+		return cloudflare.OriginCert{}, false
+	}
+
+	csrPEM, err := createCSR(chunks[0], signer)
+	if err != nil {
+		diags.AddError("Failed to create CSR", err.Error())
+		return cloudflare.OriginCert{}, false
+	}
+
+	cert, err := r.clients.requestCloudflareOriginCert(ctx, chunks[0], csrPEM)
+	if err != nil {
+		diags.AddError("Failed to request Cloudflare Origin Certificate", err.Error())
+		return cloudflare.OriginCert{}, false
+	}
+	return cert, true
+}
+
+func (r *CertificateResource) discardKMSKey(ctx context.Context, keyArn string, diags *diag.Diagnostics) {
+	if err := r.clients.KMSClient.scheduleKeyDeletion(ctx, keyArn); err != nil {
+		diags.AddWarning(
+			"Failed to clean up KMS key",
+			fmt.Sprintf("KMS key %s was created but could not be scheduled for deletion: %s", keyArn, err),
+		)
+	}
+}
+
+func (r *CertificateResource) discardPKCS11Key(ctx context.Context, keyID string, diags *diag.Diagnostics) {
+	if err := r.clients.PKCS11Client.deleteKey(ctx, keyID); err != nil {
+		diags.AddWarning(
+			"Failed to clean up PKCS#11 key",
+			fmt.Sprintf("PKCS#11 key pair %s was generated but could not be deleted: %s", keyID, err),
+		)
+	}
 }
 
 func (r *CertificateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -160,6 +1156,66 @@ func (r *CertificateResource) Read(ctx context.Context, req resource.ReadRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	r = r.withAssumeRole(ctx, data.AssumeRole, &resp.Diagnostics)
+	r = r.withCloudflareAccount(data.AccountID)
+	r = r.withRetry(ctx, data.Retry, &resp.Diagnostics)
+	ctx = withDomainLogField(ctx, data.DomainName.ValueString())
+
+	// State written before key_backend existed always used ACM.
+	if data.KeyBackend.IsNull() {
+		data.KeyBackend = tfTypes.StringValue(keyBackendACM)
+	}
+	// State written before metadata_json existed lacks it.
+	if data.MetadataJSON.IsNull() && !data.CertificatePEM.IsNull() {
+		data.MetadataJSON = metadataFromPEM(data.CertificatePEM.ValueString())
+	}
+	// State written before public_key_pem existed lacks it.
+	if data.PublicKeyPEM.IsNull() && !data.CertificatePEM.IsNull() {
+		data.PublicKeyPEM, data.KeyFingerprintSHA256 = publicKeyFromPEM(data.CertificatePEM.ValueString())
+	}
+	// State written before certificate_der_base64 existed lacks it.
+	if data.CertificateDERBase64.IsNull() && !data.CertificatePEM.IsNull() {
+		data.CertificateDERBase64 = derBase64FromPEM(data.CertificatePEM.ValueString())
+	}
+	// State written before origin existed lacks it. Issuance metadata is only
+	// recorded for certificates this resource issued; others stay unknown.
+	if data.Origin.IsNull() {
+		if meta, diags := getIssuanceMetadata(ctx, req.Private); meta != nil && !diags.HasError() {
+			data.Origin = tfTypes.StringValue(originIssued)
+		}
+	}
+	// State written before key_algorithm existed lacks it; KMS and PKCS#11
+	// keys are always generated as P-256.
+	if data.KeyAlgorithm.IsNull() {
+		data.KeyAlgorithm = tfTypes.StringValue(issuedKeyAlgorithm)
+	}
+
+	if data.KeyBackend.ValueString() == keyBackendKMS {
+		key, err := r.clients.KMSClient.describeKey(ctx, data.KMSKeyArn.ValueString())
+		if isKMSNotFoundError(err) || key.KeyState == "PendingDeletion" {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to describe KMS key", err.Error())
+			return
+		}
+		r.readCloudflareStatus(ctx, &data, req.Private, &resp.Diagnostics)
+		r.checkRevocation(ctx, data, &resp.Diagnostics)
+		r.clients.warnIfExpiring(&resp.Diagnostics, data.DomainName.ValueString(), data.ExpiresAt.ValueString())
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	// Checking the token requires a PIN login, so PKCS#11 keys are trusted
+	// to exist until the resource is deleted.
+	if data.KeyBackend.ValueString() == keyBackendPKCS11 {
+		r.readCloudflareStatus(ctx, &data, req.Private, &resp.Diagnostics)
+		r.checkRevocation(ctx, data, &resp.Diagnostics)
+		r.clients.warnIfExpiring(&resp.Diagnostics, data.DomainName.ValueString(), data.ExpiresAt.ValueString())
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
 
 	arn := data.CertificateArn.ValueString()
 	if arn == "" {
@@ -167,25 +1223,201 @@ func (r *CertificateResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	_, err := r.clients.ACMClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+	// State written before hostnames existed held a single certificate.
+	if data.CertificateArns.IsNull() {
+		data.CertificateArns = certificateArnList([]string{arn})
+	}
+	var arns []string
+	resp.Diagnostics.Append(data.CertificateArns.ElementsAs(ctx, &arns, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	details, err := r.describeCertificates(ctx, arns)
+	// Only a missing certificate means it is gone; anything else, such as
+	// AccessDenied or throttling, must not orphan it.
+	if isResourceNotFoundError(err) {
+		if r.reimportable(ctx, &data, req.Private, &resp.Diagnostics) {
+			_, location := r.clients.keyStore(data.keyStoreLocations())
+			data.CertificateStatus = tfTypes.StringValue(certificateStatusDeleted)
+			resp.Diagnostics.AddWarning(
+				"Certificate Deleted Outside Terraform",
+				fmt.Sprintf("%s no longer exists in ACM. The certificate and key stored at %s will be imported again on the next apply.",
+					arn, location),
+			)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to describe certificate", err.Error())
+		return
+	}
+	data.ExpiresAt = expiryFromDetail(details[0])
+	serial := serialFromDetail(details[0])
+	if !data.SerialNumber.IsNull() && !serial.Equal(data.SerialNumber) {
+		resp.Diagnostics.AddWarning(
+			"Certificate Serial Changed Outside Terraform",
+			fmt.Sprintf("%s now holds certificate %s instead of %s; it was re-imported or rotated outside Terraform. "+
+				"Set replace_on_drift to plan a replacement, or replace the resource to bring it back under Terraform's control.",
+				arns[0], serial.ValueString(), data.SerialNumber.ValueString()),
+		)
+	}
+	data.SerialNumber = serial
+	data.KeyAlgorithm = keyAlgorithmFromDetails(details)
+	data.CertificateStatus = acmStatus(details)
+	setACMDetail(&data, details[0])
+
+	r.readTags(ctx, &data, arns[0], &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.checkReimported(ctx, &data, details[0], req.Private, &resp.Diagnostics)
+	r.readCloudflareStatus(ctx, &data, req.Private, &resp.Diagnostics)
+	r.checkRevocation(ctx, data, &resp.Diagnostics)
+	r.clients.warnIfExpiring(&resp.Diagnostics, data.DomainName.ValueString(), data.ExpiresAt.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// readACMDetail describes the certificate at certificate_arn for
+// setACMDetail. It only warns on failure, since the certificate itself is in
+// place and the next refresh fills the attributes in.
+func (r *CertificateResource) readACMDetail(ctx context.Context, data *CertificateResourceModel, diags *diag.Diagnostics) {
+	output, err := r.clients.ACMClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+		CertificateArn: aws.String(data.CertificateArn.ValueString()),
+	})
+	if err != nil {
+		setACMDetail(data, nil)
+		diags.AddWarning("Failed to describe certificate", err.Error())
+		return
+	}
+	setACMDetail(data, output.Certificate)
+}
+
+// describeCertificates describes every ARN concurrently, up to
+// importParallelism at a time, returning the details in ARN order or the
+// first ARN's error.
+func (r *CertificateResource) describeCertificates(ctx context.Context, arns []string) ([]*types.CertificateDetail, error) {
+	details := make([]*types.CertificateDetail, len(arns))
+	errs := make([]error, len(arns))
+	slots := make(chan struct{}, importParallelism)
+	var wg sync.WaitGroup
+	for i, arn := range arns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			output, err := r.clients.ACMClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+				CertificateArn: aws.String(arn),
+			})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			details[i] = output.Certificate
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return details, nil
+}
+
+// checkReimported compares the certificate in ACM with the key recorded at
+// issuance, warning when it was re-imported outside Terraform. The
+// certificate body is only fetched when its serial number no longer matches
+// the one recorded at issuance.
+func (r *CertificateResource) checkReimported(ctx context.Context, data *CertificateResourceModel, detail *types.CertificateDetail, private privateState, diags *diag.Diagnostics) {
+	meta, d := getIssuanceMetadata(ctx, private)
+	diags.Append(d...)
+	if meta == nil || meta.KeyFingerprint == "" {
+		return
+	}
+	if meta.Serial != "" && serialFromDetail(detail).ValueString() == meta.Serial {
+		return
+	}
+
+	arn := aws.ToString(detail.CertificateArn)
+	output, err := r.clients.ACMClient.GetCertificate(ctx, &acm.GetCertificateInput{
 		CertificateArn: aws.String(arn),
 	})
 	if err != nil {
-		resp.State.RemoveResource(ctx)
+		diags.AddWarning("Failed to check certificate for drift", err.Error())
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	certPEM := aws.ToString(output.Certificate)
+	if keyFingerprint(certPEM) == meta.KeyFingerprint {
+		return
+	}
+	diags.AddWarning(
+		"Certificate Changed Outside Terraform",
+		fmt.Sprintf("%s no longer holds the key this resource issued on %s; it was re-imported outside Terraform. "+
+			"Replace the resource to issue a new certificate under Terraform's control.", arn, meta.IssuedAt),
+	)
+	data.CertificatePEM = tfTypes.StringValue(certPEM)
+	data.MetadataJSON = metadataFromPEM(certPEM)
+	data.PublicKeyPEM, data.KeyFingerprintSHA256 = publicKeyFromPEM(certPEM)
+	data.CertificateDERBase64 = derBase64FromPEM(certPEM)
 }
 
 func (r *CertificateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// domain_name forces replacement, so Update is a no-op
-	var data CertificateResourceModel
+	// domain_name forces replacement, so Update only renews certificates that
+	// ModifyPlan found inside their rotation policy's renewal window.
+	var data, state CertificateResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	r = r.withAssumeRole(ctx, data.AssumeRole, &resp.Diagnostics)
+	r = r.withCloudflareAccount(data.AccountID)
+	r = r.withRetry(ctx, data.Retry, &resp.Diagnostics)
+	ctx = withDomainLogField(ctx, data.DomainName.ValueString())
+
+	if state.CertificateStatus.ValueString() == certificateStatusDeleted && data.CertificateArn.IsUnknown() {
+		r.reimportFromKeyStore(ctx, &data, state, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		r.syncRenewalTags(ctx, data, &state, resp.Private, &resp.Diagnostics)
+		return
+	}
+
+	if data.ExpiresAt.IsUnknown() {
+		r.renewWithPolicy(ctx, &data, state, resp.Private, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			if !data.ExpiresAt.IsUnknown() {
+				// Some hostname chunks were renewed before the failure;
+				// save them, leaving tags as they were.
+				data.TagsAll = state.TagsAll
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			}
+			return
+		}
+	}
+
+	if data.KeyBackend.ValueString() == keyBackendACM && !data.TagsAll.Equal(state.TagsAll) {
+		var arns []string
+		resp.Diagnostics.Append(state.CertificateArns.ElementsAs(ctx, &arns, false)...)
+		resp.Diagnostics.Append(r.updateTags(ctx, arns, state.TagsAll, data.TagsAll)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if !data.RenewalTags.Equal(state.RenewalTags) || !data.ExpiresAt.Equal(state.ExpiresAt) {
+		r.syncRenewalTags(ctx, data, &state, resp.Private, &resp.Diagnostics)
+	}
 }
 
 func (r *CertificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -194,13 +1426,74 @@ func (r *CertificateResource) Delete(ctx context.Context, req resource.DeleteReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	r = r.withAssumeRole(ctx, data.AssumeRole, &resp.Diagnostics)
+	r = r.withCloudflareAccount(data.AccountID)
+	r = r.withRetry(ctx, data.Retry, &resp.Diagnostics)
+	ctx = withDomainLogField(ctx, data.DomainName.ValueString())
+	defer func() {
+		if !resp.Diagnostics.HasError() {
+			r.revokeOnDelete(ctx, req.Private, &resp.Diagnostics)
+			r.publishLifecycleEvent(ctx, lifecycleDeleted, data, req.Private, &resp.Diagnostics)
+		}
+	}()
 
-	arn := data.CertificateArn.ValueString()
-	if arn == "" {
+	switch data.KeyBackend.ValueString() {
+	case keyBackendKMS:
+		err := r.clients.KMSClient.scheduleKeyDeletion(ctx, data.KMSKeyArn.ValueString())
+		if err != nil && !isKMSNotFoundError(err) {
+			resp.Diagnostics.AddError("Failed to schedule KMS key deletion", err.Error())
+		}
 		return
+	case keyBackendPKCS11:
+		if err := r.clients.PKCS11Client.deleteKey(ctx, data.PKCS11KeyID.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Failed to delete PKCS#11 key pair", err.Error())
+		}
+		return
+	}
+
+	arns := []string{data.CertificateArn.ValueString()}
+	if !data.CertificateArns.IsNull() {
+		resp.Diagnostics.Append(data.CertificateArns.ElementsAs(ctx, &arns, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// State written before delete_wait_for_unused existed has no value.
+	wait, err := time.ParseDuration(data.DeleteWaitForUnused.ValueString())
+	if err != nil {
+		wait, _ = time.ParseDuration(defaultDeleteWaitForUnused)
+	}
+
+	for _, arn := range arns {
+		if arn == "" {
+			continue
+		}
+		err := r.deleteACMCertificate(ctx, arn, wait)
+		if isResourceInUseError(err) {
+			resp.Diagnostics.AddError("Certificate Still In Use", r.inUseDetail(ctx, arn, err))
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to delete certificate", err.Error())
+			return
+		}
 	}
 
-	const maxWait = 60 * time.Second
+	// Deleted only once ACM no longer holds the certificate, so a failed
+	// delete leaves the private key of a certificate still in use.
+	if keyStore, location := r.clients.keyStore(data.keyStoreLocations()); keyStore != nil {
+		if err := deleteStoredCertificate(ctx, keyStore, location, data.CertificateArn.ValueString()); err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to delete certificate from %s", keyStore.Name()), err.Error())
+		}
+	}
+}
+
+// deleteACMCertificate deletes arn, retrying for up to maxWait while services
+// such as CloudFront release it. ACM can take a few minutes to notice that a
+// load balancer listener has moved to a replacement certificate, as happens
+// with create_before_destroy.
+func (r *CertificateResource) deleteACMCertificate(ctx context.Context, arn string, maxWait time.Duration) error {
 	deadline := time.Now().Add(maxWait)
 	backoff := 5 * time.Second
 
@@ -209,119 +1502,241 @@ func (r *CertificateResource) Delete(ctx context.Context, req resource.DeleteReq
 			CertificateArn: aws.String(arn),
 		})
 		if err == nil {
-			return
+			r.clients.CertificateList.invalidate()
+			return nil
 		}
 		if !isResourceInUseError(err) || time.Now().After(deadline) {
-			resp.Diagnostics.AddError("Failed to delete certificate", err.Error())
-			return
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
 		}
-		time.Sleep(backoff)
 		if backoff < 15*time.Second {
 			backoff += 5 * time.Second
 		}
 	}
 }
 
-func (r *CertificateResource) findExistingCertificate(ctx context.Context, domainName string) (string, error) {
-	paginator := acm.NewListCertificatesPaginator(r.clients.ACMClient, &acm.ListCertificatesInput{
-		CertificateStatuses: []types.CertificateStatus{types.CertificateStatusIssued},
-		Includes: &types.Filters{
-			KeyTypes: []types.KeyAlgorithm{types.KeyAlgorithmEcPrime256v1},
-		},
-		SortBy:    types.SortByCreatedAt,
-		SortOrder: types.SortOrderDescending,
-	})
-
-	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(ctx)
+// ImportState imports an ACM backed certificate by ARN, or by domain name,
+// optionally followed by "/<alias>", using the same lookup as certificate
+// adoption.
+func (r *CertificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	arn := req.ID
+	if parsed, err := awsarn.Parse(arn); err == nil {
+		if parsed.Region != r.clients.Region {
+			resp.Diagnostics.AddError(
+				"Certificate Region Mismatch",
+				fmt.Sprintf("%s is in %s, but the provider is configured for %s. Import it with a provider configured for %s.",
+					arn, parsed.Region, r.clients.Region, parsed.Region),
+			)
+			return
+		}
+	} else {
+		// A domain name cannot contain "/", so "<domain>/<alias>" picks the
+		// certificate imported under alias, as adoption would.
+		domainName, alias, _ := strings.Cut(req.ID, "/")
+		candidates, err := r.findExistingCertificates(ctx, domainName, alias)
 		if err != nil {
-			return "", err
+			resp.Diagnostics.AddError("Failed to look up certificate", err.Error())
+			return
 		}
-		for _, cert := range page.CertificateSummaryList {
-			if aws.ToString(cert.DomainName) == domainName {
-				return aws.ToString(cert.CertificateArn), nil
+		var diags diag.Diagnostics
+		arn, diags = adoptionCandidate(domainName, adoptionStrategyNewest, candidates)
+		resp.Diagnostics.Append(diags...)
+		if arn == "" {
+			detail := fmt.Sprintf("No issued EC_prime256v1 Cloudflare Origin certificate for %q was found in ACM.", domainName)
+			if alias != "" {
+				detail = fmt.Sprintf("No issued EC_prime256v1 Cloudflare Origin certificate for %q with alias %q was found in ACM.", domainName, alias)
 			}
+			resp.Diagnostics.AddError("Certificate Not Found", detail)
+			return
 		}
 	}
-	return "", nil
-}
 
-func isResourceInUseError(err error) bool {
-	return strings.Contains(err.Error(), "ResourceInUseException")
-}
+	describeOutput, err := r.clients.ACMClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+		CertificateArn: aws.String(arn),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to describe certificate", err.Error())
+		return
+	}
 
-type cloudflareOriginCertRequest struct {
-	CSR               string   `json:"csr"`
-	Hostnames         []string `json:"hostnames"`
-	RequestType       string   `json:"request_type"`
-	RequestedValidity int      `json:"requested_validity"`
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain_name"), aws.ToString(describeOutput.Certificate.DomainName))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key_backend"), keyBackendACM)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("certificate_arn"), arn)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("certificate_arns"), []string{arn})...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("delete_wait_for_unused"), defaultDeleteWaitForUnused)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("adoption_strategy"), adoptionStrategyNewest)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("origin"), originImported)...)
+	if _, alias, err := r.clients.ownership(ctx, arn); err != nil {
+		resp.Diagnostics.AddError("Failed to read certificate tags", err.Error())
+	} else if alias != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("alias"), alias)...)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), arn)...)
 }
 
-type cloudflareOriginCertResponse struct {
-	Success bool `json:"success"`
-	Result  struct {
-		Certificate string `json:"certificate"`
-	} `json:"result"`
-	Errors []struct {
-		Message string `json:"message"`
-	} `json:"errors"`
-}
+// findExistingCertificates returns the issued P-256 certificates in ACM
+// covering domainName, as their primary domain or one of their SANs, that
+// this provider imported under alias and Cloudflare Origin CA issued, newest
+// first. Certificates from other CAs or tooling for the same domain are never
+// adopted.
+func (r *CertificateResource) findExistingCertificates(ctx context.Context, domainName, alias string) ([]string, error) {
+	domainName = normalizeHostname(domainName)
+	summaries, err := r.clients.CertificateList.issuedP256(ctx, r.clients.ACMClient)
+	if err != nil {
+		return nil, err
+	}
 
-func (r *CertificateResource) requestCloudflareOriginCert(domainName, csrPEM string) (string, error) {
-	reqBody := cloudflareOriginCertRequest{
-		CSR:               csrPEM,
-		Hostnames:         []string{domainName},
-		RequestType:       "origin-ecc",
-		RequestedValidity: 5475,
+	var candidates []string
+	for _, cert := range summaries {
+		arn := aws.ToString(cert.CertificateArn)
+		covered, err := r.coversDomain(ctx, cert, domainName)
+		if err != nil {
+			return nil, err
+		}
+		if !covered {
+			continue
+		}
+		owned, certAlias, err := r.clients.ownership(ctx, arn)
+		if err != nil {
+			return nil, err
+		}
+		if !owned || certAlias != alias {
+			continue
+		}
+		ok, err := r.issuedByCloudflareOriginCA(ctx, arn)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			candidates = append(candidates, arn)
+		}
 	}
+	return candidates, nil
+}
 
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+// adoptionDetail describes an adopted certificate, so operators know this
+// resource did not generate a fresh key and where the one it uses came from.
+func adoptionDetail(domainName string, detail *types.CertificateDetail) string {
+	expires, issuer := "unknown", "unknown"
+	if detail.NotAfter != nil {
+		expires = detail.NotAfter.UTC().Format(time.RFC3339)
+	}
+	if detail.Issuer != nil && *detail.Issuer != "" {
+		issuer = *detail.Issuer
 	}
+	return fmt.Sprintf("Adopted the existing ACM certificate %s for %q instead of issuing a new one, so Terraform did not "+
+		"generate a fresh private key for this resource. Expires: %s. Issuer: %s.\n\n"+
+		"Resources that set hostnames or a key store attribute such as vault_kv_path never adopt, and always issue their own certificate and key.",
+		aws.ToString(detail.CertificateArn), domainName, expires, issuer)
+}
 
-	httpReq, err := http.NewRequest("POST", "https://api.cloudflare.com/client/v4/certificates", bytes.NewReader(jsonBody))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+// adoptionCandidate picks the certificate to adopt from candidates, ordered
+// newest first, according to strategy. More than one candidate is reported,
+// as a warning or, with the error strategy, as an error.
+func adoptionCandidate(domainName, strategy string, candidates []string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if len(candidates) == 0 {
+		return "", diags
+	}
+	if len(candidates) == 1 {
+		return candidates[0], diags
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	if r.clients.CloudflareAPIToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+r.clients.CloudflareAPIToken)
-	} else if r.clients.CloudflareServiceAPIToken != "" {
-		httpReq.Header.Set("X-Auth-User-Service-Key", r.clients.CloudflareServiceAPIToken)
-	} else {
-		return "", fmt.Errorf("no Cloudflare API token provided")
+	list := "\n  " + strings.Join(candidates, "\n  ")
+	switch strategy {
+	case adoptionStrategyError:
+		diags.AddError(
+			"Multiple Certificates Match Domain",
+			fmt.Sprintf("%d Cloudflare Origin certificates for %q could be adopted, newest first:%s\n\n"+
+				"Set adoption_strategy to \"newest\" or \"oldest\", or import the intended certificate by ARN.",
+				len(candidates), domainName, list),
+		)
+		return "", diags
+	case adoptionStrategyOldest:
+		arn := candidates[len(candidates)-1]
+		diags.AddWarning(
+			"Multiple Certificates Match Domain",
+			fmt.Sprintf("%d Cloudflare Origin certificates for %q could be adopted; adopting the oldest, %s. Candidates, newest first:%s",
+				len(candidates), domainName, arn, list),
+		)
+		return arn, diags
+	default:
+		arn := candidates[0]
+		diags.AddWarning(
+			"Multiple Certificates Match Domain",
+			fmt.Sprintf("%d Cloudflare Origin certificates for %q could be adopted; adopting the newest, %s. Candidates, newest first:%s",
+				len(candidates), domainName, arn, list),
+		)
+		return arn, diags
 	}
+}
 
-	client := &http.Client{}
-	httpResp, err := client.Do(httpReq)
+// coversDomain reports whether a listed certificate names domainName. The
+// list only summarises SANs, so certificates with more are described.
+func (r *CertificateResource) coversDomain(ctx context.Context, cert types.CertificateSummary, domainName string) (bool, error) {
+	if aws.ToString(cert.DomainName) == domainName || slices.Contains(cert.SubjectAlternativeNameSummaries, domainName) {
+		return true, nil
+	}
+	if !aws.ToBool(cert.HasAdditionalSubjectAlternativeNames) {
+		return false, nil
+	}
+	output, err := r.clients.ACMClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+		CertificateArn: cert.CertificateArn,
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return false, fmt.Errorf("failed to describe %s: %w", aws.ToString(cert.CertificateArn), err)
 	}
-	defer httpResp.Body.Close()
+	return slices.Contains(output.Certificate.SubjectAlternativeNames, domainName), nil
+}
 
-	body, err := io.ReadAll(httpResp.Body)
+func (r *CertificateResource) issuedByCloudflareOriginCA(ctx context.Context, arn string) (bool, error) {
+	output, err := r.clients.ACMClient.GetCertificate(ctx, &acm.GetCertificateInput{
+		CertificateArn: aws.String(arn),
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return false, fmt.Errorf("failed to get %s: %w", arn, err)
 	}
-
-	var cfResp cloudflareOriginCertResponse
-	if err := json.Unmarshal(body, &cfResp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+	cert, err := parseCertificatePEM(aws.ToString(output.Certificate))
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", arn, err)
+	}
+	if !isCloudflareOriginCA(cert) {
+		return false, nil
 	}
+	return r.clients.verifyOriginCAChain(cert, aws.ToString(output.CertificateChain)) == nil, nil
+}
 
-	if !cfResp.Success {
-		errMsg := "unknown error"
-		if len(cfResp.Errors) > 0 {
-			errMsg = ""
-			for _, err := range cfResp.Errors {
-				errMsg += fmt.Sprintf("%s; ", err.Message)
-			}
-			errMsg = strings.TrimSuffix(errMsg, "; ")
-		}
-		return "", fmt.Errorf("cloudflare API error: %s", errMsg)
+// inUseDetail explains a delete that never stopped failing with
+// ResourceInUseException, listing what still references the certificate.
+func (r *CertificateResource) inUseDetail(ctx context.Context, arn string, err error) string {
+	detail := fmt.Sprintf("%s is still in use after waiting for it to be released: %s", arn, err)
+	output, describeErr := r.clients.ACMClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+		CertificateArn: aws.String(arn),
+	})
+	if describeErr != nil || len(output.Certificate.InUseBy) == 0 {
+		return detail
 	}
+	return detail + "\n\nDetach it from the following resources and apply again:\n  " +
+		strings.Join(output.Certificate.InUseBy, "\n  ")
+}
+
+func isResourceInUseError(err error) bool {
+	var inUse *types.ResourceInUseException
+	return errors.As(err, &inUse)
+}
+
+// isLimitExceededError reports an ACM quota, as opposed to request
+// throttling, which the SDK retries.
+func isLimitExceededError(err error) bool {
+	var limitExceeded *types.LimitExceededException
+	return errors.As(err, &limitExceeded)
+}
 
-	return cfResp.Result.Certificate, nil
+func isResourceNotFoundError(err error) bool {
+	var notFound *types.ResourceNotFoundException
+	return errors.As(err, &notFound)
 }
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Certificate lifecycle actions reported by publishLifecycleEvent.
+const (
+	lifecycleIssued  = "issued"
+	lifecycleAdopted = "adopted"
+	lifecycleRenewed = "renewed"
+	lifecycleDeleted = "deleted"
+)
+
+// lifecycleEvent is the detail of a lifecycle event. Field names are part of
+// the event's contract, so only add to them.
+type lifecycleEvent struct {
+	Action          string   `json:"action"`
+	DomainName      string   `json:"domain_name"`
+	Hostnames       []string `json:"hostnames,omitempty"`
+	CertificateArns []string `json:"certificate_arns,omitempty"`
+	// CloudflareCertificateIDs are the Origin CA IDs of the certificates
+	// issued for the resource.
+	CloudflareCertificateIDs []string `json:"cloudflare_certificate_ids,omitempty"`
+	SerialNumber             string   `json:"serial_number,omitempty"`
+	ExpiresAt                string   `json:"expires_at,omitempty"`
+	KeyBackend               string   `json:"key_backend"`
+	Workspace                string   `json:"workspace,omitempty"`
+}
+
+// newLifecycleEvent describes action on the certificate in data, whose
+// issuance metadata is in private.
+func newLifecycleEvent(ctx context.Context, action string, data CertificateResourceModel, private privateState) lifecycleEvent {
+	event := lifecycleEvent{
+		Action:       action,
+		DomainName:   data.DomainName.ValueString(),
+		SerialNumber: data.SerialNumber.ValueString(),
+		ExpiresAt:    data.ExpiresAt.ValueString(),
+		KeyBackend:   data.KeyBackend.ValueString(),
+	}
+	if !data.Hostnames.IsNull() && !data.Hostnames.IsUnknown() {
+		data.Hostnames.ElementsAs(ctx, &event.Hostnames, false)
+	}
+	if !data.CertificateArns.IsNull() && !data.CertificateArns.IsUnknown() {
+		data.CertificateArns.ElementsAs(ctx, &event.CertificateArns, false)
+	} else if data.CertificateArn.ValueString() != "" {
+		event.CertificateArns = []string{data.CertificateArn.ValueString()}
+	}
+	if meta, _ := getIssuanceMetadata(ctx, private); meta != nil {
+		event.CloudflareCertificateIDs = meta.CloudflareCertificateIDs
+	}
+	return event
+}
+
+// snsMaxSubjectLength is the longest subject SNS accepts.
+const snsMaxSubjectLength = 100
+
+// publishLifecycleEvent reports action on the certificate in data to the
+// provider's event_bus_name and the resource's notification_topic_arn, and
+// records issuance, renewal and deletion in the provider's audit log. The
+// certificate has already changed by the time it is called, so failures are
+// warnings.
+func (r *CertificateResource) publishLifecycleEvent(ctx context.Context, action string, data CertificateResourceModel, private privateState, diags *diag.Diagnostics) {
+	topicArn := data.NotificationTopicArn.ValueString()
+	if r.clients.EventBusName == "" && topicArn == "" && r.clients.AuditLog == nil {
+		return
+	}
+	event := newLifecycleEvent(ctx, action, data, private)
+	event.Workspace = r.clients.Workspace
+	detailType := "Certificate " + titleCase(action)
+
+	if r.clients.EventBusName != "" {
+		if err := r.clients.EventBridgeClient.putEvent(ctx, r.clients.EventBusName, detailType, event); err != nil {
+			diags.AddWarning(
+				"Failed to publish certificate event",
+				fmt.Sprintf("The %q event for %s was not put on EventBridge bus %s: %s", detailType, event.DomainName, r.clients.EventBusName, err),
+			)
+		}
+	}
+
+	if topicArn != "" {
+		message, err := json.Marshal(event)
+		if err == nil {
+			subject := detailType + ": " + event.DomainName
+			if len(subject) > snsMaxSubjectLength {
+				subject = subject[:snsMaxSubjectLength]
+			}
+			err = r.clients.SNSClient.publish(ctx, topicArn, subject, string(message))
+		}
+		if err != nil {
+			diags.AddWarning(
+				"Failed to send certificate notification",
+				fmt.Sprintf("The %q notification for %s was not published to %s: %s", detailType, event.DomainName, topicArn, err),
+			)
+		}
+	}
+
+	if r.clients.AuditLog != nil && action != lifecycleAdopted {
+		err := r.clients.AuditLog.write(ctx, auditRecord{
+			Action:                   action,
+			DomainName:               event.DomainName,
+			CertificateArns:          event.CertificateArns,
+			CloudflareCertificateIDs: event.CloudflareCertificateIDs,
+			SerialNumber:             event.SerialNumber,
+			Workspace:                event.Workspace,
+		})
+		if err != nil {
+			diags.AddWarning(
+				"Failed to write audit record",
+				fmt.Sprintf("The %s of the certificate for %s was not recorded in s3://%s/%s: %s",
+					action, event.DomainName, r.clients.AuditLog.bucket, r.clients.AuditLog.prefix, err),
+			)
+		}
+	}
+}
+
+// titleCase upper-cases the first letter of an ASCII word.
+func titleCase(word string) string {
+	if word == "" || word[0] < 'a' || word[0] > 'z' {
+		return word
+	}
+	return string(word[0]-'a'+'A') + word[1:]
+}
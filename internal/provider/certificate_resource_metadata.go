@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // SHA-1 fingerprints are still used for pinning and thumbprints
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// issuedKeyAlgorithm is the ACM key algorithm of every certificate this
+// provider issues.
+const issuedKeyAlgorithm = string(types.KeyAlgorithmEcPrime256v1)
+
+// certificateMetadata is the shape of metadata_json. Field names are part of
+// the attribute's contract, so only add to them.
+type certificateMetadata struct {
+	SerialNumber      string   `json:"serial_number"`
+	Subject           string   `json:"subject"`
+	Issuer            string   `json:"issuer"`
+	SANs              []string `json:"sans"`
+	NotBefore         string   `json:"not_before"`
+	NotAfter          string   `json:"not_after"`
+	SHA1Fingerprint   string   `json:"sha1_fingerprint"`
+	SHA256Fingerprint string   `json:"sha256_fingerprint"`
+}
+
+// metadataFromPEM summarises the first certificate in certPEM as JSON, or
+// returns null when it cannot be parsed.
+func metadataFromPEM(certPEM string) tfTypes.String {
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return tfTypes.StringNull()
+	}
+
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses))
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sha1Sum := sha1.Sum(cert.Raw) //nolint:gosec
+	sha256Sum := sha256.Sum256(cert.Raw)
+
+	metadata, err := json.Marshal(certificateMetadata{
+		SerialNumber:      serialHex(cert.SerialNumber),
+		Subject:           cert.Subject.String(),
+		Issuer:            cert.Issuer.String(),
+		SANs:              sans,
+		NotBefore:         cert.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:          cert.NotAfter.UTC().Format(time.RFC3339),
+		SHA1Fingerprint:   hex.EncodeToString(sha1Sum[:]),
+		SHA256Fingerprint: hex.EncodeToString(sha256Sum[:]),
+	})
+	if err != nil {
+		return tfTypes.StringNull()
+	}
+	return tfTypes.StringValue(string(metadata))
+}
+
+// publicKeyFromPEM returns the public key of the first certificate in
+// certPEM as PEM, and the hex SHA-256 of its DER encoding, or nulls if
+// certPEM cannot be parsed.
+func publicKeyFromPEM(certPEM string) (tfTypes.String, tfTypes.String) {
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return tfTypes.StringNull(), tfTypes.StringNull()
+	}
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: cert.RawSubjectPublicKeyInfo})
+	return tfTypes.StringValue(string(publicKeyPEM)), tfTypes.StringValue(keyFingerprint(certPEM))
+}
+
+// derBase64FromPEM returns the first certificate in certPEM as base64
+// encoded DER, or null if certPEM cannot be parsed.
+func derBase64FromPEM(certPEM string) tfTypes.String {
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return tfTypes.StringNull()
+	}
+	return tfTypes.StringValue(base64.StdEncoding.EncodeToString(cert.Raw))
+}
+
+// serialHex formats a serial number the way serial_number and metadata_json
+// report it: lowercase hex without separators.
+func serialHex(serial *big.Int) string {
+	return hex.EncodeToString(serial.Bytes())
+}
+
+// serialFromPEM returns the serial number of the first certificate in
+// certPEM, or null when it cannot be parsed.
+func serialFromPEM(certPEM string) tfTypes.String {
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return tfTypes.StringNull()
+	}
+	return tfTypes.StringValue(serialHex(cert.SerialNumber))
+}
+
+// serialFromDetail converts ACM's colon separated serial number.
+func serialFromDetail(detail *types.CertificateDetail) tfTypes.String {
+	if detail == nil || detail.Serial == nil {
+		return tfTypes.StringNull()
+	}
+	serial, ok := new(big.Int).SetString(strings.ReplaceAll(aws.ToString(detail.Serial), ":", ""), 16)
+	if !ok {
+		return tfTypes.StringNull()
+	}
+	return tfTypes.StringValue(serialHex(serial))
+}
+
+// setACMDetail records when ACM created and last imported the certificate,
+// its ACM type and whether ACM can renew it. Null detail clears them.
+func setACMDetail(data *CertificateResourceModel, detail *types.CertificateDetail) {
+	data.ImportedAt, data.CreatedAt = tfTypes.StringNull(), tfTypes.StringNull()
+	data.ACMType, data.RenewalEligibility = tfTypes.StringNull(), tfTypes.StringNull()
+	if detail == nil {
+		return
+	}
+	if detail.ImportedAt != nil {
+		data.ImportedAt = tfTypes.StringValue(detail.ImportedAt.UTC().Format(time.RFC3339))
+	}
+	if detail.CreatedAt != nil {
+		data.CreatedAt = tfTypes.StringValue(detail.CreatedAt.UTC().Format(time.RFC3339))
+	}
+	if detail.Type != "" {
+		data.ACMType = tfTypes.StringValue(string(detail.Type))
+	}
+	if detail.RenewalEligibility != "" {
+		data.RenewalEligibility = tfTypes.StringValue(string(detail.RenewalEligibility))
+	}
+}
+
+// keyAlgorithmFromDetails returns the key algorithm of the certificates,
+// preferring one that differs from issuedKeyAlgorithm so a single mismatched
+// chunk is not hidden.
+func keyAlgorithmFromDetails(details []*types.CertificateDetail) tfTypes.String {
+	algorithm := tfTypes.StringNull()
+	for _, detail := range details {
+		if detail == nil || detail.KeyAlgorithm == "" {
+			continue
+		}
+		algorithm = tfTypes.StringValue(string(detail.KeyAlgorithm))
+		if detail.KeyAlgorithm != types.KeyAlgorithmEcPrime256v1 {
+			break
+		}
+	}
+	return algorithm
+}
+
+// keyAlgorithmFromPEM names the public key algorithm of the first certificate
+// in certPEM the way ACM does, or returns null when it cannot be parsed.
+func keyAlgorithmFromPEM(certPEM string) tfTypes.String {
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return tfTypes.StringNull()
+	}
+	switch key := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return tfTypes.StringValue(string(types.KeyAlgorithmEcPrime256v1))
+		case elliptic.P384():
+			return tfTypes.StringValue(string(types.KeyAlgorithmEcSecp384r1))
+		case elliptic.P521():
+			return tfTypes.StringValue(string(types.KeyAlgorithmEcSecp521r1))
+		}
+	case *rsa.PublicKey:
+		return tfTypes.StringValue(fmt.Sprintf("RSA_%d", key.N.BitLen()))
+	}
+	return tfTypes.StringNull()
+}
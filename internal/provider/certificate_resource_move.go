@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithMoveState = &CertificateResource{}
+
+// acmCertificateState is the subset of hashicorp/aws aws_acm_certificate state
+// needed to adopt an imported certificate.
+type acmCertificateState struct {
+	Arn                     string   `json:"arn"`
+	DomainName              string   `json:"domain_name"`
+	SubjectAlternativeNames []string `json:"subject_alternative_names"`
+	CertificateBody         string   `json:"certificate_body"`
+	NotAfter                string   `json:"not_after"`
+	Status                  string   `json:"status"`
+}
+
+// MoveState lets moved blocks adopt an aws_acm_certificate, typically one
+// imported from a tls_private_key and a hand-requested origin certificate,
+// without replacing it.
+func (r *CertificateResource) MoveState(ctx context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				if req.SourceTypeName != "aws_acm_certificate" || req.SourceProviderAddress != "registry.terraform.io/hashicorp/aws" {
+					return
+				}
+				if req.SourceRawState == nil {
+					resp.Diagnostics.AddError("Missing Source State", "The aws_acm_certificate state to move was empty.")
+					return
+				}
+
+				var source acmCertificateState
+				if err := json.Unmarshal(req.SourceRawState.JSON, &source); err != nil {
+					resp.Diagnostics.AddError("Failed to decode aws_acm_certificate state", err.Error())
+					return
+				}
+
+				var hostnames []string
+				for _, san := range source.SubjectAlternativeNames {
+					if san != source.DomainName {
+						hostnames = append(hostnames, san)
+					}
+				}
+
+				data := CertificateResourceModel{
+					DomainName:              tfTypes.StringValue(source.DomainName),
+					Hostnames:               tfTypes.ListNull(tfTypes.StringType),
+					KeyBackend:              tfTypes.StringValue(keyBackendACM),
+					CertificateArn:          tfTypes.StringValue(source.Arn),
+					CertificateArns:         certificateArnList([]string{source.Arn}),
+					ReplacedCertificateArns: tfTypes.ListNull(tfTypes.StringType),
+					CertificatePEM:          tfTypes.StringNull(),
+					MetadataJSON:            tfTypes.StringNull(),
+					PublicKeyPEM:            tfTypes.StringNull(),
+					CertificateDERBase64:    tfTypes.StringNull(),
+					KeyFingerprintSHA256:    tfTypes.StringNull(),
+					SerialNumber:            tfTypes.StringNull(),
+					KeyAlgorithm:            tfTypes.StringNull(),
+					ReplaceOnDrift:          tfTypes.BoolNull(),
+					ReimportIfDeleted:       tfTypes.BoolNull(),
+					CheckRevocation:         tfTypes.BoolNull(),
+					VerifyZone:              tfTypes.BoolNull(),
+					CheckDNSRecords:         tfTypes.BoolNull(),
+					NotificationTopicArn:    tfTypes.StringNull(),
+					CertificateStatus:       tfTypes.StringNull(),
+					Origin:                  tfTypes.StringValue(originImported),
+					CloudflareStatus:        tfTypes.StringNull(),
+					KMSKeyArn:               tfTypes.StringNull(),
+					PKCS11KeyID:             tfTypes.StringNull(),
+					VaultKVPath:             tfTypes.StringNull(),
+					SecretsManagerSecretID:  tfTypes.StringNull(),
+					SSMParameterName:        tfTypes.StringNull(),
+					S3ObjectURI:             tfTypes.StringNull(),
+					RotationPolicy:          tfTypes.ObjectNull(rotationPolicyAttrTypes()),
+					Tags:                    tfTypes.MapNull(tfTypes.StringType),
+					TagsAll:                 tfTypes.MapNull(tfTypes.StringType),
+					DeleteWaitForUnused:     tfTypes.StringValue(defaultDeleteWaitForUnused),
+					WaitForInUse:            tfTypes.StringNull(),
+					AdoptionStrategy:        tfTypes.StringValue(adoptionStrategyNewest),
+					Alias:                   tfTypes.StringNull(),
+					AccountID:               tfTypes.StringNull(),
+					RenewalTags:             tfTypes.BoolNull(),
+					AssumeRole:              tfTypes.ObjectNull(assumeRoleAttrTypes()),
+					Retry:                   tfTypes.ObjectNull(retryAttrTypes()),
+					ExpiresAt:               tfTypes.StringNull(),
+					ImportedAt:              tfTypes.StringNull(),
+					CreatedAt:               tfTypes.StringNull(),
+					ACMType:                 tfTypes.StringNull(),
+					RenewalEligibility:      tfTypes.StringNull(),
+					ID:                      tfTypes.StringValue(source.Arn),
+				}
+				if len(hostnames) > 0 {
+					list, diags := tfTypes.ListValueFrom(ctx, tfTypes.StringType, hostnames)
+					resp.Diagnostics.Append(diags...)
+					data.Hostnames = list
+				}
+				if source.CertificateBody != "" {
+					data.CertificatePEM = tfTypes.StringValue(source.CertificateBody)
+					data.MetadataJSON = metadataFromPEM(source.CertificateBody)
+					data.PublicKeyPEM, data.KeyFingerprintSHA256 = publicKeyFromPEM(source.CertificateBody)
+					data.CertificateDERBase64 = derBase64FromPEM(source.CertificateBody)
+					data.SerialNumber = serialFromPEM(source.CertificateBody)
+					data.KeyAlgorithm = keyAlgorithmFromPEM(source.CertificateBody)
+				}
+				if source.Status != "" {
+					data.CertificateStatus = tfTypes.StringValue(source.Status)
+				}
+				if source.NotAfter != "" {
+					data.ExpiresAt = tfTypes.StringValue(source.NotAfter)
+				}
+
+				resp.Diagnostics.Append(resp.TargetState.Set(ctx, &data)...)
+			},
+		},
+	}
+}
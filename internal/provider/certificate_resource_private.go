@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// issuanceMetadataKey is the private state key holding issuanceMetadata.
+const issuanceMetadataKey = "issuance"
+
+// replacedArnsKey is the private state key carrying the certificate ARNs of
+// an instance being replaced. Terraform hands the private state from the
+// replacement plan to the plan for the new instance, so ModifyPlan can
+// record them as its replaced_certificate_arns.
+const replacedArnsKey = "replaced_certificate_arns"
+
+// issuanceMetadata records details of the last issuance that are useful for
+// renewal and drift checks but not worth exposing as attributes.
+type issuanceMetadata struct {
+	IssuedAt                 string   `json:"issued_at"`
+	CloudflareCertificateIDs []string `json:"cloudflare_certificate_ids"`
+	// KeyFingerprint is the SHA-256 of the first certificate's
+	// SubjectPublicKeyInfo, in hex.
+	KeyFingerprint string `json:"key_fingerprint"`
+	// Serial is the first certificate's serial number, formatted as
+	// serial_number is.
+	Serial string `json:"serial,omitempty"`
+}
+
+// privateState is satisfied by the framework's request and response private
+// state fields.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+func newIssuanceMetadata(certPEM string, cloudflareIDs []string) issuanceMetadata {
+	return issuanceMetadata{
+		IssuedAt:                 time.Now().UTC().Format(time.RFC3339),
+		CloudflareCertificateIDs: cloudflareIDs,
+		KeyFingerprint:           keyFingerprint(certPEM),
+		Serial:                   serialFromPEM(certPEM).ValueString(),
+	}
+}
+
+func setIssuanceMetadata(ctx context.Context, private privateState, meta issuanceMetadata) diag.Diagnostics {
+	value, err := json.Marshal(meta)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to encode issuance metadata", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, issuanceMetadataKey, value)
+}
+
+// getIssuanceMetadata returns the recorded metadata, or nil for resources
+// created before it was tracked.
+func getIssuanceMetadata(ctx context.Context, private privateState) (*issuanceMetadata, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, issuanceMetadataKey)
+	if diags.HasError() || len(value) == 0 {
+		return nil, diags
+	}
+	var meta issuanceMetadata
+	if err := json.Unmarshal(value, &meta); err != nil {
+		diags.AddError("Failed to decode issuance metadata", err.Error())
+		return nil, diags
+	}
+	return &meta, diags
+}
+
+// keyFingerprint returns the hex SHA-256 of a certificate's public key, or
+// "" if certPEM cannot be parsed.
+func keyFingerprint(certPEM string) string {
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:])
+}
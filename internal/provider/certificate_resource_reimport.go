@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// certificateStatusDeleted is the certificate_status Read records when
+// reimport_if_deleted is set and the ACM certificate was deleted outside
+// Terraform, so the next apply re-imports it instead of the resource being
+// dropped from state and issued anew.
+const certificateStatusDeleted = "DELETED"
+
+// reimportable reports whether a certificate that is gone from ACM can be
+// restored from its key store: reimport_if_deleted and a key store are set,
+// and the certificate has neither expired nor been revoked by Cloudflare.
+func (r *CertificateResource) reimportable(ctx context.Context, data *CertificateResourceModel, private privateState, diags *diag.Diagnostics) bool {
+	keyStore, _ := r.clients.keyStore(data.keyStoreLocations())
+	if !data.ReimportIfDeleted.ValueBool() || keyStore == nil || keyStore.Check() != nil {
+		return false
+	}
+	if expires, err := time.Parse(time.RFC3339, data.ExpiresAt.ValueString()); err != nil || time.Now().After(expires) {
+		return false
+	}
+	r.readCloudflareStatus(ctx, data, private, diags)
+	return data.CloudflareStatus.ValueString() != cloudflareStatusRevoked
+}
+
+// reimportFromKeyStore imports the certificates and keys stored in the
+// resource's key store into ACM again, for each ARN in state that no longer
+// exists. Certificates that still exist keep their ARN. The stored entry is
+// updated with the new ARNs.
+func (r *CertificateResource) reimportFromKeyStore(ctx context.Context, data *CertificateResourceModel, state CertificateResourceModel, diags *diag.Diagnostics) {
+	keyStore, location := r.clients.keyStore(data.keyStoreLocations())
+	secret, err := keyStore.Read(ctx, location)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to read certificate from %s", keyStore.Name()), err.Error())
+		return
+	}
+
+	var arns []string
+	diags.Append(state.CertificateArns.ElementsAs(ctx, &arns, false)...)
+	tags := map[string]string{}
+	diags.Append(data.TagsAll.ElementsAs(ctx, &tags, false)...)
+	if diags.HasError() {
+		return
+	}
+	importTags := r.clients.ownershipTags(data.DomainName.ValueString(), data.Alias.ValueString())
+	maps.Copy(importTags, tags)
+
+	for i, arn := range arns {
+		suffix := ""
+		if i > 0 {
+			suffix = fmt.Sprintf("_%d", i)
+		}
+		_, err := r.clients.ACMClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+			CertificateArn: aws.String(arn),
+		})
+		if err == nil {
+			continue
+		}
+		if !isResourceNotFoundError(err) {
+			diags.AddError("Failed to describe certificate", err.Error())
+			return
+		}
+
+		certPEM := secret["certificate"+suffix]
+		keyPEM := []byte(secret["private_key"+suffix])
+		if certPEM == "" || len(keyPEM) == 0 {
+			diags.AddError(
+				fmt.Sprintf("Certificate Not Found in %s", keyStore.Name()),
+				fmt.Sprintf("%s does not contain the certificate and private key for %s, so it cannot be re-imported. "+
+					"Replace the resource to issue a new certificate.", location, arn),
+			)
+			return
+		}
+		output, err := r.clients.ACMClient.ImportCertificate(ctx, &acm.ImportCertificateInput{
+			Certificate: []byte(certPEM),
+			PrivateKey:  keyPEM,
+			Tags:        acmTags(importTags),
+		})
+		zeroize(keyPEM)
+		if err != nil {
+			diags.AddError("Failed to re-import certificate", fmt.Sprintf("re-importing %s to ACM: %s", arn, err))
+			return
+		}
+		r.clients.CertificateList.invalidate()
+		arns[i] = aws.ToString(output.CertificateArn)
+		secret["certificate_arn"+suffix] = arns[i]
+		diags.AddWarning(
+			"Certificate Re-imported",
+			fmt.Sprintf("%s was deleted from ACM outside Terraform; the certificate and key stored at %s were imported again as %s.",
+				arn, location, arns[i]),
+		)
+	}
+
+	if err := keyStore.Write(ctx, location, secret); err != nil {
+		diags.AddWarning(fmt.Sprintf("Failed to update certificate ARNs in %s", keyStore.Name()), err.Error())
+	}
+	data.CertificateArn = tfTypes.StringValue(arns[0])
+	data.CertificateArns = certificateArnList(arns)
+	data.CertificateStatus = tfTypes.StringValue(string(types.CertificateStatusIssued))
+	data.ID = tfTypes.StringValue(arns[0])
+	r.readACMDetail(ctx, data, diags)
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// reimportACM is a fakeACM in which the ARNs in deleted do not exist.
+type reimportACM struct {
+	fakeACM
+	deleted  map[string]bool
+	imported []*acm.ImportCertificateInput
+}
+
+func (f *reimportACM) DescribeCertificate(_ context.Context, params *acm.DescribeCertificateInput, _ ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	if f.deleted[*params.CertificateArn] {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("not found")}
+	}
+	return &acm.DescribeCertificateOutput{Certificate: &types.CertificateDetail{CertificateArn: params.CertificateArn}}, nil
+}
+
+func (f *reimportACM) ImportCertificate(_ context.Context, params *acm.ImportCertificateInput, _ ...func(*acm.Options)) (*acm.ImportCertificateOutput, error) {
+	f.imported = append(f.imported, params)
+	return &acm.ImportCertificateOutput{CertificateArn: aws.String("arn:aws:acm:us-east-1:123456789012:certificate/reimported")}, nil
+}
+
+func TestReimportFromVault(t *testing.T) {
+	clients := newTestClients(cloudflaretest.NewServer(t))
+	cert, keyPEM, err := clients.issueWithLocalKey(context.Background(), []string{"example.com"})
+	if err != nil {
+		t.Fatalf("issueWithLocalKey: %v", err)
+	}
+
+	const deletedArn = "arn:aws:acm:us-east-1:123456789012:certificate/deleted"
+	secret := map[string]string{
+		"domain_name":     "example.com",
+		"certificate_arn": deletedArn,
+		"certificate":     cert.Certificate,
+		"private_key":     string(keyPEM),
+	}
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/example" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodPost {
+			var body struct {
+				Data map[string]string `json:"data"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			secret = body.Data
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": secret}})
+	}))
+	t.Cleanup(vault.Close)
+
+	fake := &reimportACM{deleted: map[string]bool{deletedArn: true}}
+	clients.ACMClient = fake
+	clients.VaultClient = &vaultClient{Address: vault.URL, Token: "test-token", httpClient: vault.Client()}
+	r := &CertificateResource{clients: clients}
+
+	state := CertificateResourceModel{
+		DomainName:        tfTypes.StringValue("example.com"),
+		CertificateArn:    tfTypes.StringValue(deletedArn),
+		CertificateArns:   certificateArnList([]string{deletedArn}),
+		CertificateStatus: tfTypes.StringValue(certificateStatusDeleted),
+		VaultKVPath:       tfTypes.StringValue("secret/example"),
+		TagsAll:           tfTypes.MapValueMust(tfTypes.StringType, nil),
+	}
+	data := state
+	var diags diag.Diagnostics
+	r.reimportFromKeyStore(context.Background(), &data, state, &diags)
+	if diags.HasError() {
+		t.Fatalf("reimportFromKeyStore: %v", diags)
+	}
+
+	if len(fake.imported) != 1 {
+		t.Fatalf("imported %d certificates, want 1", len(fake.imported))
+	}
+	if got := string(fake.imported[0].Certificate); got != cert.Certificate {
+		t.Error("re-imported a different certificate from the one stored in Vault")
+	}
+	const want = "arn:aws:acm:us-east-1:123456789012:certificate/reimported"
+	if got := data.CertificateArn.ValueString(); got != want {
+		t.Errorf("certificate_arn = %q, want %q", got, want)
+	}
+	if got := data.CertificateStatus.ValueString(); got != string(types.CertificateStatusIssued) {
+		t.Errorf("certificate_status = %q, want ISSUED", got)
+	}
+	if got := secret["certificate_arn"]; got != want {
+		t.Errorf("Vault certificate_arn = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,441 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ModifyPlan plans tags_all, defers creation while the hostnames are unknown,
+// replaces certificates with a key this provider would not issue, replaces
+// certificates that drifted when replace_on_drift is set, and plans
+// an in-place renewal when the certificate has entered its rotation policy's
+// renewal window.
+func (r *CertificateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan CertificateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.planTagsAll(ctx, plan, resp)
+
+	if req.State.Raw.IsNull() {
+		r.planReplacedArns(ctx, req, resp)
+		r.deferUnknownHostnames(ctx, req, resp)
+		return
+	}
+
+	var state CertificateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.recordReplacedArns(ctx, state, resp)
+
+	if !state.KeyAlgorithm.IsNull() && state.KeyAlgorithm.ValueString() != issuedKeyAlgorithm {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("key_algorithm"), issuedKeyAlgorithm)...)
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("key_algorithm"))
+		return
+	}
+
+	if state.CertificateStatus.ValueString() == certificateStatusDeleted && plan.ReimportIfDeleted.ValueBool() {
+		r.planReimport(ctx, resp)
+		return
+	}
+
+	if plan.ReplaceOnDrift.ValueBool() {
+		meta, diags := getIssuanceMetadata(ctx, req.Private)
+		resp.Diagnostics.Append(diags...)
+		if meta != nil && meta.Serial != "" && !state.SerialNumber.IsNull() && state.SerialNumber.ValueString() != meta.Serial {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("serial_number"))
+			return
+		}
+	}
+
+	if plan.RotationPolicy.IsNull() || plan.RotationPolicy.IsUnknown() || state.ExpiresAt.IsNull() {
+		return
+	}
+	policy, diags := rotationPolicyFromObject(ctx, plan.RotationPolicy)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !policy.renewalDue(certificateIssuedAt(state), state.ExpiresAt.ValueString()) {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("expires_at"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certificate_pem"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("metadata_json"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("public_key_pem"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("key_fingerprint_sha256"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certificate_der_base64"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("serial_number"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("cloudflare_status"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("origin"), originIssued)...)
+	if state.KeyBackend.ValueString() == keyBackendACM {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certificate_status"), tfTypes.StringUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("imported_at"), tfTypes.StringUnknown())...)
+	}
+	if policy.RotateKeyOnRenew.ValueBool() {
+		switch state.KeyBackend.ValueString() {
+		case keyBackendKMS:
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("kms_key_arn"), tfTypes.StringUnknown())...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), tfTypes.StringUnknown())...)
+		case keyBackendPKCS11:
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("pkcs11_key_id"), tfTypes.StringUnknown())...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), tfTypes.StringUnknown())...)
+		}
+	}
+}
+
+// certificateIssuedAt returns when the certificate in data was issued: its
+// NotBefore when certificate_pem is known, otherwise when ACM imported or
+// created it, as for certificates adopted or imported without their PEM. It
+// is zero when none of them are known.
+func certificateIssuedAt(data CertificateResourceModel) time.Time {
+	if cert, err := parseCertificatePEM(data.CertificatePEM.ValueString()); err == nil {
+		return cert.NotBefore
+	}
+	for _, value := range []tfTypes.String{data.ImportedAt, data.CreatedAt} {
+		if issuedAt, err := time.Parse(time.RFC3339, value.ValueString()); err == nil {
+			return issuedAt
+		}
+	}
+	return time.Time{}
+}
+
+// planReimport plans the new ARNs of a certificate Read found deleted from
+// ACM, which Update re-imports from the key store.
+func (r *CertificateResource) planReimport(ctx context.Context, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certificate_arn"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certificate_arns"), tfTypes.ListUnknown(tfTypes.StringType))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certificate_status"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("imported_at"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("created_at"), tfTypes.StringUnknown())...)
+}
+
+// recordReplacedArns saves the certificate ARNs in state to the planned
+// private state, in case this plan turns out to be a replacement.
+func (r *CertificateResource) recordReplacedArns(ctx context.Context, state CertificateResourceModel, resp *resource.ModifyPlanResponse) {
+	arns := []string{}
+	if !state.CertificateArns.IsNull() {
+		resp.Diagnostics.Append(state.CertificateArns.ElementsAs(ctx, &arns, false)...)
+	} else if state.CertificateArn.ValueString() != "" {
+		arns = append(arns, state.CertificateArn.ValueString())
+	}
+	value, err := json.Marshal(arns)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode replaced certificate ARNs", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, replacedArnsKey, value)...)
+}
+
+// planReplacedArns plans replaced_certificate_arns for a new instance from
+// the ARNs recordReplacedArns saved when planning the replacement, or null
+// when this is not a replacement.
+func (r *CertificateResource) planReplacedArns(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	replaced := tfTypes.ListNull(tfTypes.StringType)
+	value, diags := req.Private.GetKey(ctx, replacedArnsKey)
+	resp.Diagnostics.Append(diags...)
+	var arns []string
+	if len(value) > 0 && json.Unmarshal(value, &arns) == nil && len(arns) > 0 {
+		replaced = certificateArnList(arns)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("replaced_certificate_arns"), replaced)...)
+}
+
+// deferUnknownHostnames defers a new certificate whose domain_name or
+// hostnames are not yet known, such as when they come from a DNS zone created
+// in the same run. Without this the plan cannot say whether an existing ACM
+// certificate will be adopted or how many certificates will be issued.
+func (r *CertificateResource) deferUnknownHostnames(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if !req.ClientCapabilities.DeferralAllowed {
+		return
+	}
+
+	var plan CertificateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	unknown := plan.DomainName.IsUnknown() || plan.Hostnames.IsUnknown()
+	for _, hostname := range plan.Hostnames.Elements() {
+		unknown = unknown || hostname.IsUnknown()
+	}
+	if unknown {
+		resp.Deferred = &resource.Deferred{Reason: resource.DeferredReasonResourceConfigUnknown}
+	}
+}
+
+// renewWithPolicy renews the certificate as its rotation policy directs and
+// notifies the policy's targets.
+func (r *CertificateResource) renewWithPolicy(ctx context.Context, data *CertificateResourceModel, state CertificateResourceModel, private privateState, diags *diag.Diagnostics) {
+	policy, d := rotationPolicyFromObject(ctx, data.RotationPolicy)
+	diags.Append(d...)
+	if diags.HasError() {
+		return
+	}
+
+	r.renew(ctx, data, state, policy.RotateKeyOnRenew.ValueBool(), private, diags)
+	if diags.HasError() {
+		return
+	}
+
+	r.publishLifecycleEvent(ctx, lifecycleRenewed, *data, private, diags)
+
+	targets, d := policy.notificationTargets(ctx)
+	diags.Append(d...)
+	subject, message := renewalNotification(data.DomainName.ValueString(), data.ID.ValueString(), data.ExpiresAt.ValueString())
+	for _, topicArn := range targets {
+		if err := r.clients.SNSClient.publish(ctx, topicArn, subject, message); err != nil {
+			diags.AddWarning("Failed to send renewal notification", fmt.Sprintf("Publishing to %s failed: %s", topicArn, err))
+		}
+	}
+}
+
+// renew issues a replacement certificate for the resource. ACM backed
+// certificates are re-imported into their existing ARN so attachments keep
+// working. It depends only on the resource's own attributes so it can also
+// back an on-demand renew action once the plugin framework supports them.
+func (r *CertificateResource) renew(ctx context.Context, data *CertificateResourceModel, state CertificateResourceModel, rotateKey bool, private privateState, diags *diag.Diagnostics) {
+	domainName := data.DomainName.ValueString()
+
+	var certPEM string
+	var cloudflareIDs []string
+	// partial is set when only some of the hostname chunks were renewed.
+	var partial bool
+	switch state.KeyBackend.ValueString() {
+	case keyBackendKMS:
+		keyArn := state.KMSKeyArn.ValueString()
+		if rotateKey {
+			key, err := r.clients.KMSClient.createSigningKey(ctx, "Cloudflare Origin Certificate key for "+domainName)
+			if err != nil {
+				diags.AddError("Failed to create KMS key", err.Error())
+				return
+			}
+			keyArn = key.Arn
+		}
+		signer, err := r.clients.KMSClient.signer(ctx, keyArn)
+		if err != nil {
+			diags.AddError("Failed to load KMS public key", err.Error())
+		}
+		var cert cloudflare.OriginCert
+		var ok bool
+		if err == nil {
+			cert, ok = r.issueWithSigner(ctx, *data, signer, diags)
+		}
+		if !ok {
+			if keyArn != state.KMSKeyArn.ValueString() {
+				r.discardKMSKey(ctx, keyArn, diags)
+			}
+			return
+		}
+		if keyArn != state.KMSKeyArn.ValueString() {
+			r.discardKMSKey(ctx, state.KMSKeyArn.ValueString(), diags)
+		}
+		certPEM = cert.Certificate
+		cloudflareIDs = []string{cert.ID}
+		data.KMSKeyArn = tfTypes.StringValue(keyArn)
+		data.ID = tfTypes.StringValue(keyArn)
+
+	case keyBackendPKCS11:
+		keyID := state.PKCS11KeyID.ValueString()
+		if rotateKey {
+			newID, err := r.clients.PKCS11Client.generateKey(ctx, "cfcert-"+domainName)
+			if err != nil {
+				diags.AddError("Failed to generate PKCS#11 key pair", err.Error())
+				return
+			}
+			keyID = newID
+		}
+		signer, err := r.clients.PKCS11Client.signer(ctx, keyID)
+		if err != nil {
+			diags.AddError("Failed to load PKCS#11 public key", err.Error())
+		}
+		var cert cloudflare.OriginCert
+		var ok bool
+		if err == nil {
+			cert, ok = r.issueWithSigner(ctx, *data, signer, diags)
+		}
+		if !ok {
+			if keyID != state.PKCS11KeyID.ValueString() {
+				r.discardPKCS11Key(ctx, keyID, diags)
+			}
+			return
+		}
+		if keyID != state.PKCS11KeyID.ValueString() {
+			r.discardPKCS11Key(ctx, state.PKCS11KeyID.ValueString(), diags)
+		}
+		certPEM = cert.Certificate
+		cloudflareIDs = []string{cert.ID}
+		data.PKCS11KeyID = tfTypes.StringValue(keyID)
+		data.ID = tfTypes.StringValue("pkcs11:" + keyID)
+
+	default:
+		chunks, d := r.hostnameChunks(ctx, *data)
+		diags.Append(d...)
+		arns := []string{state.CertificateArn.ValueString()}
+		if !state.CertificateArns.IsNull() {
+			diags.Append(state.CertificateArns.ElementsAs(ctx, &arns, false)...)
+		}
+		if diags.HasError() {
+			return
+		}
+		if len(arns) != len(chunks) {
+			diags.AddError(
+				"Failed to renew certificate",
+				fmt.Sprintf("State holds %d certificates but hostnames need %d; replace the resource to re-split them.", len(arns), len(chunks)),
+			)
+			return
+		}
+		// A failed chunk leaves the others renewed. They are saved below,
+		// and expires_at keeps its old value so the failed ones are retried
+		// on the next apply.
+		issued, err := r.importChunks(ctx, chunks, arns, nil)
+		defer zeroizeIssued(issued)
+		if isLimitExceededError(err) {
+			if advice := r.clients.importQuotaAdvice(ctx); advice != "" {
+				err = fmt.Errorf("%w\n\n%s", err, advice)
+			}
+		}
+		if err != nil {
+			diags.AddError("Failed to re-import certificate to ACM", err.Error())
+			if len(issued) == 0 {
+				return
+			}
+		}
+		renewed := renewedChunks(arns, issued)
+		certPEM = state.CertificatePEM.ValueString()
+		if renewed[0] != nil {
+			certPEM = renewed[0].certPEM
+		}
+		data.CertificateStatus = tfTypes.StringValue(string(types.CertificateStatusIssued))
+		r.readACMDetail(ctx, data, diags)
+		var previousIDs []string
+		if meta, d := getIssuanceMetadata(ctx, private); !d.HasError() && meta != nil {
+			previousIDs = meta.CloudflareCertificateIDs
+		}
+		for i, cert := range renewed {
+			switch {
+			case cert != nil:
+				cloudflareIDs = append(cloudflareIDs, cert.cloudflareID)
+			case i < len(previousIDs):
+				cloudflareIDs = append(cloudflareIDs, previousIDs[i])
+			default:
+				cloudflareIDs = append(cloudflareIDs, "")
+			}
+		}
+		partial = len(issued) < len(chunks)
+		if keyStore, location := r.clients.keyStore(data.keyStoreLocations()); keyStore != nil {
+			stored := keyStoreData(domainName, issued)
+			var storeErr error
+			if partial {
+				stored, storeErr = keyStore.Read(ctx, location)
+				if storeErr == nil {
+					mergeRenewedKeyStoreData(stored, renewed)
+				}
+			}
+			if storeErr == nil {
+				storeErr = keyStore.Write(ctx, location, stored)
+			}
+			if storeErr != nil {
+				diags.AddError(fmt.Sprintf("Failed to write renewed certificate to %s", keyStore.Name()), storeErr.Error())
+			}
+		}
+	}
+
+	data.CertificatePEM = tfTypes.StringValue(certPEM)
+	data.Origin = tfTypes.StringValue(originIssued)
+	data.MetadataJSON = metadataFromPEM(certPEM)
+	data.PublicKeyPEM, data.KeyFingerprintSHA256 = publicKeyFromPEM(certPEM)
+	data.CertificateDERBase64 = derBase64FromPEM(certPEM)
+	data.ExpiresAt = expiryFromPEM(certPEM)
+	if partial {
+		data.ExpiresAt = state.ExpiresAt
+	}
+	data.SerialNumber = serialFromPEM(certPEM)
+	data.CloudflareStatus = tfTypes.StringValue(cloudflareStatusActive)
+	diags.Append(setIssuanceMetadata(ctx, private, newIssuanceMetadata(certPEM, cloudflareIDs))...)
+}
+
+// renewedChunks returns the certificate importChunks renewed into each of
+// arns, or nil for those that failed.
+func renewedChunks(arns []string, issued []issuedCertificate) []*issuedCertificate {
+	renewed := make([]*issuedCertificate, len(arns))
+	for i := range issued {
+		if index := slices.Index(arns, issued[i].arn); index >= 0 {
+			renewed[index] = &issued[i]
+		}
+	}
+	return renewed
+}
+
+// mergeRenewedKeyStoreData replaces the certificates in a key store entry
+// laid out by keyStoreData with those that were renewed, keeping the others.
+func mergeRenewedKeyStoreData(stored map[string]string, renewed []*issuedCertificate) {
+	for i, cert := range renewed {
+		if cert == nil {
+			continue
+		}
+		suffix := ""
+		if i > 0 {
+			suffix = fmt.Sprintf("_%d", i)
+		}
+		stored["certificate_arn"+suffix] = cert.arn
+		stored["certificate"+suffix] = cert.certPEM
+		stored["private_key"+suffix] = string(cert.keyPEM)
+	}
+}
+
+// warnIfExpiring adds a warning when expiresAt (RFC 3339) falls within the
+// provider's expiry_warning_days, so routine plans surface upcoming renewals.
+func (c *ProviderClients) warnIfExpiring(diags *diag.Diagnostics, name, expiresAt string) {
+	if c.ExpiryWarningDays <= 0 || expiresAt == "" {
+		return
+	}
+	expires, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return
+	}
+	remaining := time.Until(expires)
+	if remaining > time.Duration(c.ExpiryWarningDays)*24*time.Hour {
+		return
+	}
+	if remaining <= 0 {
+		diags.AddWarning("Certificate Expired", fmt.Sprintf("The certificate for %s expired at %s.", name, expiresAt))
+		return
+	}
+	diags.AddWarning(
+		"Certificate Expiring Soon",
+		fmt.Sprintf("The certificate for %s expires at %s, in %d days.", name, expiresAt, int(remaining.Hours()/24)),
+	)
+}
+
+func expiryFromPEM(certPEM string) tfTypes.String {
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return tfTypes.StringNull()
+	}
+	return tfTypes.StringValue(cert.NotAfter.UTC().Format(time.RFC3339))
+}
+
+func expiryFromDetail(detail *types.CertificateDetail) tfTypes.String {
+	if detail == nil || detail.NotAfter == nil {
+		return tfTypes.StringNull()
+	}
+	return tfTypes.StringValue(detail.NotAfter.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// failingImportACM is a fakeACM whose re-imports into failArn fail.
+type failingImportACM struct {
+	fakeACM
+	failArn string
+}
+
+func (f *failingImportACM) ImportCertificate(_ context.Context, params *acm.ImportCertificateInput, _ ...func(*acm.Options)) (*acm.ImportCertificateOutput, error) {
+	if aws.ToString(params.CertificateArn) == f.failArn {
+		return nil, errors.New("import failed")
+	}
+	return &acm.ImportCertificateOutput{CertificateArn: params.CertificateArn}, nil
+}
+
+func (f *failingImportACM) DescribeCertificate(_ context.Context, params *acm.DescribeCertificateInput, _ ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	return &acm.DescribeCertificateOutput{Certificate: &types.CertificateDetail{CertificateArn: params.CertificateArn}}, nil
+}
+
+func TestRenewPartialFailure(t *testing.T) {
+	const (
+		firstArn  = "arn:aws:acm:us-east-1:123456789012:certificate/first"
+		secondArn = "arn:aws:acm:us-east-1:123456789012:certificate/second"
+	)
+	clients := newTestClients(cloudflaretest.NewServer(t))
+	hostnames := make([]string, 100)
+	for i := range hostnames {
+		hostnames[i] = fmt.Sprintf("host%d.example.com", i)
+	}
+	chunks := hostnameChunks("example.com", hostnames)
+	if len(chunks) != 2 {
+		t.Fatalf("hostnames split into %d chunks, want 2", len(chunks))
+	}
+	ctx := context.Background()
+	old, oldKey, err := clients.issueWithLocalKey(ctx, chunks[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := map[string]string{
+		"domain_name":       "example.com",
+		"certificate_arn":   firstArn,
+		"certificate":       old.Certificate,
+		"private_key":       string(oldKey),
+		"certificate_arn_1": secondArn,
+		"certificate_1":     "old second certificate",
+		"private_key_1":     "old second key",
+	}
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var body struct {
+				Data map[string]string `json:"data"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			secret = body.Data
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": secret}})
+	}))
+	t.Cleanup(vault.Close)
+	clients.VaultClient = &vaultClient{Address: vault.URL, Token: "test-token", httpClient: vault.Client()}
+	clients.ACMClient = &failingImportACM{failArn: secondArn}
+	r := &CertificateResource{clients: clients}
+
+	hostnameValues, _ := tfTypes.ListValueFrom(ctx, tfTypes.StringType, hostnames)
+	state := CertificateResourceModel{
+		DomainName:      tfTypes.StringValue("example.com"),
+		Hostnames:       hostnameValues,
+		KeyBackend:      tfTypes.StringValue(keyBackendACM),
+		CertificateArn:  tfTypes.StringValue(firstArn),
+		CertificateArns: certificateArnList([]string{firstArn, secondArn}),
+		CertificatePEM:  tfTypes.StringValue(old.Certificate),
+		ExpiresAt:       tfTypes.StringValue("2020-01-01T00:00:00Z"),
+		VaultKVPath:     tfTypes.StringValue("secret/example"),
+	}
+	data := state
+	var diags diag.Diagnostics
+	r.renew(ctx, &data, state, false, memoryPrivateState{}, &diags)
+
+	if !diags.HasError() {
+		t.Fatal("renew did not report the failed chunk")
+	}
+	if secret["certificate"] == old.Certificate || secret["certificate_1"] != "old second certificate" {
+		t.Error("the key store does not hold the renewed first certificate and the unchanged second one")
+	}
+	if err := verifyKeyPair(secret["certificate"], []byte(secret["private_key"])); err != nil {
+		t.Errorf("stored private key does not match the renewed certificate: %v", err)
+	}
+	if data.CertificatePEM.ValueString() != secret["certificate"] {
+		t.Error("certificate_pem is not the renewed first certificate")
+	}
+	if !data.ExpiresAt.Equal(state.ExpiresAt) {
+		t.Errorf("expires_at = %s, want the old %s so the failed chunk is retried", data.ExpiresAt, state.ExpiresAt)
+	}
+}
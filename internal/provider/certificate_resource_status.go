@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	cloudflareStatusActive  = "active"
+	cloudflareStatusRevoked = "revoked"
+)
+
+// acmStatus reports the status of the least healthy certificate, so a single
+// expired or revoked chunk is not hidden behind the first one.
+func acmStatus(details []*types.CertificateDetail) tfTypes.String {
+	status := tfTypes.StringNull()
+	for _, detail := range details {
+		if detail == nil {
+			continue
+		}
+		if detail.Status != types.CertificateStatusIssued {
+			return tfTypes.StringValue(string(detail.Status))
+		}
+		status = tfTypes.StringValue(string(detail.Status))
+	}
+	return status
+}
+
+// readCloudflareStatus refreshes cloudflare_status from the Cloudflare
+// certificate IDs recorded at issuance. Certificates adopted from ACM or
+// issued before the IDs were recorded keep a null status. Revocation is
+// permanent, so revoked certificates are not looked up again.
+func (r *CertificateResource) readCloudflareStatus(ctx context.Context, data *CertificateResourceModel, private privateState, diags *diag.Diagnostics) {
+	if data.CloudflareStatus.ValueString() == cloudflareStatusRevoked {
+		return
+	}
+	meta, d := getIssuanceMetadata(ctx, private)
+	diags.Append(d...)
+	if meta == nil || len(meta.CloudflareCertificateIDs) == 0 {
+		return
+	}
+
+	status := cloudflareStatusActive
+	for _, id := range meta.CloudflareCertificateIDs {
+		cert, err := r.clients.getCloudflareOriginCert(ctx, id)
+		if err != nil {
+			diags.AddWarning("Failed to check Cloudflare certificate status", err.Error())
+			return
+		}
+		if cert.RevokedAt != "" {
+			status = cloudflareStatusRevoked
+		}
+	}
+	data.CloudflareStatus = tfTypes.StringValue(status)
+}
+
+// checkRevocation warns when check_revocation is set and the certificate's
+// CRL lists it as revoked, which ACM does not notice.
+func (r *CertificateResource) checkRevocation(ctx context.Context, data CertificateResourceModel, diags *diag.Diagnostics) {
+	if !data.CheckRevocation.ValueBool() {
+		return
+	}
+
+	certPEM := data.CertificatePEM.ValueString()
+	if certPEM == "" && data.CertificateArn.ValueString() != "" {
+		output, err := r.clients.ACMClient.GetCertificate(ctx, &acm.GetCertificateInput{
+			CertificateArn: aws.String(data.CertificateArn.ValueString()),
+		})
+		if err != nil {
+			diags.AddWarning("Failed to check certificate revocation", err.Error())
+			return
+		}
+		certPEM = aws.ToString(output.Certificate)
+	}
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		diags.AddWarning("Failed to check certificate revocation", err.Error())
+		return
+	}
+
+	at, err := revokedAt(ctx, cert)
+	if err != nil {
+		diags.AddWarning("Failed to check certificate revocation", err.Error())
+		return
+	}
+	if !at.IsZero() {
+		diags.AddWarning(
+			"Certificate Revoked",
+			fmt.Sprintf("The certificate for %s (serial %s) was revoked on %s according to its CRL, so clients that check "+
+				"revocation will reject it. Replace the resource to issue a new certificate.",
+				data.DomainName.ValueString(), serialHex(cert.SerialNumber), at.UTC().Format(time.RFC3339)),
+		)
+	}
+}
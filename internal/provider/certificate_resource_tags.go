@@ -0,0 +1,292 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// ownershipTagPrefix marks tag keys reserved for the provider.
+	ownershipTagPrefix = "cfcert:"
+	// ownershipMarkerTag is set on every certificate the provider imports.
+	// Only certificates carrying it are adopted or found by lookups, so
+	// certificates managed by other tooling are never taken over.
+	ownershipMarkerTag = ownershipTagPrefix + "managed"
+	ownershipDomainTag = ownershipTagPrefix + "domain"
+	// ownershipWorkspaceTag is only set when the provider has a workspace.
+	ownershipWorkspaceTag = ownershipTagPrefix + "workspace"
+	// ownershipAliasTag is only set when the resource has an alias. Adoption
+	// only considers certificates with the same alias, or none.
+	ownershipAliasTag = ownershipTagPrefix + "alias"
+	// renewalExpiresAtTag and renewalRenewAfterTag are only set when the
+	// resource has renewal_tags, for schedulers that act on them.
+	renewalExpiresAtTag  = ownershipTagPrefix + "expires-at"
+	renewalRenewAfterTag = ownershipTagPrefix + "renew-after"
+)
+
+// maxACMTagValueLength is the longest tag value ACM accepts.
+const maxACMTagValueLength = 256
+
+func isOwnershipTag(key string) bool {
+	return strings.HasPrefix(key, ownershipTagPrefix)
+}
+
+// ownershipTags returns the tags marking a certificate for domainName, under
+// alias if it is not empty, as imported by this provider.
+func (c *ProviderClients) ownershipTags(domainName, alias string) map[string]string {
+	tags := map[string]string{
+		ownershipMarkerTag: "true",
+		ownershipDomainTag: domainName,
+	}
+	if c.Workspace != "" {
+		tags[ownershipWorkspaceTag] = c.Workspace
+	}
+	if alias != "" {
+		tags[ownershipAliasTag] = alias
+	}
+	return tags
+}
+
+// hasOwnershipMarker reports whether the provider imported arn.
+func (c *ProviderClients) hasOwnershipMarker(ctx context.Context, arn string) (bool, error) {
+	owned, _, err := c.ownership(ctx, arn)
+	return owned, err
+}
+
+// ownership reports whether the provider imported arn, and the alias it was
+// imported under, if any.
+func (c *ProviderClients) ownership(ctx context.Context, arn string) (owned bool, alias string, err error) {
+	output, err := c.ACMClient.ListTagsForCertificate(ctx, &acm.ListTagsForCertificateInput{
+		CertificateArn: aws.String(arn),
+	})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to list tags for %s: %w", arn, err)
+	}
+	for _, tag := range output.Tags {
+		switch aws.ToString(tag.Key) {
+		case ownershipMarkerTag:
+			owned = true
+		case ownershipAliasTag:
+			alias = aws.ToString(tag.Value)
+		}
+	}
+	return owned, alias, nil
+}
+
+// planTagsAll plans tags_all as the provider's default_tags merged with the
+// resource's tags. Only ACM certificates carry tags.
+func (r *CertificateResource) planTagsAll(ctx context.Context, plan CertificateResourceModel, resp *resource.ModifyPlanResponse) {
+	tagsAll := tfTypes.MapNull(tfTypes.StringType)
+	switch {
+	case plan.KeyBackend.IsUnknown() || plan.Tags.IsUnknown():
+		tagsAll = tfTypes.MapUnknown(tfTypes.StringType)
+	case plan.KeyBackend.ValueString() == keyBackendACM:
+		for _, value := range plan.Tags.Elements() {
+			if value.IsUnknown() {
+				resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tags_all"), tfTypes.MapUnknown(tfTypes.StringType))...)
+				return
+			}
+		}
+		var diags diag.Diagnostics
+		tagsAll, diags = r.tagsAll(ctx, plan.Tags)
+		resp.Diagnostics.Append(diags...)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tags_all"), tagsAll)...)
+}
+
+// tagsAll merges the provider's default_tags with tags, which win on
+// conflicting keys.
+func (r *CertificateResource) tagsAll(ctx context.Context, tags tfTypes.Map) (tfTypes.Map, diag.Diagnostics) {
+	merged := map[string]string{}
+	for key, value := range r.clients.DefaultTags {
+		merged[key] = value
+	}
+	resourceTags := map[string]string{}
+	diags := tags.ElementsAs(ctx, &resourceTags, false)
+	for key, value := range resourceTags {
+		merged[key] = value
+	}
+	result, d := tfTypes.MapValueFrom(ctx, tfTypes.StringType, merged)
+	diags.Append(d...)
+	return result, diags
+}
+
+// readTags refreshes tags_all from ACM, and tags from the ACM tags that do
+// not simply restate a default tag. The provider's ownership tags and the
+// provider's ignore_tags appear in neither. A change from the tags_all in
+// state is reported as a warning, since the next apply reverts it.
+func (r *CertificateResource) readTags(ctx context.Context, data *CertificateResourceModel, arn string, diags *diag.Diagnostics) {
+	output, err := r.clients.ACMClient.ListTagsForCertificate(ctx, &acm.ListTagsForCertificateInput{
+		CertificateArn: aws.String(arn),
+	})
+	if err != nil {
+		diags.AddError("Failed to list certificate tags", err.Error())
+		return
+	}
+
+	all := map[string]string{}
+	own := map[string]string{}
+	for _, tag := range output.Tags {
+		key, value := aws.ToString(tag.Key), aws.ToString(tag.Value)
+		if isOwnershipTag(key) || r.clients.IgnoreTags.ignored(key) {
+			continue
+		}
+		all[key] = value
+		if defaultValue, ok := r.clients.DefaultTags[key]; !ok || defaultValue != value {
+			own[key] = value
+		}
+	}
+
+	// tags_all is null in state after an import, when there is nothing to
+	// compare against.
+	if !data.TagsAll.IsNull() && !data.TagsAll.IsUnknown() {
+		previous := map[string]string{}
+		diags.Append(data.TagsAll.ElementsAs(ctx, &previous, false)...)
+		for key := range previous {
+			if r.clients.IgnoreTags.ignored(key) {
+				delete(previous, key)
+			}
+		}
+		if changes := tagDrift(previous, all); len(changes) > 0 {
+			diags.AddWarning(
+				"Certificate Tags Changed Outside Terraform",
+				fmt.Sprintf("The tags on %s were changed outside Terraform: %s. The next apply sets them back to the "+
+					"configuration; add tags managed by other tooling to the provider's ignore_tags to leave them alone.",
+					arn, strings.Join(changes, ", ")),
+			)
+		}
+	}
+
+	data.TagsAll, _ = tfTypes.MapValueFrom(ctx, tfTypes.StringType, all)
+	if len(own) > 0 || !data.Tags.IsNull() {
+		data.Tags, _ = tfTypes.MapValueFrom(ctx, tfTypes.StringType, own)
+	}
+}
+
+// tagDrift describes the differences between two sets of tags, in key order.
+func tagDrift(before, after map[string]string) []string {
+	var changes []string
+	for key, value := range before {
+		current, ok := after[key]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("%q removed", key))
+		case current != value:
+			changes = append(changes, fmt.Sprintf("%q changed from %q to %q", key, value, current))
+		}
+	}
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			changes = append(changes, fmt.Sprintf("%q added as %q", key, value))
+		}
+	}
+	slices.Sort(changes)
+	return changes
+}
+
+// syncRenewalTags sets the renewal reminder tags on every certificate of an
+// ACM backed resource with renewal_tags, from its expiry and rotation policy,
+// or removes them when previous had renewal_tags and data no longer does.
+// previous is nil on create. Re-importing into an existing ARN keeps its
+// tags, so this runs after every renewal to replace the stale values.
+func (r *CertificateResource) syncRenewalTags(ctx context.Context, data CertificateResourceModel, previous *CertificateResourceModel, private privateState, diags *diag.Diagnostics) {
+	if data.KeyBackend.ValueString() != keyBackendACM {
+		return
+	}
+	var arns []string
+	diags.Append(data.CertificateArns.ElementsAs(ctx, &arns, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	if !data.RenewalTags.ValueBool() {
+		if previous == nil || !previous.RenewalTags.ValueBool() {
+			return
+		}
+		removed := map[string]string{renewalExpiresAtTag: "", renewalRenewAfterTag: ""}
+		for _, arn := range arns {
+			if err := removeACMTags(ctx, r.clients.ACMClient, arn, removed); err != nil {
+				diags.AddWarning("Failed to remove renewal tags", err.Error())
+				return
+			}
+		}
+		return
+	}
+
+	expires, err := time.Parse(time.RFC3339, data.ExpiresAt.ValueString())
+	if err != nil {
+		diags.AddWarning("Failed to tag certificate for renewal", "The certificate's expiry is unknown.")
+		return
+	}
+	policy := RotationPolicyModel{RenewBeforeDays: tfTypes.Int64Value(30)}
+	if !data.RotationPolicy.IsNull() {
+		var d diag.Diagnostics
+		policy, d = rotationPolicyFromObject(ctx, data.RotationPolicy)
+		diags.Append(d...)
+	}
+	issuedAt := certificateIssuedAt(data)
+	meta, d := getIssuanceMetadata(ctx, private)
+	diags.Append(d...)
+	if meta != nil {
+		if metaIssuedAt, err := time.Parse(time.RFC3339, meta.IssuedAt); err == nil {
+			issuedAt = metaIssuedAt
+		}
+	}
+	tags := map[string]string{
+		renewalExpiresAtTag:  expires.UTC().Format(time.RFC3339),
+		renewalRenewAfterTag: policy.renewAfter(issuedAt, expires).UTC().Format(time.RFC3339),
+	}
+	for _, arn := range arns {
+		if err := addACMTags(ctx, r.clients.ACMClient, arn, tags); err != nil {
+			diags.AddWarning("Failed to tag certificate for renewal", err.Error())
+			return
+		}
+	}
+}
+
+// updateTags applies the difference between two tags_all values to every
+// certificate.
+func (r *CertificateResource) updateTags(ctx context.Context, arns []string, previous, planned tfTypes.Map) diag.Diagnostics {
+	before := map[string]string{}
+	after := map[string]string{}
+	diags := previous.ElementsAs(ctx, &before, false)
+	diags.Append(planned.ElementsAs(ctx, &after, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	removed := map[string]string{}
+	for key, value := range before {
+		if _, ok := after[key]; !ok {
+			removed[key] = value
+		}
+	}
+	changed := map[string]string{}
+	for key, value := range after {
+		if old, ok := before[key]; !ok || old != value {
+			changed[key] = value
+		}
+	}
+
+	for _, arn := range arns {
+		if err := removeACMTags(ctx, r.clients.ACMClient, arn, removed); err != nil {
+			diags.AddError("Failed to remove certificate tags", err.Error())
+			return diags
+		}
+		if err := addACMTags(ctx, r.clients.ACMClient, arn, changed); err != nil {
+			diags.AddError("Failed to tag certificate", err.Error())
+			return diags
+		}
+	}
+	return diags
+}
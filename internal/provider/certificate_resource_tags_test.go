@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestReadTagsReportsDrift(t *testing.T) {
+	fake := &fakeACM{tags: map[string]map[string]string{"arn:1": {
+		ownershipMarkerTag: "true",
+		"team":             "payments",
+		"backup:schedule":  "daily",
+	}}}
+	r := &CertificateResource{clients: &ProviderClients{
+		ACMClient:  fake,
+		IgnoreTags: ignoreTags{KeyPrefixes: []string{"backup:"}},
+	}}
+	ctx := context.Background()
+	tagsAll, _ := tfTypes.MapValueFrom(ctx, tfTypes.StringType, map[string]string{"team": "web", "env": "prod"})
+	data := CertificateResourceModel{Tags: tfTypes.MapNull(tfTypes.StringType), TagsAll: tagsAll}
+
+	var diags diag.Diagnostics
+	r.readTags(ctx, &data, "arn:1", &diags)
+	if diags.WarningsCount() != 1 {
+		t.Fatalf("diags = %v, want one drift warning", diags)
+	}
+	if detail := diags[0].Detail(); !strings.Contains(detail, `"env" removed, "team" changed from "web" to "payments"`) {
+		t.Errorf("warning = %q", detail)
+	}
+	var got map[string]string
+	data.TagsAll.ElementsAs(ctx, &got, false)
+	if want := map[string]string{"team": "payments"}; !maps.Equal(got, want) {
+		t.Errorf("tags_all = %v, want %v without ignored or ownership tags", got, want)
+	}
+
+	diags = nil
+	r.readTags(ctx, &data, "arn:1", &diags)
+	if diags.WarningsCount() != 0 {
+		t.Errorf("warned again once state matched: %v", diags)
+	}
+}
@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const testAccResourceType = "cfcert_origin_certificate"
+
+// testAccDomainPrefix starts every hostname acceptance tests certify, so the
+// sweepers can find certificates a failed run left behind.
+const testAccDomainPrefix = "tf-acc-"
+
+// testAccDomain returns a domain unique to this run, so runs against a shared
+// ACM endpoint do not adopt each other's certificates.
+func testAccDomain(t *testing.T) string {
+	return fmt.Sprintf("%s%d.example.com", testAccDomainPrefix, time.Now().UnixNano())
+}
+
+func TestAccCertificateResource_createAndDelete(t *testing.T) {
+	h := newAccHarness(t)
+	domain := testAccDomain(t)
+	config := h.config(testAccResourceType, map[string]tftypes.Value{
+		"domain_name": tftypes.NewValue(tftypes.String, domain),
+	})
+
+	res := h.create(testAccResourceType, config)
+	arn := res.stringAttribute(t, "certificate_arn")
+	if arn == "" {
+		t.Fatal("certificate_arn is empty after create")
+	}
+	if got := res.stringAttribute(t, "key_algorithm"); got != issuedKeyAlgorithm {
+		t.Errorf("key_algorithm = %q, want %q", got, issuedKeyAlgorithm)
+	}
+	if got := res.stringAttribute(t, "acm_type"); got != "IMPORTED" {
+		t.Errorf("acm_type = %q, want IMPORTED", got)
+	}
+	if res.stringAttribute(t, "imported_at") == "" {
+		t.Error("imported_at is empty after create")
+	}
+	if got := len(h.cloudflare.Issued()); got != 1 {
+		t.Errorf("issued %d Cloudflare certificates, want 1", got)
+	}
+
+	h.refresh(res)
+	if got := res.stringAttribute(t, "certificate_arn"); got != arn {
+		t.Errorf("certificate_arn after refresh = %q, want %q", got, arn)
+	}
+	if plan := h.plan(res, config); len(plan.RequiresReplace) > 0 {
+		t.Errorf("plan after create requires replacement of %v", plan.RequiresReplace)
+	}
+
+	h.destroy(res)
+	if !res.state.IsNull() {
+		t.Fatal("state is not null after destroy")
+	}
+}
+
+func TestAccCertificateResource_adopt(t *testing.T) {
+	h := newAccHarness(t)
+	domain := testAccDomain(t)
+	config := h.config(testAccResourceType, map[string]tftypes.Value{
+		"domain_name": tftypes.NewValue(tftypes.String, domain),
+	})
+
+	first := h.create(testAccResourceType, config)
+	second := h.create(testAccResourceType, config)
+	arn := first.stringAttribute(t, "certificate_arn")
+	if got := second.stringAttribute(t, "certificate_arn"); got != arn {
+		t.Errorf("second resource has certificate_arn %q, want the adopted %q", got, arn)
+	}
+	if got := len(h.cloudflare.Issued()); got != 1 {
+		t.Errorf("issued %d Cloudflare certificates, want 1", got)
+	}
+
+	// Destroying the adopter deletes the shared certificate, so the first
+	// resource is found gone on refresh.
+	h.destroy(second)
+	h.refresh(first)
+	if !first.state.IsNull() {
+		t.Error("first resource still in state after its certificate was deleted")
+	}
+}
+
+func TestAccCertificateResource_alias(t *testing.T) {
+	h := newAccHarness(t)
+	domain := testAccDomain(t)
+	config := func(alias string) tftypes.Value {
+		return h.config(testAccResourceType, map[string]tftypes.Value{
+			"domain_name": tftypes.NewValue(tftypes.String, domain),
+			"alias":       tftypes.NewValue(tftypes.String, alias),
+		})
+	}
+
+	blue := h.create(testAccResourceType, config("blue"))
+	t.Cleanup(func() { h.destroy(blue) })
+	green := h.create(testAccResourceType, config("green"))
+	t.Cleanup(func() { h.destroy(green) })
+	blueAgain := h.create(testAccResourceType, config("blue"))
+
+	if blue.stringAttribute(t, "certificate_arn") == green.stringAttribute(t, "certificate_arn") {
+		t.Error("green adopted blue's certificate")
+	}
+	if got, want := blueAgain.stringAttribute(t, "certificate_arn"), blue.stringAttribute(t, "certificate_arn"); got != want {
+		t.Errorf("second blue resource has certificate_arn %q, want the adopted %q", got, want)
+	}
+	if got := len(h.cloudflare.Issued()); got != 2 {
+		t.Errorf("issued %d Cloudflare certificates, want 2", got)
+	}
+}
+
+func TestAccCertificateResource_createBeforeDestroy(t *testing.T) {
+	h := newAccHarness(t)
+	domain := testAccDomain(t)
+	config := h.config(testAccResourceType, map[string]tftypes.Value{
+		"domain_name": tftypes.NewValue(tftypes.String, domain),
+	})
+
+	old := h.create(testAccResourceType, config)
+	oldArn := old.stringAttribute(t, "certificate_arn")
+	res := h.replace(old, config)
+	t.Cleanup(func() { h.destroy(res) })
+
+	if got := res.stringAttribute(t, "certificate_arn"); got == oldArn {
+		t.Fatalf("replacement adopted the certificate being replaced, %s", oldArn)
+	}
+	if replaced := res.stringListAttribute(t, "replaced_certificate_arns"); len(replaced) != 1 || replaced[0] != oldArn {
+		t.Errorf("replaced_certificate_arns = %v, want [%s]", replaced, oldArn)
+	}
+	if got := len(h.cloudflare.Issued()); got != 2 {
+		t.Errorf("issued %d Cloudflare certificates, want 2", got)
+	}
+
+	h.refresh(res)
+	if res.state.IsNull() {
+		t.Fatal("replacement's certificate was deleted with the instance it replaced")
+	}
+}
+
+func TestAccCertificateResource_renew(t *testing.T) {
+	h := newAccHarness(t)
+	domain := testAccDomain(t)
+	policyType := h.resourceType(testAccResourceType).AttributeTypes["rotation_policy"].(tftypes.Object)
+	config := func(renewBeforeDays int64) tftypes.Value {
+		return h.config(testAccResourceType, map[string]tftypes.Value{
+			"domain_name": tftypes.NewValue(tftypes.String, domain),
+			"rotation_policy": objectValue(policyType, map[string]tftypes.Value{
+				"renew_before_days": tftypes.NewValue(tftypes.Number, big.NewFloat(float64(renewBeforeDays))),
+			}),
+		})
+	}
+
+	res := h.create(testAccResourceType, config(30))
+	arn := res.stringAttribute(t, "certificate_arn")
+	serial := res.stringAttribute(t, "serial_number")
+	t.Cleanup(func() { h.destroy(res) })
+
+	// Certificates are issued for 15 years, so a 20 year window is due now.
+	plan := h.apply(res, config(20*365))
+	if len(plan.RequiresReplace) > 0 {
+		t.Fatalf("renewal planned a replacement of %v", plan.RequiresReplace)
+	}
+	if got := res.stringAttribute(t, "certificate_arn"); got != arn {
+		t.Errorf("certificate_arn after renewal = %q, want %q re-imported in place", got, arn)
+	}
+	if got := res.stringAttribute(t, "serial_number"); got == serial {
+		t.Errorf("serial_number %q did not change on renewal", got)
+	}
+	if got := len(h.cloudflare.Issued()); got != 2 {
+		t.Errorf("issued %d Cloudflare certificates, want 2", got)
+	}
+}
+
+// describeACM is a pemACM that describes every certificate as its summary
+// lists it.
+type describeACM struct {
+	pemACM
+}
+
+func (f *describeACM) DescribeCertificate(_ context.Context, params *acm.DescribeCertificateInput, _ ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	for _, summary := range f.summaries {
+		if aws.ToString(summary.CertificateArn) == aws.ToString(params.CertificateArn) {
+			return &acm.DescribeCertificateOutput{Certificate: &types.CertificateDetail{
+				CertificateArn: summary.CertificateArn,
+				DomainName:     summary.DomainName,
+			}}, nil
+		}
+	}
+	return nil, fmt.Errorf("no certificate %s", aws.ToString(params.CertificateArn))
+}
+
+func TestCertificateImportStateByDomainAndAlias(t *testing.T) {
+	clients := newTestClients(cloudflaretest.NewServer(t))
+	ctx := context.Background()
+	fake := &describeACM{pemACM{certificates: map[string]string{}}}
+	fake.pageSize = 100
+	fake.tags = map[string]map[string]string{}
+	for _, alias := range []string{"", "blue"} {
+		cert, _, err := clients.issueWithLocalKey(ctx, []string{"example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		arn := "arn:aws:acm:us-east-1:123456789012:certificate/" + cert.ID
+		fake.certificates[arn] = cert.Certificate
+		fake.tags[arn] = clients.ownershipTags("example.com", alias)
+		fake.summaries = append(fake.summaries, types.CertificateSummary{
+			CertificateArn: aws.String(arn),
+			DomainName:     aws.String("example.com"),
+		})
+	}
+	clients.ACMClient = fake
+	r := &CertificateResource{clients: clients}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	tests := []struct {
+		id, wantArn, wantAlias string
+	}{
+		{id: "example.com", wantArn: aws.ToString(fake.summaries[0].CertificateArn)},
+		{id: "example.com/blue", wantArn: aws.ToString(fake.summaries[1].CertificateArn), wantAlias: "blue"},
+		{id: "example.com/green"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			resp := &resource.ImportStateResponse{State: tfsdk.State{
+				Schema: schemaResp.Schema,
+				Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+			}}
+			r.ImportState(ctx, resource.ImportStateRequest{ID: tt.id}, resp)
+			if tt.wantArn == "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Certificate Not Found" {
+					t.Errorf("diagnostics = %v, want Certificate Not Found", resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("ImportState: %v", resp.Diagnostics)
+			}
+			var data CertificateResourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+			if data.CertificateArn.ValueString() != tt.wantArn {
+				t.Errorf("certificate_arn = %s, want %s", data.CertificateArn, tt.wantArn)
+			}
+			if data.Alias.ValueString() != tt.wantAlias || data.DomainName.ValueString() != "example.com" {
+				t.Errorf("imported domain_name %s and alias %s, want example.com and %q", data.DomainName, data.Alias, tt.wantAlias)
+			}
+		})
+	}
+}
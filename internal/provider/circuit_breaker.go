@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// circuitBreakerThreshold is how many consecutive failures open a
+	// breaker.
+	circuitBreakerThreshold = 5
+	// circuitBreakerCooldown is how long an open breaker rejects calls
+	// before letting one through to test the upstream.
+	circuitBreakerCooldown = 30 * time.Second
+)
+
+// circuitBreaker is shared by every resource calling one upstream API. Once
+// the upstream has failed circuitBreakerThreshold times in a row, calls fail
+// immediately for circuitBreakerCooldown instead of adding load to an outage
+// and reporting the same failure for every resource in the apply.
+type circuitBreaker struct {
+	name string
+
+	mu        sync.Mutex
+	failures  int
+	lastError string
+	openUntil time.Time
+}
+
+func newCircuitBreaker(name string) *circuitBreaker {
+	return &circuitBreaker{name: name}
+}
+
+// allow returns an error while the breaker is open.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if time.Now().Before(b.openUntil) {
+		return &circuitOpenError{name: b.name, failures: b.failures, lastError: b.lastError, retryAt: b.openUntil}
+	}
+	return nil
+}
+
+// record notes the outcome of a call; failure is "" for a success.
+func (b *circuitBreaker) record(failure string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if failure == "" {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	b.lastError = failure
+	if b.failures >= circuitBreakerThreshold {
+		b.openUntil = time.Now().Add(circuitBreakerCooldown)
+	}
+}
+
+type circuitOpenError struct {
+	name      string
+	failures  int
+	lastError string
+	retryAt   time.Time
+}
+
+func (e *circuitOpenError) Error() string {
+	return fmt.Sprintf("%s appears to be unavailable: skipped after %d consecutive failures, the last being %q. "+
+		"Further calls are skipped until %s.", e.name, e.failures, e.lastError, e.retryAt.UTC().Format(time.RFC3339))
+}
+
+// RetryableError stops the AWS SDK retrying a call the breaker rejected.
+func (e *circuitOpenError) RetryableError() bool {
+	return false
+}
+
+// httpDoer is satisfied by *http.Client and the AWS SDK's HTTP clients.
+type httpDoer interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// breakerHTTPClient counts transport errors, 429s and 5xx responses from
+// next against breaker.
+type breakerHTTPClient struct {
+	next    httpDoer
+	breaker *circuitBreaker
+}
+
+func (c *breakerHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.next.Do(req)
+	switch {
+	case errors.Is(err, context.Canceled):
+		// Cancellation says nothing about the upstream.
+	case err != nil:
+		c.breaker.record(err.Error())
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		c.breaker.record(resp.Status)
+	default:
+		c.breaker.record("")
+	}
+	return resp, err
+}
@@ -0,0 +1,280 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// isCloudflareOriginCA reports whether cert was issued by one of Cloudflare's
+// Origin CA roots, whose subjects name them "CloudFlare Origin ... Certificate
+// Authority" under the "CloudFlare, Inc." organisation.
+func isCloudflareOriginCA(cert *x509.Certificate) bool {
+	issuer := cert.Issuer
+	if !strings.HasPrefix(issuer.CommonName, "CloudFlare Origin ") {
+		return false
+	}
+	for _, org := range issuer.Organization {
+		if org == "CloudFlare, Inc." {
+			return true
+		}
+	}
+	return false
+}
+
+// createCSR builds a CSR covering hostnames, using the first as the common
+// name.
+func createCSR(hostnames []string, signer crypto.Signer) (string, error) {
+	csrTemplate := x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: hostnames[0]},
+		DNSNames: hostnames,
+	}
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &csrTemplate, signer)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})), nil
+}
+
+// requestCloudflareOriginCert requests a certificate for csrPEM and checks
+// the response is a well-formed certificate for the CSR's key and hostnames.
+// Hostnames outside the provider's allowed_zone_ids are refused first.
+func (c *ProviderClients) requestCloudflareOriginCert(ctx context.Context, hostnames []string, csrPEM string) (cloudflare.OriginCert, error) {
+	if err := c.checkAllowedZones(ctx, hostnames); err != nil {
+		return cloudflare.OriginCert{}, err
+	}
+	cert, err := c.Cloudflare.Issue(ctx, cloudflare.OriginCertRequest{
+		CSR:               csrPEM,
+		Hostnames:         hostnames,
+		RequestType:       cloudflare.RequestTypeECC,
+		RequestedValidity: 5475,
+	})
+	if err != nil {
+		return cloudflare.OriginCert{}, withOriginCAHint(err)
+	}
+	cert.Certificate, err = normalizeCertificatePEM(cert.Certificate)
+	if err != nil {
+		return cloudflare.OriginCert{}, fmt.Errorf("invalid certificate %q in response (Cloudflare ray ID %s): %w", cert.ID, cloudflare.RayIDOrUnknown(cert.RayID), err)
+	}
+	if err := verifyIssuedCertificate(cert.Certificate, csrPEM, hostnames); err != nil {
+		return cloudflare.OriginCert{}, fmt.Errorf("certificate %s does not match the request (Cloudflare ray ID %s): %w", cert.ID, cloudflare.RayIDOrUnknown(cert.RayID), err)
+	}
+	leaf, err := parseCertificatePEM(cert.Certificate)
+	if err == nil {
+		err = c.verifyOriginCAChain(leaf, cert.Certificate)
+	}
+	if err != nil {
+		return cloudflare.OriginCert{}, fmt.Errorf("certificate %s (Cloudflare ray ID %s): %w", cert.ID, cloudflare.RayIDOrUnknown(cert.RayID), err)
+	}
+	return cert, nil
+}
+
+// normalizeCertificatePEM re-encodes the certificates in certPEM with
+// canonical line endings and no surrounding whitespace, rejecting responses
+// that are empty, hold other PEM block types, or contain anything that does
+// not parse as a certificate.
+func normalizeCertificatePEM(certPEM string) (string, error) {
+	rest := []byte(strings.TrimSpace(strings.ReplaceAll(certPEM, "\r\n", "\n")))
+	if len(rest) == 0 {
+		return "", fmt.Errorf("certificate is empty")
+	}
+
+	var normalized []byte
+	for len(rest) > 0 {
+		// pem.Decode skips text before a block, which would hide garbage.
+		if !bytes.HasPrefix(rest, []byte("-----BEGIN ")) {
+			return "", fmt.Errorf("certificate is not valid PEM")
+		}
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return "", fmt.Errorf("certificate is not valid PEM")
+		}
+		if block.Type != "CERTIFICATE" {
+			return "", fmt.Errorf("unexpected PEM block type %q", block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return "", fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		normalized = append(normalized, pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: block.Bytes})...)
+		rest = bytes.TrimSpace(rest)
+	}
+	return string(normalized), nil
+}
+
+// verifyIssuedCertificate checks that certPEM certifies the CSR's public key
+// for exactly the requested hostnames, so a bad response fails here rather
+// than as an obscure ACM import error or a certificate that cannot be used.
+func verifyIssuedCertificate(certPEM, csrPEM string, hostnames []string) error {
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil {
+		return fmt.Errorf("failed to decode CSR PEM")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse CSR: %w", err)
+	}
+
+	publicKey, ok := csr.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !publicKey.Equal(cert.PublicKey) {
+		return fmt.Errorf("public key does not match the CSR")
+	}
+
+	requested := slices.Clone(hostnames)
+	issued := slices.Clone(cert.DNSNames)
+	slices.Sort(requested)
+	slices.Sort(issued)
+	if !slices.Equal(slices.Compact(requested), slices.Compact(issued)) {
+		return fmt.Errorf("subject alternative names %v do not match the requested hostnames %v", cert.DNSNames, hostnames)
+	}
+	return nil
+}
+
+// verifyKeyPair checks that keyPEM is the private key for the first
+// certificate in certPEM before the pair is imported. ACM reports a mismatch
+// only as an invalid certificate, so the public key fingerprints of both are
+// named here instead.
+func verifyKeyPair(certPEM string, keyPEM []byte) error {
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return err
+	}
+	key, err := parsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return err
+	}
+	if ecKey, ok := key.(*ecdsa.PrivateKey); ok {
+		defer zeroizeECKey(ecKey)
+	}
+
+	public, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if ok && public.Equal(cert.PublicKey) {
+		return nil
+	}
+	keyDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return fmt.Errorf("private key does not match the certificate for %v", cert.DNSNames)
+	}
+	keySum := sha256.Sum256(keyDER)
+	certSum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return fmt.Errorf("private key does not match the certificate for %v: the certificate's public key has SHA-256 fingerprint %x, the private key's has %x",
+		cert.DNSNames, certSum, keySum)
+}
+
+// parsePrivateKeyPEM parses a SEC 1, PKCS#1 or PKCS#8 private key.
+func parsePrivateKeyPEM(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode private key PEM")
+	}
+	defer zeroize(block.Bytes)
+
+	var key crypto.Signer
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		var parsed any
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if signer, ok := parsed.(crypto.Signer); ok {
+			key = signer
+		} else if err == nil {
+			err = fmt.Errorf("unsupported private key type %T", parsed)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected private key PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	return key, nil
+}
+
+// getCloudflareOriginCert looks up a previously issued certificate by its
+// Cloudflare ID.
+func (c *ProviderClients) getCloudflareOriginCert(ctx context.Context, id string) (cloudflare.OriginCert, error) {
+	cert, err := c.Cloudflare.Get(ctx, id)
+	return cert, withOriginCAHint(err)
+}
+
+// revokeUnused revokes cert after a failure left it stored nowhere, so a
+// failed create does not leave a valid certificate behind in Cloudflare. A
+// failed revocation is a warning naming the certificate to revoke by hand.
+func (c *ProviderClients) revokeUnused(ctx context.Context, cert cloudflare.OriginCert, diags *diag.Diagnostics) {
+	if err := c.Cloudflare.Revoke(ctx, cert.ID); err != nil {
+		diags.AddWarning(
+			"Cloudflare Certificate Not Revoked",
+			fmt.Sprintf("Failed to revoke unused Cloudflare certificate %s: %s", cert.ID, withOriginCAHint(err)),
+		)
+	}
+}
+
+// issueWithLocalKey generates a P-256 key in provider memory and requests an
+// origin certificate for it, returning the certificate and the key as PEM.
+func (c *ProviderClients) issueWithLocalKey(ctx context.Context, hostnames []string) (cloudflare.OriginCert, []byte, error) {
+	hostnames = normalizeHostnames(hostnames)
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return cloudflare.OriginCert{}, nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+	defer zeroizeECKey(privateKey)
+
+	csrPEM, err := createCSR(hostnames, privateKey)
+	if err != nil {
+		return cloudflare.OriginCert{}, nil, fmt.Errorf("failed to create CSR: %w", err)
+	}
+
+	cert, err := c.requestCloudflareOriginCert(ctx, hostnames, csrPEM)
+	if err != nil {
+		return cloudflare.OriginCert{}, nil, fmt.Errorf("failed to request Cloudflare Origin Certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return cloudflare.OriginCert{}, nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	defer zeroize(keyDER)
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// hostnameChunks returns the normalized, de-duplicated hostnames, domainName
+// first, split into groups Cloudflare will accept on a single certificate.
+func hostnameChunks(domainName string, hostnames []string) [][]string {
+	domainName = normalizeHostname(domainName)
+	hostnames = normalizeHostnames(hostnames)
+	all := []string{domainName}
+	seen := map[string]bool{domainName: true}
+	for _, h := range hostnames {
+		if !seen[h] {
+			seen[h] = true
+			all = append(all, h)
+		}
+	}
+
+	var chunks [][]string
+	for len(all) > cloudflare.MaxHostnames {
+		chunks = append(chunks, all[:cloudflare.MaxHostnames])
+		all = all[cloudflare.MaxHostnames:]
+	}
+	return append(chunks, all)
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+)
+
+// Origin CA error codes with a known cause.
+const (
+	cloudflareCodeAuthentication = 10000
+	cloudflareCodeInvalidToken   = 1000
+	cloudflareCodeHostnameAccess = 1010
+)
+
+// withOriginCAHint appends remediation advice to an Origin CA API error whose
+// cause is recognised, and returns other errors unchanged.
+func withOriginCAHint(err error) error {
+	var apiErr *cloudflare.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	if hint := originCAHint(apiErr); hint != "" {
+		return fmt.Errorf("%w\n\n%s", err, hint)
+	}
+	return err
+}
+
+// originCAHint explains how to fix the commonest Origin CA failures. Cloudflare
+// does not document a code for every one, so messages are matched as well.
+func originCAHint(apiErr *cloudflare.APIError) string {
+	messages := make([]string, 0, len(apiErr.Errors))
+	for _, detail := range apiErr.Errors {
+		switch detail.Code {
+		case cloudflareCodeAuthentication, cloudflareCodeInvalidToken:
+			return originCAAuthHint
+		case cloudflareCodeHostnameAccess:
+			return originCAHostnameHint
+		}
+		messages = append(messages, strings.ToLower(detail.Message))
+	}
+	message := strings.Join(messages, " ")
+
+	switch {
+	case apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden,
+		strings.Contains(message, "authentication"), strings.Contains(message, "permission"):
+		return originCAAuthHint
+	case strings.Contains(message, "not part of your account"),
+		strings.Contains(message, "hostname") && strings.Contains(message, "zone"):
+		return originCAHostnameHint
+	case strings.Contains(message, "hostnames") && (strings.Contains(message, "too many") || strings.Contains(message, "maximum")):
+		return fmt.Sprintf("Cloudflare accepts at most %d hostnames per certificate. Remove hostnames, or set split_hostnames so "+
+			"they are issued as several certificates.", cloudflare.MaxHostnames)
+	case strings.Contains(message, "validity"):
+		return fmt.Sprintf("Cloudflare only issues certificates valid for %s days.", joinInts(cloudflare.ValidityDays))
+	}
+	return ""
+}
+
+const (
+	originCAAuthHint = "Cloudflare rejected the credentials for the Origin CA API. Set cloudflare_service_api_token to an " +
+		"Origin CA key, or give cloudflare_api_token the Zone > SSL and Certificates > Edit permission on every zone the " +
+		"certificate covers."
+	originCAHostnameHint = "Every hostname must be in a zone of the Cloudflare account the credentials belong to. Check the " +
+		"hostnames for typos, and set verify_zone to catch this before a certificate is requested."
+)
+
+// joinInts formats values as "a, b or c".
+func joinInts(values []int64) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = fmt.Sprint(value)
+	}
+	if len(formatted) < 2 {
+		return strings.Join(formatted, "")
+	}
+	return strings.Join(formatted[:len(formatted)-1], ", ") + " or " + formatted[len(formatted)-1]
+}
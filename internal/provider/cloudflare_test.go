@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// newTestClients returns clients that send Cloudflare requests to mock. AWS
+// clients are left unset.
+func newTestClients(mock *cloudflaretest.Server) *ProviderClients {
+	return &ProviderClients{
+		CloudflareAPIToken: "test-token",
+		Cloudflare:         mock.NewClient(),
+		Issuances:          newIssuanceLocks(),
+		CertificateList:    newCertificateListCache(),
+		Features:           defaultFeatures,
+	}
+}
+
+func TestIssueWithLocalKey(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	clients := newTestClients(mock)
+
+	cert, keyPEM, err := clients.issueWithLocalKey(context.Background(), []string{"Example.com.", "*.example.com"})
+	if err != nil {
+		t.Fatalf("issueWithLocalKey: %v", err)
+	}
+	if err := verifyKeyPair(cert.Certificate, keyPEM); err != nil {
+		t.Errorf("issued key does not match the certificate: %v", err)
+	}
+	parsed, err := parseCertificatePEM(cert.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"example.com", "*.example.com"}; !slices.Equal(parsed.DNSNames, want) {
+		t.Errorf("DNSNames = %v, want %v", parsed.DNSNames, want)
+	}
+	if cert.RayID != cloudflaretest.RayID {
+		t.Errorf("RayID = %q, want %q", cert.RayID, cloudflaretest.RayID)
+	}
+	if got := len(mock.Issued()); got != 1 {
+		t.Errorf("issued %d certificates, want 1", got)
+	}
+}
+
+func TestGetCloudflareOriginCert(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	clients := newTestClients(mock)
+	ctx := context.Background()
+
+	issued, _, err := clients.issueWithLocalKey(ctx, []string{"example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := clients.getCloudflareOriginCert(ctx, issued.ID)
+	if err != nil {
+		t.Fatalf("getCloudflareOriginCert: %v", err)
+	}
+	if cert.ID != issued.ID || cert.RevokedAt != "" {
+		t.Errorf("got %+v, want an active certificate %s", cert, issued.ID)
+	}
+
+	_, err = clients.getCloudflareOriginCert(ctx, "missing")
+	var apiErr *cloudflare.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("getCloudflareOriginCert(missing) error = %v, want a 404 cloudflare.APIError", err)
+	}
+}
+
+func TestCloudflareAPIErrorDetails(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	clients := newTestClients(mock)
+	mock.FailNext(http.StatusBadRequest,
+		cloudflare.ErrorDetail{Code: 1010, Message: "Failed to validate requested hostname example.com: This zone is either not part of your account, or you do not have access to it."},
+		cloudflare.ErrorDetail{Code: 1011, Message: "Second problem"},
+	)
+
+	_, _, err := clients.issueWithLocalKey(context.Background(), []string{"example.com"})
+	if err == nil {
+		t.Fatal("issueWithLocalKey succeeded, want an error")
+	}
+	var apiErr *cloudflare.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error %v is not a cloudflare.APIError", err)
+	}
+	if apiErr.RayID != cloudflaretest.RayID || len(apiErr.Errors) != 2 {
+		t.Errorf("got %+v, want ray ID %s and both errors", apiErr, cloudflaretest.RayID)
+	}
+	for _, want := range []string{"ray ID " + cloudflaretest.RayID, "(code 1010)", "Second problem (code 1011)", originCAHostnameHint} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestOriginCAHint(t *testing.T) {
+	tests := []struct {
+		name string
+		err  cloudflare.APIError
+		want string
+	}{
+		{
+			name: "authentication code",
+			err:  cloudflare.APIError{StatusCode: 400, Errors: []cloudflare.ErrorDetail{{Code: 10000, Message: "Authentication error"}}},
+			want: originCAAuthHint,
+		},
+		{
+			name: "forbidden",
+			err:  cloudflare.APIError{StatusCode: 403},
+			want: originCAAuthHint,
+		},
+		{
+			name: "hostname outside account",
+			err:  cloudflare.APIError{StatusCode: 400, Errors: []cloudflare.ErrorDetail{{Code: 1010, Message: "not in zone"}}},
+			want: originCAHostnameHint,
+		},
+		{
+			name: "too many hostnames",
+			err:  cloudflare.APIError{StatusCode: 400, Errors: []cloudflare.ErrorDetail{{Code: 1004, Message: "Too many hostnames, the maximum is 100"}}},
+			want: "at most 100 hostnames",
+		},
+		{
+			name: "validity",
+			err:  cloudflare.APIError{StatusCode: 400, Errors: []cloudflare.ErrorDetail{{Code: 1003, Message: "Invalid requested validity"}}},
+			want: "7, 30, 90, 365, 730, 1095 or 5475 days",
+		},
+		{
+			name: "unrecognised",
+			err:  cloudflare.APIError{StatusCode: 400, Errors: []cloudflare.ErrorDetail{{Code: 1, Message: "Something else"}}},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := originCAHint(&tt.err)
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("originCAHint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyZones(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	mock.AddZone("example.com")
+	clients := newTestClients(mock)
+
+	var diags diag.Diagnostics
+	clients.verifyZones(context.Background(), []string{"example.com", "www.example.com", "example.org"}, &diags)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("got %d errors, want 1 for example.org: %v", diags.ErrorsCount(), diags)
+	}
+	if detail := diags.Errors()[0].Detail(); !strings.Contains(detail, "example.org") {
+		t.Errorf("error %q does not name example.org", detail)
+	}
+}
+
+func TestCheckDNSRecords(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	mock.AddZone("example.com",
+		cloudflare.DNSRecord{Name: "example.com", Type: "A", Proxied: true},
+		cloudflare.DNSRecord{Name: "direct.example.com", Type: "A"},
+	)
+	clients := newTestClients(mock)
+
+	var diags diag.Diagnostics
+	clients.checkDNSRecords(context.Background(), []string{"example.com", "direct.example.com", "missing.example.com"}, &diags)
+	if diags.HasError() || diags.WarningsCount() != 2 {
+		t.Fatalf("got %v, want missing and unproxied warnings", diags)
+	}
+	for _, warning := range diags.Warnings() {
+		switch warning.Summary() {
+		case "No DNS Record For Hostname":
+			if !strings.Contains(warning.Detail(), "missing.example.com") {
+				t.Errorf("missing record warning %q does not name missing.example.com", warning.Detail())
+			}
+		case "DNS Record Not Proxied":
+			if !strings.Contains(warning.Detail(), "direct.example.com") {
+				t.Errorf("unproxied warning %q does not name direct.example.com", warning.Detail())
+			}
+		default:
+			t.Errorf("unexpected warning %q", warning.Summary())
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// cloudflareTokenSource returns a function that reads the Cloudflare API token
+// from tokenFile or, when that is empty, from the Secrets Manager secret
+// secretID. The Cloudflare client calls it again whenever the API rejects the
+// token it holds, so a token rotated during a long apply is picked up.
+func cloudflareTokenSource(tokenFile, secretID string, secrets *secretsManagerClient) func(ctx context.Context) (string, error) {
+	if tokenFile != "" {
+		return func(context.Context) (string, error) {
+			content, err := os.ReadFile(tokenFile)
+			if err != nil {
+				return "", fmt.Errorf("failed to read Cloudflare API token file: %w", err)
+			}
+			token := strings.TrimSpace(string(content))
+			if token == "" {
+				return "", fmt.Errorf("Cloudflare API token file %s is empty", tokenFile)
+			}
+			return token, nil
+		}
+	}
+	return func(ctx context.Context) (string, error) {
+		secret, err := secrets.secretString(ctx, secretID)
+		if err != nil {
+			return "", fmt.Errorf("failed to read Cloudflare API token from secret %s: %w", secretID, err)
+		}
+		token := strings.TrimSpace(secret)
+		if token == "" {
+			return "", fmt.Errorf("Cloudflare API token secret %s is empty", secretID)
+		}
+		return token, nil
+	}
+}
@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// zoneCandidates returns the names a hostname's zone could have, longest
+// first, down to a two-label domain: www.example.com yields www.example.com
+// and example.com.
+func zoneCandidates(hostname string) []string {
+	labels := strings.Split(strings.TrimPrefix(normalizeHostname(hostname), "*."), ".")
+	var candidates []string
+	for i := 0; i <= len(labels)-2; i++ {
+		candidates = append(candidates, strings.Join(labels[i:], "."))
+	}
+	return candidates
+}
+
+// findCloudflareZone returns the zone hostname belongs to, trying each
+// candidate from zoneCandidates. Lookups are remembered in zones so hostnames
+// sharing a zone are only looked up once.
+func (c *ProviderClients) findCloudflareZone(ctx context.Context, hostname string, zones map[string]*cloudflare.Zone) (*cloudflare.Zone, error) {
+	for _, name := range zoneCandidates(hostname) {
+		zone, seen := zones[name]
+		if !seen {
+			found, ok, err := c.Cloudflare.Zone(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				zone = &found
+			}
+			zones[name] = zone
+		}
+		if zone != nil {
+			return zone, nil
+		}
+	}
+	return nil, nil
+}
+
+// forCloudflareAccount returns a copy of c whose zone lookups are limited to
+// the Cloudflare account accountID. The circuit breakers, caches and locks are
+// shared with c.
+func (c *ProviderClients) forCloudflareAccount(accountID string) *ProviderClients {
+	clients := *c
+	clients.CloudflareConfig.AccountID = accountID
+	clients.Cloudflare = cloudflare.New(clients.CloudflareConfig)
+	return &clients
+}
+
+// withCloudflareAccount returns r, or a copy of r whose zone lookups are
+// limited to the account in accountID when it is set.
+func (r *CertificateResource) withCloudflareAccount(accountID tfTypes.String) *CertificateResource {
+	if accountID.IsNull() || accountID.IsUnknown() || accountID.ValueString() == "" {
+		return r
+	}
+	return &CertificateResource{clients: r.clients.forCloudflareAccount(accountID.ValueString())}
+}
+
+// zoneScope describes where zones are looked up, for error messages.
+func (c *ProviderClients) zoneScope() string {
+	if c.CloudflareConfig.AccountID == "" {
+		return "the Cloudflare API token can access"
+	}
+	return "the Cloudflare API token can access in account " + c.CloudflareConfig.AccountID
+}
+
+// verifyZones reports an error for each hostname without a zone in the
+// authenticated Cloudflare account, so a mistyped domain fails before a
+// certificate is requested rather than with an Origin CA validation error.
+func (c *ProviderClients) verifyZones(ctx context.Context, hostnames []string, diags *diag.Diagnostics) {
+	if c.CloudflareAPIToken == "" {
+		diags.AddError(
+			"Cannot Verify Cloudflare Zones",
+			"verify_zone requires cloudflare_api_token, with Zone Read permission; the Origin CA service key cannot list zones.",
+		)
+		return
+	}
+
+	zones := map[string]*cloudflare.Zone{}
+	for _, hostname := range hostnames {
+		zone, err := c.findCloudflareZone(ctx, hostname, zones)
+		if err != nil {
+			diags.AddError("Failed to look up Cloudflare zone", err.Error())
+			return
+		}
+		if zone == nil {
+			diags.AddError(
+				"Cloudflare Zone Not Found",
+				fmt.Sprintf("zone not found for %s: none of %s is a zone %s. "+
+					"Check the hostname for typos, or that the token has Zone Read permission on its zone.",
+					hostname, strings.Join(zoneCandidates(hostname), ", "), c.zoneScope()),
+			)
+		}
+	}
+}
+
+// checkAllowedZones returns an error naming the first hostname whose zone is
+// not in the provider's allowed_zone_ids, or that has no zone the API token
+// can access. It does nothing when no allowlist is configured.
+func (c *ProviderClients) checkAllowedZones(ctx context.Context, hostnames []string) error {
+	if len(c.AllowedZoneIDs) == 0 {
+		return nil
+	}
+	zones := map[string]*cloudflare.Zone{}
+	for _, hostname := range hostnames {
+		zone, err := c.findCloudflareZone(ctx, hostname, zones)
+		if err != nil {
+			return fmt.Errorf("failed to look up the zone of %s to check it against allowed_zone_ids: %w", hostname, err)
+		}
+		if zone == nil {
+			return fmt.Errorf("refusing to issue a certificate for %s: none of %s is a zone %s, so it cannot be checked "+
+				"against allowed_zone_ids", hostname, strings.Join(zoneCandidates(hostname), ", "), c.zoneScope())
+		}
+		if !slices.Contains(c.AllowedZoneIDs, zone.ID) {
+			return fmt.Errorf("refusing to issue a certificate for %s: its zone %s (%s) is not in allowed_zone_ids",
+				hostname, zone.Name, zone.ID)
+		}
+	}
+	return nil
+}
+
+// checkDNSRecords warns about hostnames with no DNS record in Cloudflare, or
+// with records that are not proxied. Origin certificates are only trusted by
+// Cloudflare's proxy, so either usually means the hostname is not routed the
+// way the certificate assumes.
+func (c *ProviderClients) checkDNSRecords(ctx context.Context, hostnames []string, diags *diag.Diagnostics) {
+	if c.CloudflareAPIToken == "" {
+		diags.AddWarning(
+			"Cannot Check DNS Records",
+			"check_dns_records requires cloudflare_api_token, with Zone Read and DNS Read permissions; the Origin CA service key cannot read DNS records.",
+		)
+		return
+	}
+
+	var missing, unproxied []string
+	zones := map[string]*cloudflare.Zone{}
+	for _, hostname := range hostnames {
+		zone, err := c.findCloudflareZone(ctx, hostname, zones)
+		if err != nil {
+			diags.AddWarning("Failed to check DNS records", err.Error())
+			return
+		}
+		if zone == nil {
+			missing = append(missing, hostname)
+			continue
+		}
+		records, err := c.Cloudflare.DNSRecords(ctx, zone.ID, hostname)
+		if err != nil {
+			diags.AddWarning("Failed to check DNS records", err.Error())
+			return
+		}
+		if len(records) == 0 {
+			missing = append(missing, hostname)
+			continue
+		}
+		if !slices.ContainsFunc(records, func(record cloudflare.DNSRecord) bool { return record.Proxied }) {
+			unproxied = append(unproxied, hostname)
+		}
+	}
+
+	if len(missing) > 0 {
+		diags.AddWarning(
+			"No DNS Record For Hostname",
+			fmt.Sprintf("Cloudflare has no DNS record for %s, so the certificate is being issued for hostnames that are not routed "+
+				"through Cloudflare yet.", strings.Join(missing, ", ")),
+		)
+	}
+	if len(unproxied) > 0 {
+		diags.AddWarning(
+			"DNS Record Not Proxied",
+			fmt.Sprintf("The DNS records for %s are not proxied. Origin certificates are only trusted by Cloudflare's proxy; "+
+				"clients connecting to the origin directly will reject them.", strings.Join(unproxied, ", ")),
+		)
+	}
+}
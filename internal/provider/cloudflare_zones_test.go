@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+)
+
+func TestAllowedZoneIDs(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	allowed := mock.AddZone("example.com")
+	mock.AddZone("example.org")
+	clients := newTestClients(mock)
+	clients.AllowedZoneIDs = []string{allowed}
+	ctx := context.Background()
+
+	if _, _, err := clients.issueWithLocalKey(ctx, []string{"www.example.com"}); err != nil {
+		t.Fatalf("issuing in an allowed zone: %v", err)
+	}
+	for _, hostnames := range [][]string{{"example.com", "www.example.org"}, {"example.net"}} {
+		_, _, err := clients.issueWithLocalKey(ctx, hostnames)
+		if err == nil || !strings.Contains(err.Error(), "refusing to issue") {
+			t.Errorf("issueWithLocalKey(%v) error = %v, want a refusal", hostnames, err)
+		}
+	}
+	if got := len(mock.Issued()); got != 1 {
+		t.Errorf("issued %d certificates, want only the allowed one", got)
+	}
+}
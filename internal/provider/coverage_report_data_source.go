@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &CoverageReportDataSource{}
+var _ datasource.DataSourceWithConfigure = &CoverageReportDataSource{}
+
+type CoverageReportDataSource struct {
+	clients *ProviderClients
+}
+
+type CoverageReportDataSourceModel struct {
+	Hostnames tfTypes.List   `tfsdk:"hostnames"`
+	Coverage  tfTypes.List   `tfsdk:"coverage"`
+	Uncovered tfTypes.List   `tfsdk:"uncovered"`
+	ID        tfTypes.String `tfsdk:"id"`
+}
+
+// CoverageModel is one element of the coverage report's coverage list.
+type CoverageModel struct {
+	Hostname        tfTypes.String `tfsdk:"hostname"`
+	CertificateArns tfTypes.List   `tfsdk:"certificate_arns"`
+}
+
+func coverageAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"hostname":         tfTypes.StringType,
+		"certificate_arns": tfTypes.ListType{ElemType: tfTypes.StringType},
+	}
+}
+
+func NewCoverageReportDataSource() datasource.DataSource {
+	return &CoverageReportDataSource{}
+}
+
+func (d *CoverageReportDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_coverage_report"
+}
+
+func (d *CoverageReportDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Report which issued certificates imported by this provider cover each of a list of hostnames, " +
+			"and which hostnames no certificate covers.",
+		Attributes: map[string]schema.Attribute{
+			"hostnames": schema.ListAttribute{
+				Description: "The hostnames to look for.",
+				ElementType: tfTypes.StringType,
+				Required:    true,
+				Validators: []validator.List{
+					validHostname(),
+				},
+			},
+			"coverage": schema.ListNestedAttribute{
+				Description: "One entry per hostname, in the order given.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"hostname": schema.StringAttribute{
+							Description: "The hostname, normalized.",
+							Computed:    true,
+						},
+						"certificate_arns": schema.ListAttribute{
+							Description: "ARNs of the certificates that cover the hostname, exactly or with a wildcard, " +
+								"newest first. Empty if none do.",
+							ElementType: tfTypes.StringType,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"uncovered": schema.ListAttribute{
+				Description: "The hostnames no certificate covers, in the order given.",
+				ElementType: tfTypes.StringType,
+				Computed:    true,
+			},
+			"id": schema.StringAttribute{
+				Description: "Data source identifier.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *CoverageReportDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*ProviderClients)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderClients, got: %T", req.ProviderData),
+		)
+		return
+	}
+	d.clients = clients
+}
+
+func (d *CoverageReportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CoverageReportDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var hostnames []string
+	resp.Diagnostics.Append(data.Hostnames.ElementsAs(ctx, &hostnames, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	hostnames = normalizeHostnames(hostnames)
+
+	covering, err := d.coveringCertificates(ctx, hostnames)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to search for certificates", err.Error())
+		return
+	}
+
+	coverage := make([]CoverageModel, len(hostnames))
+	uncovered := []string{}
+	for i, hostname := range hostnames {
+		coverage[i] = CoverageModel{
+			Hostname:        tfTypes.StringValue(hostname),
+			CertificateArns: certificateArnList(covering[hostname]),
+		}
+		if len(covering[hostname]) == 0 {
+			uncovered = append(uncovered, hostname)
+		}
+	}
+	coverageList, diags := tfTypes.ListValueFrom(ctx, tfTypes.ObjectType{AttrTypes: coverageAttrTypes()}, coverage)
+	resp.Diagnostics.Append(diags...)
+	uncoveredList, diags := tfTypes.ListValueFrom(ctx, tfTypes.StringType, uncovered)
+	resp.Diagnostics.Append(diags...)
+	data.Coverage = coverageList
+	data.Uncovered = uncoveredList
+	data.ID = tfTypes.StringValue(strings.Join(hostnames, ","))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// coveringCertificates returns, for each of hostnames, the ARNs of the
+// issued P-256 certificates carrying the provider's ownership marker that
+// cover it, newest first. Each certificate's tags are only read if it covers
+// one of hostnames.
+func (d *CoverageReportDataSource) coveringCertificates(ctx context.Context, hostnames []string) (map[string][]string, error) {
+	summaries, err := d.clients.CertificateList.issuedP256(ctx, d.clients.ACMClient)
+	if err != nil {
+		return nil, err
+	}
+
+	unique := slices.Clone(hostnames)
+	slices.Sort(unique)
+	unique = slices.Compact(unique)
+	covering := map[string][]string{}
+	for _, cert := range summaries {
+		sans, err := d.subjectAlternativeNames(ctx, cert)
+		if err != nil {
+			return nil, err
+		}
+		var covered []string
+		for _, hostname := range unique {
+			if slices.ContainsFunc(sans, func(san string) bool { return sanCovers(san, hostname) }) {
+				covered = append(covered, hostname)
+			}
+		}
+		if len(covered) == 0 {
+			continue
+		}
+		arn := aws.ToString(cert.CertificateArn)
+		owned, err := d.clients.hasOwnershipMarker(ctx, arn)
+		if err != nil {
+			return nil, err
+		}
+		if !owned {
+			continue
+		}
+		for _, hostname := range covered {
+			covering[hostname] = append(covering[hostname], arn)
+		}
+	}
+	return covering, nil
+}
+
+// subjectAlternativeNames returns every name cert covers. Listings only
+// include the first 100, so certificates with more are described.
+func (d *CoverageReportDataSource) subjectAlternativeNames(ctx context.Context, cert types.CertificateSummary) ([]string, error) {
+	if !aws.ToBool(cert.HasAdditionalSubjectAlternativeNames) {
+		return append([]string{aws.ToString(cert.DomainName)}, cert.SubjectAlternativeNameSummaries...), nil
+	}
+	output, err := d.clients.ACMClient.DescribeCertificate(ctx, &acm.DescribeCertificateInput{
+		CertificateArn: cert.CertificateArn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe %s: %w", aws.ToString(cert.CertificateArn), err)
+	}
+	return output.Certificate.SubjectAlternativeNames, nil
+}
+
+// sanCovers reports whether a certificate with the subject alternative name
+// san covers hostname: the names are equal, or san is a wildcard for
+// hostname's parent domain. A wildcard matches exactly one label.
+func sanCovers(san, hostname string) bool {
+	san = normalizeHostname(san)
+	if san == hostname {
+		return true
+	}
+	parent, ok := strings.CutPrefix(san, "*.")
+	if !ok {
+		return false
+	}
+	label, rest, found := strings.Cut(hostname, ".")
+	return found && label != "" && label != "*" && rest == parent
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+func TestCoveringCertificates(t *testing.T) {
+	owned := map[string]string{ownershipMarkerTag: "true"}
+	fake := &fakeACM{pageSize: 100, tags: map[string]map[string]string{"arn:wildcard": owned, "arn:exact": owned}}
+	fake.summaries = []types.CertificateSummary{
+		{CertificateArn: aws.String("arn:wildcard"), DomainName: aws.String("example.com"), SubjectAlternativeNameSummaries: []string{"example.com", "*.example.com"}},
+		{CertificateArn: aws.String("arn:unowned"), DomainName: aws.String("www.example.com"), SubjectAlternativeNameSummaries: []string{"www.example.com"}},
+		{CertificateArn: aws.String("arn:exact"), DomainName: aws.String("www.example.com"), SubjectAlternativeNameSummaries: []string{"www.example.com"}},
+	}
+	d := &CoverageReportDataSource{clients: &ProviderClients{ACMClient: fake, CertificateList: newCertificateListCache()}}
+
+	covering, err := d.coveringCertificates(context.Background(), []string{"www.example.com", "a.b.example.com", "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"www.example.com": {"arn:wildcard", "arn:exact"},
+		"example.com":     {"arn:wildcard"},
+	}
+	if len(covering) != len(want) {
+		t.Fatalf("covering = %v, want %v", covering, want)
+	}
+	for hostname, arns := range want {
+		if !slices.Equal(covering[hostname], arns) {
+			t.Errorf("covering[%q] = %v, want %v", hostname, covering[hostname], arns)
+		}
+	}
+}
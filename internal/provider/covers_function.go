@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &CoversFunction{}
+
+// CoversFunction reports whether a PEM certificate is valid for a hostname.
+type CoversFunction struct{}
+
+func NewCoversFunction() function.Function {
+	return &CoversFunction{}
+}
+
+func (f *CoversFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "covers"
+}
+
+func (f *CoversFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks whether a certificate covers a hostname.",
+		Description: "Returns true when the first certificate in a PEM string is valid for the hostname according to its " +
+			"subject alternative names. A wildcard such as *.example.com matches exactly one label, so it covers " +
+			"www.example.com but not example.com or a.b.example.com. The common name is ignored, as browsers do.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "pem",
+				Description: "A PEM encoded certificate.",
+			},
+			function.StringParameter{
+				Name:        "hostname",
+				Description: "The hostname or IP address to check.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *CoversFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var certPEM, hostname string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &certPEM, &hostname))
+	if resp.Error != nil {
+		return
+	}
+
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	// VerifyHostname applies the RFC 6125 matching rules TLS clients use.
+	covered := cert.VerifyHostname(hostname) == nil
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, covered))
+}
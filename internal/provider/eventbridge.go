@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// eventBridgeSource is the source of every event the provider puts.
+const eventBridgeSource = "cfcert"
+
+// eventBridgeClient puts certificate lifecycle events on EventBridge buses.
+type eventBridgeClient struct {
+	api *awsJSONClient
+}
+
+// putEvent puts one event with the given detail type and JSON detail on
+// busName, which may be a bus name or ARN.
+func (c *eventBridgeClient) putEvent(ctx context.Context, busName, detailType string, detail interface{}) error {
+	detailJSON, err := json.Marshal(detail)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event detail: %w", err)
+	}
+	var out struct {
+		FailedEntryCount int `json:"FailedEntryCount"`
+		Entries          []struct {
+			ErrorCode    string `json:"ErrorCode"`
+			ErrorMessage string `json:"ErrorMessage"`
+		} `json:"Entries"`
+	}
+	err = c.api.call(ctx, "PutEvents", map[string]interface{}{
+		"Entries": []map[string]interface{}{{
+			"EventBusName": busName,
+			"Source":       eventBridgeSource,
+			"DetailType":   detailType,
+			"Detail":       string(detailJSON),
+		}},
+	}, &out)
+	if err != nil {
+		return err
+	}
+	// PutEvents reports rejected entries in a successful response.
+	if out.FailedEntryCount > 0 {
+		for _, entry := range out.Entries {
+			if entry.ErrorCode != "" {
+				return fmt.Errorf("event rejected: %s: %s", entry.ErrorCode, entry.ErrorMessage)
+			}
+		}
+		return fmt.Errorf("event rejected")
+	}
+	return nil
+}
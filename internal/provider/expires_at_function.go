@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &ExpiresAtFunction{}
+
+// ExpiresAtFunction returns the NotAfter time of a PEM certificate.
+type ExpiresAtFunction struct{}
+
+func NewExpiresAtFunction() function.Function {
+	return &ExpiresAtFunction{}
+}
+
+func (f *ExpiresAtFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "expires_at"
+}
+
+func (f *ExpiresAtFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Returns when a certificate expires.",
+		Description: "Parses the first certificate in a PEM string and returns its NotAfter time in RFC 3339 format.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "pem",
+				Description: "A PEM encoded certificate.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ExpiresAtFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var certPEM string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &certPEM))
+	if resp.Error != nil {
+		return
+	}
+
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, cert.NotAfter.UTC().Format(time.RFC3339)))
+}
+
+// parseCertificatePEM parses the first CERTIFICATE block in certPEM.
+func parseCertificatePEM(certPEM string) (*x509.Certificate, error) {
+	rest := []byte(certPEM)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM encoded certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse certificate: %w", err)
+			}
+			return cert, nil
+		}
+	}
+}
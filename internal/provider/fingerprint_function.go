@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // SHA-1 fingerprints are still used for pinning and thumbprints
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &FingerprintFunction{}
+
+// FingerprintFunction returns the hex digest of a PEM certificate's DER
+// encoding.
+type FingerprintFunction struct{}
+
+func NewFingerprintFunction() function.Function {
+	return &FingerprintFunction{}
+}
+
+func (f *FingerprintFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "fingerprint"
+}
+
+func (f *FingerprintFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns a certificate fingerprint.",
+		Description: "Parses the first certificate in a PEM string and returns the lowercase hex digest of its DER encoding " +
+			"using the given algorithm, \"sha256\" or \"sha1\".",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "pem",
+				Description: "A PEM encoded certificate.",
+			},
+			function.StringParameter{
+				Name:        "algo",
+				Description: "The digest algorithm: \"sha256\" or \"sha1\".",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *FingerprintFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var certPEM, algo string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &certPEM, &algo))
+	if resp.Error != nil {
+		return
+	}
+
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	var fingerprint string
+	switch strings.ReplaceAll(strings.ToLower(algo), "-", "") {
+	case "sha256":
+		fingerprint = fingerprintDER(cert.Raw)
+	case "sha1":
+		sum := sha1.Sum(cert.Raw) //nolint:gosec
+		fingerprint = hex.EncodeToString(sum[:])
+	default:
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(1,
+			fmt.Sprintf("unsupported algorithm %q, expected \"sha256\" or \"sha1\"", algo)))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, fingerprint))
+}
@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	gcpCertificateManagerEndpoint = "https://certificatemanager.googleapis.com/v1/"
+	gcpMetadataTokenURL           = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	gcpCloudPlatformScope         = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// errGCPNotFound is returned when a Google API responds with 404.
+var errGCPNotFound = errors.New("not found")
+
+// gcpClient calls Google Cloud REST APIs. Access tokens are taken, in order,
+// from an explicit token, a service account key file, or the GCE metadata
+// server.
+type gcpClient struct {
+	Project         string
+	AccessToken     string
+	CredentialsFile string
+	httpClient      *http.Client
+	// endpoint overrides gcpCertificateManagerEndpoint in tests.
+	endpoint string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func (c *gcpClient) client() *http.Client {
+	if c.httpClient == nil {
+		return newLoggingHTTPClient("Google Cloud", nil)
+	}
+	return c.httpClient
+}
+
+func (c *gcpClient) certificateManagerEndpoint() string {
+	if c.endpoint == "" {
+		return gcpCertificateManagerEndpoint
+	}
+	return c.endpoint
+}
+
+func (c *gcpClient) accessToken(ctx context.Context) (string, error) {
+	if c.AccessToken != "" {
+		return c.AccessToken, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Now().Before(c.tokenExpiry.Add(-time.Minute)) {
+		return c.token, nil
+	}
+
+	var token string
+	var expiresIn int
+	var err error
+	if c.CredentialsFile != "" {
+		token, expiresIn, err = c.serviceAccountToken(ctx)
+	} else {
+		token, expiresIn, err = c.metadataToken(ctx)
+	}
+	if err != nil {
+		return "", err
+	}
+	c.token = token
+	c.tokenExpiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	return c.token, nil
+}
+
+type gcpTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func (c *gcpClient) metadataToken(ctx context.Context) (string, int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", gcpMetadataTokenURL, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	httpReq.Header.Set("Metadata-Flavor", "Google")
+
+	httpResp, err := c.client().Do(httpReq)
+	if err != nil {
+		return "", 0, fmt.Errorf("no Google credentials configured and metadata server unavailable: %w", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("metadata server returned status %d", httpResp.StatusCode)
+	}
+
+	var tokenResp gcpTokenResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&tokenResp); err != nil {
+		return "", 0, fmt.Errorf("failed to parse metadata token: %w", err)
+	}
+	return tokenResp.AccessToken, tokenResp.ExpiresIn, nil
+}
+
+// serviceAccountToken exchanges a self-signed JWT for an access token using a
+// service account key file.
+func (c *gcpClient) serviceAccountToken(ctx context.Context) (string, int, error) {
+	raw, err := os.ReadFile(c.CredentialsFile)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read Google credentials file: %w", err)
+	}
+	var key struct {
+		Type        string `json:"type"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return "", 0, fmt.Errorf("failed to parse Google credentials file: %w", err)
+	}
+	if key.Type != "service_account" {
+		return "", 0, fmt.Errorf("unsupported Google credentials type %q, expected service_account", key.Type)
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return "", 0, fmt.Errorf("invalid private key in Google credentials file")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse service account key: %w", err)
+	}
+	rsaKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", 0, fmt.Errorf("service account key is not an RSA key")
+	}
+
+	now := time.Now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   key.ClientEmail,
+		"scope": gcpCloudPlatformScope,
+		"aud":   key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to sign token request: %w", err)
+	}
+	assertion := unsigned + "." + base64.RawURLEncoding.EncodeToString(sig)
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", key.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpResp, err := c.client().Do(httpReq)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to request Google access token: %w", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("google token endpoint returned status %d", httpResp.StatusCode)
+	}
+
+	var tokenResp gcpTokenResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&tokenResp); err != nil {
+		return "", 0, fmt.Errorf("failed to parse Google access token: %w", err)
+	}
+	return tokenResp.AccessToken, tokenResp.ExpiresIn, nil
+}
+
+func (c *gcpClient) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResp, err := c.client().Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode == http.StatusNotFound {
+		return errGCPNotFound
+	}
+	if httpResp.StatusCode >= 300 {
+		var gcpErr struct {
+			Error struct {
+				Message string `json:"message"`
+				Status  string `json:"status"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &gcpErr) == nil && gcpErr.Error.Message != "" {
+			return fmt.Errorf("google API error (%s): %s", gcpErr.Error.Status, gcpErr.Error.Message)
+		}
+		return fmt.Errorf("google API error (status %d)", httpResp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+type gcpOperation struct {
+	Name  string `json:"name"`
+	Done  bool   `json:"done"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// wait polls a Certificate Manager long-running operation until it is done.
+func (c *gcpClient) wait(ctx context.Context, op gcpOperation) error {
+	for !op.Done {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+		if err := c.do(ctx, "GET", c.certificateManagerEndpoint()+op.Name, nil, &op); err != nil {
+			return err
+		}
+	}
+	if op.Error != nil {
+		return fmt.Errorf("operation %s failed: %s", op.Name, op.Error.Message)
+	}
+	return nil
+}
+
+type gcpSelfManagedCertificate struct {
+	PemCertificate string `json:"pemCertificate,omitempty"`
+	PemPrivateKey  string `json:"pemPrivateKey,omitempty"`
+}
+
+type gcpCertificate struct {
+	Name           string                     `json:"name,omitempty"`
+	Description    string                     `json:"description,omitempty"`
+	SelfManaged    *gcpSelfManagedCertificate `json:"selfManaged,omitempty"`
+	PemCertificate string                     `json:"pemCertificate,omitempty"`
+	ExpireTime     string                     `json:"expireTime,omitempty"`
+}
+
+func (c *gcpClient) createCertificate(ctx context.Context, parent, certificateID string, cert gcpCertificate) error {
+	var op gcpOperation
+	endpoint := c.certificateManagerEndpoint() + parent + "/certificates?certificateId=" + url.QueryEscape(certificateID)
+	if err := c.do(ctx, "POST", endpoint, cert, &op); err != nil {
+		return err
+	}
+	return c.wait(ctx, op)
+}
+
+func (c *gcpClient) getCertificate(ctx context.Context, name string) (gcpCertificate, error) {
+	var cert gcpCertificate
+	err := c.do(ctx, "GET", c.certificateManagerEndpoint()+name, nil, &cert)
+	return cert, err
+}
+
+func (c *gcpClient) deleteCertificate(ctx context.Context, name string) error {
+	var op gcpOperation
+	if err := c.do(ctx, "DELETE", c.certificateManagerEndpoint()+name, nil, &op); err != nil {
+		return err
+	}
+	return c.wait(ctx, op)
+}
@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &GCPCertificateResource{}
+var _ resource.ResourceWithConfigure = &GCPCertificateResource{}
+
+type GCPCertificateResource struct {
+	clients *ProviderClients
+}
+
+type GCPCertificateResourceModel struct {
+	DomainName     tfTypes.String `tfsdk:"domain_name"`
+	Name           tfTypes.String `tfsdk:"name"`
+	Project        tfTypes.String `tfsdk:"project"`
+	Location       tfTypes.String `tfsdk:"location"`
+	Description    tfTypes.String `tfsdk:"description"`
+	CertificatePEM tfTypes.String `tfsdk:"certificate_pem"`
+	ExpireTime     tfTypes.String `tfsdk:"expire_time"`
+	ID             tfTypes.String `tfsdk:"id"`
+}
+
+func NewGCPCertificateResource() resource.Resource {
+	return &GCPCertificateResource{}
+}
+
+func (r *GCPCertificateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gcp_certificate"
+}
+
+func (r *GCPCertificateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Issues a Cloudflare Origin Certificate and uploads it to Google Cloud Certificate Manager as a self-managed certificate.",
+		Attributes: map[string]schema.Attribute{
+			"domain_name": schema.StringAttribute{
+				Description: "The domain name for the certificate.",
+				Required:    true,
+				Validators: []validator.String{
+					validHostname(),
+				},
+				PlanModifiers: []planmodifier.String{
+					equivalentHostname(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The Certificate Manager certificate ID.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"project": schema.StringAttribute{
+				Description: "The Google Cloud project. Defaults to the provider google_project.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"location": schema.StringAttribute{
+				Description: "The Certificate Manager location. Defaults to \"global\".",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("global"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "A description of the certificate.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"certificate_pem": schema.StringAttribute{
+				Description: "The issued certificate in PEM format.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"expire_time": schema.StringAttribute{
+				Description: "The certificate expiry time reported by Certificate Manager (RFC 3339).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Description: "The full Certificate Manager resource name.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *GCPCertificateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*ProviderClients)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderClients, got: %T", req.ProviderData),
+		)
+		return
+	}
+	r.clients = clients
+}
+
+func (r *GCPCertificateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data GCPCertificateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project := data.Project.ValueString()
+	if data.Project.IsUnknown() || project == "" {
+		project = r.clients.GCPClient.Project
+	}
+	if project == "" {
+		resp.Diagnostics.AddError(
+			"Missing Google Cloud Project",
+			"project must be set on the resource, the provider google_project attribute, or the GOOGLE_PROJECT environment variable.",
+		)
+		return
+	}
+	data.Project = tfTypes.StringValue(project)
+
+	ctx = withDomainLogField(ctx, data.DomainName.ValueString())
+	issued, keyPEM, err := r.clients.issueWithLocalKey(ctx, []string{data.DomainName.ValueString()})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to issue certificate", err.Error())
+		return
+	}
+	defer zeroize(keyPEM)
+	certPEM := issued.Certificate
+
+	parent := fmt.Sprintf("projects/%s/locations/%s", project, data.Location.ValueString())
+	err = r.clients.GCPClient.createCertificate(ctx, parent, data.Name.ValueString(), gcpCertificate{
+		Description: data.Description.ValueString(),
+		SelfManaged: &gcpSelfManagedCertificate{
+			PemCertificate: certPEM,
+			PemPrivateKey:  string(keyPEM),
+		},
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to upload certificate to Certificate Manager", err.Error())
+		r.clients.revokeUnused(ctx, issued, &resp.Diagnostics)
+		return
+	}
+
+	// The certificate exists in Certificate Manager from here on, so it is
+	// saved to state before anything else can fail and orphan it.
+	parsed, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse issued certificate", err.Error())
+		return
+	}
+	name := parent + "/certificates/" + data.Name.ValueString()
+	data.ID = tfTypes.StringValue(name)
+	data.CertificatePEM = tfTypes.StringValue(certPEM)
+	data.ExpireTime = tfTypes.StringValue(parsed.NotAfter.UTC().Format(time.RFC3339))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cert, err := r.clients.GCPClient.getCertificate(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Failed to Read Uploaded Certificate",
+			fmt.Sprintf("%s was uploaded, but reading it back failed: %s. expire_time is taken from the certificate "+
+				"until the next refresh.", name, err),
+		)
+		return
+	}
+	data.ExpireTime = tfTypes.StringValue(cert.ExpireTime)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GCPCertificateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data GCPCertificateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cert, err := r.clients.GCPClient.getCertificate(ctx, data.ID.ValueString())
+	if errors.Is(err, errGCPNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read Certificate Manager certificate", err.Error())
+		return
+	}
+
+	data.ExpireTime = tfTypes.StringValue(cert.ExpireTime)
+
+	r.clients.warnIfExpiring(&resp.Diagnostics, data.DomainName.ValueString(), data.ExpireTime.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GCPCertificateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All configurable attributes force replacement, so Update is a no-op
+	var data GCPCertificateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GCPCertificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data GCPCertificateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.clients.GCPClient.deleteCertificate(ctx, data.ID.ValueString())
+	if err != nil && !errors.Is(err, errGCPNotFound) {
+		resp.Diagnostics.AddError("Failed to delete Certificate Manager certificate", err.Error())
+	}
+}
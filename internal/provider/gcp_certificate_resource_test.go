@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// createResource calls r.Create with a plan holding values, every other
+// attribute null, and returns the response.
+func createResource(t *testing.T, r resource.Resource, values map[string]tftypes.Value) *resource.CreateResponse {
+	t.Helper()
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	plan := objectValue(objectType, values)
+
+	req := resource.CreateRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: plan},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan},
+	}
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+	r.Create(ctx, req, resp)
+	return resp
+}
+
+func TestGCPCertificateCreate(t *testing.T) {
+	const (
+		name       = "projects/test-project/locations/global/certificates/example"
+		expireTime = "2040-01-01T00:00:00Z"
+	)
+	tests := []struct {
+		name       string
+		createFail bool
+		readFail   bool
+	}{
+		{name: "uploaded"},
+		{name: "upload fails", createFail: true},
+		{name: "read-back fails", readFail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := cloudflaretest.NewServer(t)
+			clients := newTestClients(mock)
+			gcp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && tt.createFail, r.Method == http.MethodGet && tt.readFail:
+					w.WriteHeader(http.StatusInternalServerError)
+					_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"status": "INTERNAL", "message": "failed"}})
+				case r.Method == http.MethodPost:
+					_ = json.NewEncoder(w).Encode(gcpOperation{Name: "operations/create", Done: true})
+				case r.Method == http.MethodGet && r.URL.Path == "/"+name:
+					_ = json.NewEncoder(w).Encode(gcpCertificate{Name: name, ExpireTime: expireTime})
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			t.Cleanup(gcp.Close)
+			clients.GCPClient = &gcpClient{AccessToken: "test-token", httpClient: gcp.Client(), endpoint: gcp.URL + "/"}
+
+			resp := createResource(t, &GCPCertificateResource{clients: clients}, map[string]tftypes.Value{
+				"domain_name": tftypes.NewValue(tftypes.String, "example.com"),
+				"name":        tftypes.NewValue(tftypes.String, "example"),
+				"project":     tftypes.NewValue(tftypes.String, "test-project"),
+				"location":    tftypes.NewValue(tftypes.String, "global"),
+			})
+
+			issued := mock.Issued()
+			if len(issued) != 1 {
+				t.Fatalf("issued %d certificates, want 1", len(issued))
+			}
+			if tt.createFail {
+				if !resp.Diagnostics.HasError() {
+					t.Fatal("Create did not report the failed upload")
+				}
+				if issued[0].RevokedAt == "" {
+					t.Error("the certificate that could not be uploaded was not revoked")
+				}
+				if !resp.State.Raw.IsNull() {
+					t.Error("a failed upload was saved to state")
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Create: %v", resp.Diagnostics)
+			}
+			if issued[0].RevokedAt != "" {
+				t.Error("the uploaded certificate was revoked")
+			}
+			var data GCPCertificateResourceModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+			if data.ID.ValueString() != name || data.CertificatePEM.ValueString() != issued[0].Certificate {
+				t.Errorf("state holds id %s and a different certificate, want %s and the issued certificate", data.ID, name)
+			}
+
+			want := expireTime
+			if tt.readFail {
+				if resp.Diagnostics.WarningsCount() != 1 {
+					t.Errorf("diagnostics = %v, want a warning about the failed read-back", resp.Diagnostics)
+				}
+				want = mustParseCertificatePEM(t, issued[0].Certificate).NotAfter.UTC().Format(time.RFC3339)
+			}
+			if !data.ExpireTime.Equal(tfTypes.StringValue(want)) {
+				t.Errorf("expire_time = %s, want %s", data.ExpireTime, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"golang.org/x/net/idna"
+)
+
+var _ planmodifier.String = equivalentHostnameModifier{}
+var _ planmodifier.List = equivalentHostnamesModifier{}
+
+// normalizeHostname returns the form of a hostname sent to Cloudflare and
+// compared against ACM: lower case, without a trailing dot, and with Unicode
+// labels in their IDNA (punycode) form. Hostnames IDNA rejects are only
+// lower cased, leaving Cloudflare to report the problem.
+func normalizeHostname(hostname string) string {
+	hostname = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostname)), ".")
+	wildcard := strings.HasPrefix(hostname, "*.")
+	ascii, err := idna.Lookup.ToASCII(strings.TrimPrefix(hostname, "*."))
+	if err != nil {
+		return hostname
+	}
+	if wildcard {
+		return "*." + ascii
+	}
+	return ascii
+}
+
+// hostnameProblem explains why Cloudflare Origin CA would reject hostname,
+// or returns "" if it would not. Wildcards are only accepted as the whole
+// leftmost label and one level deep, above a registrable domain.
+func hostnameProblem(hostname string) string {
+	normalized := normalizeHostname(hostname)
+	if normalized == "" {
+		return "hostnames must not be empty"
+	}
+	labels := strings.Split(normalized, ".")
+	for i, label := range labels {
+		if label == "" {
+			return "hostnames must not contain empty labels"
+		}
+		if !strings.Contains(label, "*") {
+			continue
+		}
+		if label != "*" {
+			return "a wildcard must be a whole label, as in *.example.com"
+		}
+		if i > 0 {
+			return "a wildcard may only cover one level, as the leftmost label"
+		}
+	}
+	if labels[0] == "*" && len(labels) < 3 {
+		return "a wildcard must be below a domain, as in *.example.com"
+	}
+	if _, err := idna.Lookup.ToASCII(strings.TrimPrefix(normalized, "*.")); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func normalizeHostnames(hostnames []string) []string {
+	normalized := make([]string, len(hostnames))
+	for i, hostname := range hostnames {
+		normalized[i] = normalizeHostname(hostname)
+	}
+	return normalized
+}
+
+// equivalentHostname keeps the prior state value when the configured
+// hostname only differs in case, a trailing dot or Unicode form, so such
+// edits do not plan a replacement. It must run before RequiresReplace.
+func equivalentHostname() planmodifier.String {
+	return equivalentHostnameModifier{}
+}
+
+type equivalentHostnameModifier struct{}
+
+func (m equivalentHostnameModifier) Description(ctx context.Context) string {
+	return "Ignores changes in hostname case, trailing dots and Unicode form."
+}
+
+func (m equivalentHostnameModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m equivalentHostnameModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+	if normalizeHostname(req.PlanValue.ValueString()) == normalizeHostname(req.StateValue.ValueString()) {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// equivalentHostnames is equivalentHostname for lists of hostnames.
+func equivalentHostnames() planmodifier.List {
+	return equivalentHostnamesModifier{}
+}
+
+type equivalentHostnamesModifier struct{}
+
+func (m equivalentHostnamesModifier) Description(ctx context.Context) string {
+	return "Ignores changes in hostname case, trailing dots and Unicode form."
+}
+
+func (m equivalentHostnamesModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m equivalentHostnamesModifier) PlanModifyList(ctx context.Context, req planmodifier.ListRequest, resp *planmodifier.ListResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+	var planned, prior []string
+	if req.PlanValue.ElementsAs(ctx, &planned, false).HasError() || req.StateValue.ElementsAs(ctx, &prior, false).HasError() {
+		return
+	}
+	if len(planned) != len(prior) {
+		return
+	}
+	for i := range planned {
+		if normalizeHostname(planned[i]) != normalizeHostname(prior[i]) {
+			return
+		}
+	}
+	resp.PlanValue = req.StateValue
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+)
+
+func TestNormalizeHostname(t *testing.T) {
+	tests := map[string]string{
+		"Example.COM":      "example.com",
+		"example.com.":     "example.com",
+		" example.com ":    "example.com",
+		"*.Example.com":    "*.example.com",
+		"bücher.example":   "xn--bcher-kva.example",
+		"*.bücher.example": "*.xn--bcher-kva.example",
+	}
+	for hostname, want := range tests {
+		if got := normalizeHostname(hostname); got != want {
+			t.Errorf("normalizeHostname(%q) = %q, want %q", hostname, got, want)
+		}
+	}
+}
+
+func TestHostnameProblem(t *testing.T) {
+	valid := []string{"example.com", "www.example.com", "*.example.com", "Example.com.", "bücher.example"}
+	for _, hostname := range valid {
+		if problem := hostnameProblem(hostname); problem != "" {
+			t.Errorf("hostnameProblem(%q) = %q, want none", hostname, problem)
+		}
+	}
+
+	invalid := []string{"", "example..com", "w*.example.com", "www.*.example.com", "*.com", "*"}
+	for _, hostname := range invalid {
+		if problem := hostnameProblem(hostname); problem == "" {
+			t.Errorf("hostnameProblem(%q) found no problem", hostname)
+		}
+	}
+}
+
+func TestHostnameChunks(t *testing.T) {
+	chunks := hostnameChunks("Example.com", []string{"www.example.com", "example.com.", "WWW.example.com"})
+	if want := [][]string{{"example.com", "www.example.com"}}; !slices.EqualFunc(chunks, want, slices.Equal) {
+		t.Errorf("hostnameChunks() = %v, want %v", chunks, want)
+	}
+
+	var hostnames []string
+	for i := range 2 * cloudflare.MaxHostnames {
+		hostnames = append(hostnames, fmt.Sprintf("host%d.example.com", i))
+	}
+	chunks = hostnameChunks("example.com", hostnames)
+	if len(chunks) != 3 || len(chunks[0]) != cloudflare.MaxHostnames || len(chunks[2]) != 1 {
+		t.Fatalf("got chunks of %d, want 3 with the domain first", len(chunks))
+	}
+	if chunks[0][0] != "example.com" {
+		t.Errorf("first hostname = %q, want example.com", chunks[0][0])
+	}
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// httpLogMaxBody caps how much of a request or response body is logged.
+const httpLogMaxBody = 16 << 10
+
+// redactedHeaders carry credentials and are never logged.
+var redactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Amz-Security-Token",
+	"X-Auth-Key",
+	"X-Auth-User-Service-Key",
+	"X-Vault-Token",
+}
+
+var (
+	// pemPattern matches PEM blocks, including ones JSON encoded with
+	// escaped newlines, so certificates, CSRs and keys are never logged.
+	pemPattern = regexp.MustCompile(`-----BEGIN ([A-Z0-9 ]+)-----[\s\S]*?-----END [A-Z0-9 ]+-----`)
+	// secretFieldPattern matches JSON fields holding key or certificate
+	// material that is not PEM encoded, such as the base64 encoded bodies
+	// ACM, Kubernetes and Key Vault accept, and credentials in token
+	// responses.
+	secretFieldPattern = regexp.MustCompile(`"(PrivateKey|Certificate|CertificateChain|certificate|private_key|csr|pemCertificate|pemPrivateKey|tls\.crt|tls\.key|value|access_token|client_secret|token)"(\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// secretFormPattern matches credentials in form encoded token requests.
+	secretFormPattern = regexp.MustCompile(`\b(client_secret|assertion|refresh_token)=[^&]*`)
+)
+
+// redactBody replaces key material, certificates and credentials in a
+// logged body.
+func redactBody(body string) string {
+	body = pemPattern.ReplaceAllString(body, "[REDACTED $1]")
+	body = secretFieldPattern.ReplaceAllString(body, `"$1"$2"[REDACTED]"`)
+	return secretFormPattern.ReplaceAllString(body, "$1=[REDACTED]")
+}
+
+// redactHeaders returns header with credentials replaced.
+func redactHeaders(header http.Header) map[string]string {
+	redacted := make(map[string]string, len(header))
+	for key, values := range header {
+		redacted[key] = strings.Join(values, ", ")
+	}
+	for _, key := range redactedHeaders {
+		if header.Get(key) != "" {
+			redacted[http.CanonicalHeaderKey(key)] = "[REDACTED]"
+		}
+	}
+	return redacted
+}
+
+// truncateLogBody shortens a body for logging.
+func truncateLogBody(body []byte) string {
+	if len(body) > httpLogMaxBody {
+		return strings.ToValidUTF8(string(body[:httpLogMaxBody]), "") + "...(truncated)"
+	}
+	return string(body)
+}
+
+// logHTTP sends req with send, logging the request and response at TRACE
+// level with credentials, keys and certificates redacted. Bodies are only
+// logged when they can be read without consuming the request.
+func logHTTP(service string, req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	ctx := req.Context()
+	fields := map[string]interface{}{
+		"service": service,
+		"method":  req.Method,
+		"url":     req.URL.Redacted(),
+		"headers": redactHeaders(req.Header),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, httpLogMaxBody+1))
+			body.Close()
+			fields["body"] = redactBody(truncateLogBody(data))
+		}
+	}
+	tflog.Trace(ctx, "Sending HTTP request", fields)
+
+	resp, err := send(req)
+	if err != nil {
+		tflog.Trace(ctx, "HTTP request failed", map[string]interface{}{"service": service, "error": err.Error()})
+		return resp, err
+	}
+	logHTTPResponse(ctx, service, resp)
+	return resp, nil
+}
+
+// logHTTPResponse logs resp, replacing its body with a copy of what was read.
+func logHTTPResponse(ctx context.Context, service string, resp *http.Response) {
+	fields := map[string]interface{}{
+		"service": service,
+		"status":  resp.Status,
+		"headers": redactHeaders(resp.Header),
+	}
+	if resp.Body != nil {
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		if err == nil {
+			fields["body"] = redactBody(truncateLogBody(data))
+		}
+	}
+	tflog.Trace(ctx, "Received HTTP response", fields)
+}
+
+// loggingTransport logs the requests it sends with logHTTP.
+type loggingTransport struct {
+	service string
+	next    http.RoundTripper
+}
+
+// newLoggingHTTPClient returns an HTTP client for service that logs through
+// logHTTP, sending with next or, when nil, the default transport.
+func newLoggingHTTPClient(service string, next http.RoundTripper) *http.Client {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &http.Client{Transport: &loggingTransport{service: service, next: next}}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return logHTTP(t.service, req, t.next.RoundTrip)
+}
+
+// loggingHTTPClient is loggingTransport for clients only available as an
+// httpDoer, such as the AWS SDK's.
+type loggingHTTPClient struct {
+	service string
+	next    httpDoer
+}
+
+func (c *loggingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return logHTTP(c.service, req, c.next.Do)
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	body := `{"csr":"-----BEGIN CERTIFICATE REQUEST-----\nMIIB\n-----END CERTIFICATE REQUEST-----","PrivateKey":"c2VjcmV0","hostnames":["example.com"]}`
+	redacted := redactBody(body)
+	for _, secret := range []string{"MIIB", "c2VjcmV0"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("redactBody() = %q, still contains %q", redacted, secret)
+		}
+	}
+	if !strings.Contains(redacted, "example.com") {
+		t.Errorf("redactBody() = %q, lost the hostnames", redacted)
+	}
+
+	form := redactBody("grant_type=client_credentials&client_secret=hunter2&scope=x")
+	if strings.Contains(form, "hunter2") {
+		t.Errorf("redactBody() = %q, still contains the client secret", form)
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret")
+	header.Set("X-Auth-User-Service-Key", "v1.0-secret")
+	header.Set("Content-Type", "application/json")
+
+	redacted := redactHeaders(header)
+	if redacted["Authorization"] != "[REDACTED]" || redacted["X-Auth-User-Service-Key"] != "[REDACTED]" {
+		t.Errorf("redactHeaders() = %v, want credentials redacted", redacted)
+	}
+	if redacted["Content-Type"] != "application/json" {
+		t.Errorf("redactHeaders() = %v, want Content-Type kept", redacted)
+	}
+}
@@ -0,0 +1,49 @@
+package provider
+
+import "sync"
+
+// issuanceLocks serialises certificate issuance per domain and alias within
+// one run. Two resources for the same domain would otherwise both issue and
+// import a certificate before either shows up in ACM; with the lock held, the
+// second reuses the certificate the first imported. Resources with different
+// aliases, such as blue and green deployments, each get their own.
+type issuanceLocks struct {
+	mu      sync.Mutex
+	domains map[issuanceKey]*domainIssuance
+}
+
+// issuanceKey identifies the certificates that may be shared: those for one
+// domain with one alias.
+type issuanceKey struct {
+	domain string
+	alias  string
+}
+
+// domainIssuance is held while a resource looks up or issues a certificate
+// for one domain and alias.
+type domainIssuance struct {
+	sync.Mutex
+	// arn is the certificate imported for the domain and alias earlier in
+	// the run, or "" if none has been.
+	arn string
+}
+
+func newIssuanceLocks() *issuanceLocks {
+	return &issuanceLocks{domains: map[issuanceKey]*domainIssuance{}}
+}
+
+// lock locks and returns the issuance for domain and alias; the caller
+// unlocks it.
+func (l *issuanceLocks) lock(domain, alias string) *domainIssuance {
+	key := issuanceKey{domain: normalizeHostname(domain), alias: alias}
+	l.mu.Lock()
+	issuance, ok := l.domains[key]
+	if !ok {
+		issuance = &domainIssuance{}
+		l.domains[key] = issuance
+	}
+	l.mu.Unlock()
+
+	issuance.Lock()
+	return issuance
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestIssuanceLocksKeyedByAlias(t *testing.T) {
+	locks := newIssuanceLocks()
+	blue := locks.lock("example.com", "blue")
+	blue.arn = "arn:aws:acm:us-east-1:123456789012:certificate/blue"
+	blue.Unlock()
+
+	green := locks.lock("example.com", "green")
+	defer green.Unlock()
+	if green.arn != "" {
+		t.Errorf("green issuance arn = %q, want none", green.arn)
+	}
+	again := locks.lock("Example.com", "blue")
+	defer again.Unlock()
+	if again.arn != blue.arn {
+		t.Errorf("blue issuance arn = %q, want %q", again.arn, blue.arn)
+	}
+}
+
+func TestForRoleIssuanceLocks(t *testing.T) {
+	clients := newTestClients(cloudflaretest.NewServer(t))
+	clients.AssumedRoles = newAssumedRoleClients()
+	role := AssumeRoleModel{RoleArn: tfTypes.StringValue("arn:aws:iam::210987654321:role/certificates")}
+
+	assumed := clients.forRole(role)
+	if assumed.Issuances == clients.Issuances {
+		t.Error("an assumed role shares the provider's issuance locks")
+	}
+	if clients.forRole(role).Issuances != assumed.Issuances {
+		t.Error("resources assuming the same role do not share issuance locks")
+	}
+}
@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &IssuancePreflightDataSource{}
+var _ datasource.DataSourceWithConfigure = &IssuancePreflightDataSource{}
+
+type IssuancePreflightDataSource struct {
+	clients *ProviderClients
+}
+
+type IssuancePreflightDataSourceModel struct {
+	Hostnames                tfTypes.List   `tfsdk:"hostnames"`
+	PlannedImports           tfTypes.Int64  `tfsdk:"planned_imports"`
+	Ready                    tfTypes.Bool   `tfsdk:"ready"`
+	Problems                 tfTypes.List   `tfsdk:"problems"`
+	Zones                    tfTypes.Map    `tfsdk:"zones"`
+	ImportedCertificates     tfTypes.Int64  `tfsdk:"imported_certificates"`
+	ImportedCertificateQuota tfTypes.Int64  `tfsdk:"imported_certificate_quota"`
+	ID                       tfTypes.String `tfsdk:"id"`
+}
+
+func NewIssuancePreflightDataSource() datasource.DataSource {
+	return &IssuancePreflightDataSource{}
+}
+
+func (d *IssuancePreflightDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_issuance_preflight"
+}
+
+func (d *IssuancePreflightDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Check, without issuing or importing anything, that certificates could be issued for a list of " +
+			"hostnames: that each hostname is valid, belongs to a zone the Cloudflare API token can read, that the token " +
+			"can read the zone's origin certificates, and that ACM has room for the imports. Problems are reported in " +
+			"problems rather than failing the read, so a migration can be rehearsed in one plan.",
+		Attributes: map[string]schema.Attribute{
+			"hostnames": schema.ListAttribute{
+				Description: "The hostnames to check. Invalid hostnames are reported in problems rather than rejected.",
+				ElementType: tfTypes.StringType,
+				Required:    true,
+			},
+			"planned_imports": schema.Int64Attribute{
+				Description: "How many certificates the rehearsed change will import into ACM, checked against the imported " +
+					"certificate quota. Defaults to one per 100 hostnames, as a single resource would split them.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64Between(0, 100000),
+				},
+			},
+			"ready": schema.BoolAttribute{
+				Description: "Whether every check passed.",
+				Computed:    true,
+			},
+			"problems": schema.ListAttribute{
+				Description: "One message per failed check. Empty when ready.",
+				ElementType: tfTypes.StringType,
+				Computed:    true,
+			},
+			"zones": schema.MapAttribute{
+				Description: "The Cloudflare zone of each hostname that has one, keyed by normalized hostname.",
+				ElementType: tfTypes.StringType,
+				Computed:    true,
+			},
+			"imported_certificates": schema.Int64Attribute{
+				Description: "How many imported certificates the account already has in this region, or null if ACM " +
+					"could not be listed.",
+				Computed: true,
+			},
+			"imported_certificate_quota": schema.Int64Attribute{
+				Description: "The account's ACM imported certificate quota, or null if ACM could not be listed.",
+				Computed:    true,
+			},
+			"id": schema.StringAttribute{
+				Description: "Data source identifier.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *IssuancePreflightDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*ProviderClients)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderClients, got: %T", req.ProviderData),
+		)
+		return
+	}
+	d.clients = clients
+}
+
+func (d *IssuancePreflightDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data IssuancePreflightDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var hostnames []string
+	resp.Diagnostics.Append(data.Hostnames.ElementsAs(ctx, &hostnames, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	hostnames = normalizeHostnames(hostnames)
+	plannedImports := (len(hostnames) + cloudflare.MaxHostnames - 1) / cloudflare.MaxHostnames
+	if !data.PlannedImports.IsNull() {
+		plannedImports = int(data.PlannedImports.ValueInt64())
+	}
+
+	result := d.preflight(ctx, hostnames, plannedImports)
+
+	problems, diags := tfTypes.ListValueFrom(ctx, tfTypes.StringType, result.problems)
+	resp.Diagnostics.Append(diags...)
+	zones, diags := tfTypes.MapValueFrom(ctx, tfTypes.StringType, result.zones)
+	resp.Diagnostics.Append(diags...)
+	data.Ready = tfTypes.BoolValue(len(result.problems) == 0)
+	data.Problems = problems
+	data.Zones = zones
+	data.ImportedCertificates = tfTypes.Int64Null()
+	data.ImportedCertificateQuota = tfTypes.Int64Null()
+	if result.usage != nil {
+		data.ImportedCertificates = tfTypes.Int64Value(int64(result.usage.imported))
+		data.ImportedCertificateQuota = tfTypes.Int64Value(int64(result.usage.quota))
+	}
+	data.ID = tfTypes.StringValue(strings.Join(hostnames, ","))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// preflightResult is the outcome of the issuance checks.
+type preflightResult struct {
+	problems []string
+	zones    map[string]string
+	// usage is nil when ACM could not be listed.
+	usage *acmImportUsage
+}
+
+// preflight runs every check against hostnames, collecting failures rather
+// than stopping at the first. Issuing needs Origin CA edit permission, which
+// cannot be tested without issuing; listing a zone's certificates at least
+// shows the token can reach the Origin CA API for it.
+func (d *IssuancePreflightDataSource) preflight(ctx context.Context, hostnames []string, plannedImports int) preflightResult {
+	result := preflightResult{problems: []string{}, zones: map[string]string{}}
+
+	var valid []string
+	for _, hostname := range hostnames {
+		if problem := hostnameProblem(hostname); problem != "" {
+			result.problems = append(result.problems, fmt.Sprintf("%s: Cloudflare Origin CA will not issue a certificate for it: %s.", hostname, problem))
+			continue
+		}
+		valid = append(valid, hostname)
+	}
+
+	if d.clients.CloudflareAPIToken == "" {
+		result.problems = append(result.problems, "Zone ownership and token scope cannot be checked with the Origin CA service key; "+
+			"set cloudflare_api_token, with Zone Read and SSL and Certificates permissions, to check them.")
+	} else {
+		zones := map[string]*cloudflare.Zone{}
+		listed := map[string]bool{}
+		for _, hostname := range valid {
+			zone, err := d.clients.findCloudflareZone(ctx, hostname, zones)
+			if err != nil {
+				result.problems = append(result.problems, fmt.Sprintf("%s: failed to look up its Cloudflare zone, check the API token "+
+					"has Zone Read permission: %s", hostname, err))
+				continue
+			}
+			if zone == nil {
+				result.problems = append(result.problems, fmt.Sprintf("%s: none of %s is a zone %s.",
+					hostname, strings.Join(zoneCandidates(hostname), ", "), d.clients.zoneScope()))
+				continue
+			}
+			result.zones[hostname] = zone.Name
+			if len(d.clients.AllowedZoneIDs) > 0 && !slices.Contains(d.clients.AllowedZoneIDs, zone.ID) {
+				result.problems = append(result.problems, fmt.Sprintf("%s: its zone %s (%s) is not in the provider's allowed_zone_ids.",
+					hostname, zone.Name, zone.ID))
+			}
+			if listed[zone.ID] {
+				continue
+			}
+			listed[zone.ID] = true
+			if _, err := d.clients.Cloudflare.List(ctx, zone.ID); err != nil {
+				result.problems = append(result.problems, fmt.Sprintf("%s: failed to list the zone's origin certificates, check the "+
+					"API token has SSL and Certificates permission on it: %s", zone.Name, err))
+			}
+		}
+	}
+
+	usage, err := d.clients.acmImportUsage(ctx)
+	if err != nil {
+		result.problems = append(result.problems, fmt.Sprintf("Failed to list ACM certificates, check the provider's AWS "+
+			"credentials can call acm:ListCertificates: %s", err))
+		return result
+	}
+	result.usage = &usage
+	if usage.imported+plannedImports > usage.quota {
+		result.problems = append(result.problems, fmt.Sprintf("Importing %d more certificates would exceed the ACM imported "+
+			"certificate quota. %s", plannedImports, usage))
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+)
+
+func TestIssuancePreflight(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	mock.AddZone("example.com")
+	clients := newTestClients(mock)
+	fake := &fakeACM{pageSize: 100}
+	for _, arn := range []string{"arn:1", "arn:2"} {
+		fake.summaries = append(fake.summaries, types.CertificateSummary{CertificateArn: aws.String(arn), Type: types.CertificateTypeImported})
+	}
+	clients.ACMClient = fake
+	quotas := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"Quotas":[{"QuotaName":"Imported certificates","Value":3}]}`))
+	}))
+	t.Cleanup(quotas.Close)
+	api := newAWSJSONClient(aws.Config{
+		Region: "us-east-1",
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test", SecretAccessKey: "test"}, nil
+		}),
+	}, "servicequotas", "ServiceQuotasV20190624")
+	api.endpoint = quotas.URL
+	clients.ServiceQuotasClient = &serviceQuotasClient{api: api}
+	d := &IssuancePreflightDataSource{clients: clients}
+	ctx := context.Background()
+
+	result := d.preflight(ctx, []string{"www.example.com", "a.*.example.com", "www.example.net"}, 1)
+	if len(result.problems) != 2 || !strings.HasPrefix(result.problems[0], "a.*.example.com:") ||
+		!strings.HasPrefix(result.problems[1], "www.example.net:") {
+		t.Errorf("problems = %q, want the invalid hostname and the one without a zone", result.problems)
+	}
+	if got := result.zones["www.example.com"]; got != "example.com" || len(result.zones) != 1 {
+		t.Errorf("zones = %v, want only www.example.com in example.com", result.zones)
+	}
+	if result.usage == nil || result.usage.imported != 2 || result.usage.quota != 3 {
+		t.Errorf("usage = %+v, want 2 of 3", result.usage)
+	}
+
+	result = d.preflight(ctx, []string{"www.example.com"}, 2)
+	if len(result.problems) != 1 || !strings.Contains(result.problems[0], "quota") {
+		t.Errorf("problems = %q, want the quota exceeded", result.problems)
+	}
+}
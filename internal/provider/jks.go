@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // the JKS format is defined in terms of SHA-1
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// JKS is Java's original, proprietary keystore format. It is encoded here
+// rather than through a dependency because only the single private key entry
+// case is needed.
+const (
+	jksMagic           = 0xFEEDFEED
+	jksVersion         = 2
+	jksPrivateKeyEntry = 1
+	jksDigestWhitener  = "Mighty Aphrodite"
+)
+
+// jksKeyProtectorOID identifies Sun's KeyProtector algorithm, which JKS uses
+// to encrypt private key entries.
+var jksKeyProtectorOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 42, 2, 17, 1, 1}
+
+type jksEncryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// encodeJKS returns a JKS keystore holding one private key entry under
+// alias, with the key from keyPEM and every certificate in certPEM, leaf
+// first, as its chain. Both the entry and the keystore are protected with
+// password.
+func encodeJKS(alias, password, certPEM string, keyPEM []byte, created time.Time) ([]byte, error) {
+	var chain [][]byte
+	rest := []byte(certPEM)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			chain = append(chain, block.Bytes)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+
+	key, err := parsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	defer zeroize(keyDER)
+
+	passwordBytes := jksPasswordBytes(password)
+	defer zeroize(passwordBytes)
+	protected, err := jksProtectKey(keyDER, passwordBytes)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err := asn1.Marshal(jksEncryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: jksKeyProtectorOID, Parameters: asn1.NullRawValue},
+		EncryptedData: protected,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode protected private key: %w", err)
+	}
+
+	var buf bytes.Buffer
+	writeUint32 := func(v uint32) { _ = binary.Write(&buf, binary.BigEndian, v) }
+	writeUint32(jksMagic)
+	writeUint32(jksVersion)
+	writeUint32(1)
+	writeUint32(jksPrivateKeyEntry)
+	// Java's JKS implementation lowercases aliases as entries are added and
+	// looked up, so a mixed case alias written as is could never be found.
+	if err := jksWriteUTF(&buf, strings.ToLower(alias)); err != nil {
+		return nil, err
+	}
+	_ = binary.Write(&buf, binary.BigEndian, created.UnixMilli())
+	writeUint32(uint32(len(encryptedKey)))
+	buf.Write(encryptedKey)
+	writeUint32(uint32(len(chain)))
+	for _, der := range chain {
+		if err := jksWriteUTF(&buf, "X.509"); err != nil {
+			return nil, err
+		}
+		writeUint32(uint32(len(der)))
+		buf.Write(der)
+	}
+
+	digest := sha1.New() //nolint:gosec // see import
+	digest.Write(passwordBytes)
+	digest.Write([]byte(jksDigestWhitener))
+	digest.Write(buf.Bytes())
+	buf.Write(digest.Sum(nil))
+	return buf.Bytes(), nil
+}
+
+// jksProtectKey encrypts a PKCS#8 key the way sun.security.provider's
+// KeyProtector does: XOR with a SHA-1 keystream seeded by a random salt,
+// followed by a SHA-1 check over the plaintext.
+func jksProtectKey(keyDER, passwordBytes []byte) ([]byte, error) {
+	salt := make([]byte, sha1.Size)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	protected := make([]byte, 0, 2*sha1.Size+len(keyDER))
+	protected = append(protected, salt...)
+	digest := salt
+	for offset := 0; offset < len(keyDER); offset += sha1.Size {
+		sum := sha1.Sum(append(append([]byte{}, passwordBytes...), digest...)) //nolint:gosec // see import
+		digest = sum[:]
+		for i := 0; i < sha1.Size && offset+i < len(keyDER); i++ {
+			protected = append(protected, keyDER[offset+i]^digest[i])
+		}
+	}
+	checked := append(append([]byte{}, passwordBytes...), keyDER...)
+	defer zeroize(checked)
+	check := sha1.Sum(checked) //nolint:gosec // see import
+	return append(protected, check[:]...), nil
+}
+
+// jksPasswordBytes encodes password as Java does for JKS: each UTF-16 code
+// unit as two big-endian bytes.
+func jksPasswordBytes(password string) []byte {
+	units := utf16.Encode([]rune(password))
+	out := make([]byte, 0, 2*len(units))
+	for _, unit := range units {
+		out = append(out, byte(unit>>8), byte(unit))
+	}
+	return out
+}
+
+// jksWriteUTF writes s in the modified UTF-8 of Java's DataOutput.writeUTF.
+func jksWriteUTF(buf *bytes.Buffer, s string) error {
+	var encoded []byte
+	for _, unit := range utf16.Encode([]rune(s)) {
+		switch {
+		case unit >= 0x01 && unit <= 0x7F:
+			encoded = append(encoded, byte(unit))
+		case unit <= 0x7FF:
+			encoded = append(encoded, byte(0xC0|unit>>6), byte(0x80|unit&0x3F))
+		default:
+			encoded = append(encoded, byte(0xE0|unit>>12), byte(0x80|(unit>>6)&0x3F), byte(0x80|unit&0x3F))
+		}
+	}
+	if len(encoded) > 0xFFFF {
+		return fmt.Errorf("%q is too long for a JKS keystore", s)
+	}
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(encoded)))
+	buf.Write(encoded)
+	return nil
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha1" //nolint:gosec // the JKS format is defined in terms of SHA-1
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+)
+
+func TestEncodeJKS(t *testing.T) {
+	clients := newTestClients(cloudflaretest.NewServer(t))
+	cert, keyPEM, err := clients.issueWithLocalKey(context.Background(), []string{"example.com"})
+	if err != nil {
+		t.Fatalf("issueWithLocalKey: %v", err)
+	}
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	keystore, err := encodeJKS("origin", "changeit", cert.Certificate, keyPEM, created)
+	if err != nil {
+		t.Fatalf("encodeJKS: %v", err)
+	}
+
+	body, sum := keystore[:len(keystore)-sha1.Size], keystore[len(keystore)-sha1.Size:]
+	passwordBytes := jksPasswordBytes("changeit")
+	want := sha1.Sum(append(append(passwordBytes, jksDigestWhitener...), body...)) //nolint:gosec // see import
+	if !bytes.Equal(sum, want[:]) {
+		t.Fatal("keystore integrity digest does not verify")
+	}
+
+	r := bytes.NewReader(body)
+	var header struct{ Magic, Version, Count, Tag uint32 }
+	var aliasLen uint16
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		t.Fatal(err)
+	}
+	if header != (struct{ Magic, Version, Count, Tag uint32 }{jksMagic, jksVersion, 1, jksPrivateKeyEntry}) {
+		t.Fatalf("header = %+v", header)
+	}
+	_ = binary.Read(r, binary.BigEndian, &aliasLen)
+	alias := make([]byte, aliasLen)
+	_, _ = r.Read(alias)
+	if string(alias) != "origin" {
+		t.Errorf("alias = %q, want origin", alias)
+	}
+	var millis int64
+	_ = binary.Read(r, binary.BigEndian, &millis)
+	if millis != created.UnixMilli() {
+		t.Errorf("timestamp = %d, want %d", millis, created.UnixMilli())
+	}
+	var keyLen uint32
+	_ = binary.Read(r, binary.BigEndian, &keyLen)
+	encoded := make([]byte, keyLen)
+	_, _ = r.Read(encoded)
+
+	var info jksEncryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(encoded, &info); err != nil {
+		t.Fatalf("EncryptedPrivateKeyInfo: %v", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(jksKeyProtectorOID) {
+		t.Errorf("algorithm = %v, want %v", info.Algorithm.Algorithm, jksKeyProtectorOID)
+	}
+
+	// Reverse KeyProtector to recover the PKCS#8 key.
+	protected := info.EncryptedData
+	salt, xored := protected[:sha1.Size], protected[sha1.Size:len(protected)-sha1.Size]
+	keyDER := make([]byte, len(xored))
+	digest := salt
+	for offset := 0; offset < len(xored); offset += sha1.Size {
+		next := sha1.Sum(append(append([]byte{}, passwordBytes...), digest...)) //nolint:gosec // see import
+		digest = next[:]
+		for i := 0; i < sha1.Size && offset+i < len(xored); i++ {
+			keyDER[offset+i] = xored[offset+i] ^ digest[i]
+		}
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyDER)
+	if err != nil {
+		t.Fatalf("recovered key: %v", err)
+	}
+	parsed, err := parseCertificatePEM(cert.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.(*ecdsa.PrivateKey).PublicKey.Equal(parsed.PublicKey) {
+		t.Error("recovered key does not match the certificate")
+	}
+
+	var typeLen uint16
+	var count uint32
+	_ = binary.Read(r, binary.BigEndian, &count)
+	if count != 1 {
+		t.Fatalf("chain length = %d, want 1", count)
+	}
+	_ = binary.Read(r, binary.BigEndian, &typeLen)
+	_, _ = r.Seek(int64(typeLen), io.SeekCurrent)
+	var certLen uint32
+	_ = binary.Read(r, binary.BigEndian, &certLen)
+	der := make([]byte, certLen)
+	_, _ = r.Read(der)
+	if !bytes.Equal(der, parsed.Raw) {
+		t.Error("chain certificate does not match certificate_pem")
+	}
+	if r.Len() != 0 {
+		t.Errorf("%d trailing bytes", r.Len())
+	}
+
+	if _, err := encodeJKS("origin", "changeit", "", keyPEM, created); err == nil {
+		t.Error("encodeJKS without a certificate succeeded")
+	}
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// KeyStore persists the certificates and private keys a certificate resource
+// issues, as a flat map of strings, at a location whose form depends on the
+// backend. Certificates are always imported into ACM; a key store keeps a
+// copy of the private key, which ACM will not export.
+type KeyStore interface {
+	// Name identifies the backend in diagnostics, such as "Vault".
+	Name() string
+	// Check reports provider configuration the backend is missing.
+	Check() error
+	Write(ctx context.Context, location string, data map[string]string) error
+	Read(ctx context.Context, location string) (map[string]string, error)
+	// Delete removes everything stored at location.
+	Delete(ctx context.Context, location string) error
+}
+
+// keyStoreTarget pairs the attribute naming a location in a key store with
+// the backend it refers to. A resource sets at most one of them, or none to
+// keep the private key only in ACM.
+type keyStoreTarget struct {
+	attribute string
+	store     func(*ProviderClients) KeyStore
+}
+
+var keyStoreTargets = []keyStoreTarget{
+	{attribute: "vault_kv_path", store: func(c *ProviderClients) KeyStore { return c.VaultClient }},
+	{attribute: "secrets_manager_secret_id", store: func(c *ProviderClients) KeyStore { return c.SecretsManagerClient }},
+	{attribute: "ssm_parameter_name", store: func(c *ProviderClients) KeyStore { return c.SSMClient }},
+	{attribute: "s3_object_uri", store: func(c *ProviderClients) KeyStore { return c.S3Client }},
+}
+
+// keyStoreAttributes returns the attributes of every key store target.
+func keyStoreAttributes() []string {
+	attributes := make([]string, len(keyStoreTargets))
+	for i, target := range keyStoreTargets {
+		attributes[i] = target.attribute
+	}
+	return attributes
+}
+
+// keyStore returns the key store the first set attribute in locations refers
+// to, and the location within it, or a nil KeyStore when none are set.
+func (c *ProviderClients) keyStore(locations map[string]tfTypes.String) (KeyStore, string) {
+	for _, target := range keyStoreTargets {
+		if location := locations[target.attribute].ValueString(); location != "" {
+			return target.store(c), location
+		}
+	}
+	return nil, ""
+}
+
+func (data CertificateResourceModel) keyStoreLocations() map[string]tfTypes.String {
+	return map[string]tfTypes.String{
+		"vault_kv_path":             data.VaultKVPath,
+		"secrets_manager_secret_id": data.SecretsManagerSecretID,
+		"ssm_parameter_name":        data.SSMParameterName,
+		"s3_object_uri":             data.S3ObjectURI,
+	}
+}
+
+func (data CertificateEphemeralResourceModel) keyStoreLocations() map[string]tfTypes.String {
+	return map[string]tfTypes.String{
+		"vault_kv_path":             data.VaultKVPath,
+		"secrets_manager_secret_id": data.SecretsManagerSecretID,
+		"ssm_parameter_name":        data.SSMParameterName,
+		"s3_object_uri":             data.S3ObjectURI,
+	}
+}
+
+func (data CertificateBundleEphemeralResourceModel) keyStoreLocations() map[string]tfTypes.String {
+	return map[string]tfTypes.String{
+		"vault_kv_path":             data.VaultKVPath,
+		"secrets_manager_secret_id": data.SecretsManagerSecretID,
+		"ssm_parameter_name":        data.SSMParameterName,
+		"s3_object_uri":             data.S3ObjectURI,
+	}
+}
+
+// keyStoreData lays out issued certificates for a key store. The first keeps
+// the unsuffixed keys; any others are suffixed with their index.
+func keyStoreData(domainName string, issued []issuedCertificate) map[string]string {
+	data := map[string]string{"domain_name": domainName}
+	for i, cert := range issued {
+		suffix := ""
+		if i > 0 {
+			suffix = fmt.Sprintf("_%d", i)
+		}
+		data["certificate_arn"+suffix] = cert.arn
+		data["certificate"+suffix] = cert.certPEM
+		data["private_key"+suffix] = string(cert.keyPEM)
+	}
+	return data
+}
+
+// readStoredCertificate reads the certificate and private key stored under
+// suffix at the key store location set in locations, as keyStoreData laid
+// them out. Failures are added to diags, and secret is nil.
+func (c *ProviderClients) readStoredCertificate(ctx context.Context, locations map[string]tfTypes.String, suffix string, diags *diag.Diagnostics) map[string]string {
+	keyStore, location := c.keyStore(locations)
+	if err := keyStore.Check(); err != nil {
+		diags.AddError(fmt.Sprintf("Missing %s Configuration", keyStore.Name()), err.Error())
+		return nil
+	}
+	secret, err := keyStore.Read(ctx, location)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to read certificate from %s", keyStore.Name()), err.Error())
+		return nil
+	}
+	if secret["certificate"+suffix] == "" || secret["private_key"+suffix] == "" {
+		diags.AddError(
+			fmt.Sprintf("Certificate Not Found in %s", keyStore.Name()),
+			fmt.Sprintf("%s does not contain certificate%s and private_key%s keys.", location, suffix, suffix),
+		)
+		return nil
+	}
+	return secret
+}
+
+// deleteStoredCertificate deletes the entry at location unless it now holds a
+// certificate other than arn, as it does once a replacement created before
+// this resource was destroyed has written to the same location. An entry
+// that cannot be read is deleted.
+func deleteStoredCertificate(ctx context.Context, keyStore KeyStore, location, arn string) error {
+	stored, err := keyStore.Read(ctx, location)
+	if err == nil && stored["certificate_arn"] != "" && stored["certificate_arn"] != arn {
+		tflog.Info(ctx, "Leaving key store entry holding another certificate", map[string]interface{}{
+			"location":        location,
+			"certificate_arn": stored["certificate_arn"],
+		})
+		return nil
+	}
+	return keyStore.Delete(ctx, location)
+}
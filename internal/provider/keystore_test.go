@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// memoryKeyStore is a KeyStore holding one entry per location in memory.
+type memoryKeyStore map[string]map[string]string
+
+func (m memoryKeyStore) Name() string { return "memory" }
+func (m memoryKeyStore) Check() error { return nil }
+
+func (m memoryKeyStore) Write(_ context.Context, location string, data map[string]string) error {
+	m[location] = data
+	return nil
+}
+
+func (m memoryKeyStore) Read(_ context.Context, location string) (map[string]string, error) {
+	data, ok := m[location]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func (m memoryKeyStore) Delete(_ context.Context, location string) error {
+	delete(m, location)
+	return nil
+}
+
+func TestDeleteStoredCertificate(t *testing.T) {
+	const (
+		oldArn = "arn:aws:acm:us-east-1:123456789012:certificate/old"
+		newArn = "arn:aws:acm:us-east-1:123456789012:certificate/new"
+	)
+	ctx := context.Background()
+	store := memoryKeyStore{"secret/example": {"certificate_arn": newArn}}
+
+	if err := deleteStoredCertificate(ctx, store, "secret/example", oldArn); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store["secret/example"]; !ok {
+		t.Error("deleted the entry a replacement certificate wrote")
+	}
+	if err := deleteStoredCertificate(ctx, store, "secret/example", newArn); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store["secret/example"]; ok {
+		t.Error("did not delete the entry holding the certificate")
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// kmsClient wraps the subset of the KMS API used to hold certificate keys.
+type kmsClient struct {
+	api *awsJSONClient
+}
+
+type kmsKeyMetadata struct {
+	Arn      string `json:"Arn"`
+	KeyId    string `json:"KeyId"`
+	KeyState string `json:"KeyState"`
+}
+
+func (c *kmsClient) createSigningKey(ctx context.Context, description string) (kmsKeyMetadata, error) {
+	var out struct {
+		KeyMetadata kmsKeyMetadata `json:"KeyMetadata"`
+	}
+	err := c.api.call(ctx, "CreateKey", map[string]interface{}{
+		"Description": description,
+		"KeySpec":     "ECC_NIST_P256",
+		"KeyUsage":    "SIGN_VERIFY",
+	}, &out)
+	return out.KeyMetadata, err
+}
+
+func (c *kmsClient) describeKey(ctx context.Context, keyID string) (kmsKeyMetadata, error) {
+	var out struct {
+		KeyMetadata kmsKeyMetadata `json:"KeyMetadata"`
+	}
+	err := c.api.call(ctx, "DescribeKey", map[string]interface{}{"KeyId": keyID}, &out)
+	return out.KeyMetadata, err
+}
+
+func (c *kmsClient) scheduleKeyDeletion(ctx context.Context, keyID string) error {
+	return c.api.call(ctx, "ScheduleKeyDeletion", map[string]interface{}{
+		"KeyId":               keyID,
+		"PendingWindowInDays": 7,
+	}, nil)
+}
+
+// signer returns a crypto.Signer whose private half lives in KMS.
+func (c *kmsClient) signer(ctx context.Context, keyID string) (crypto.Signer, error) {
+	var out struct {
+		PublicKey []byte `json:"PublicKey"`
+	}
+	if err := c.api.call(ctx, "GetPublicKey", map[string]interface{}{"KeyId": keyID}, &out); err != nil {
+		return nil, err
+	}
+	pub, err := x509.ParsePKIXPublicKey(out.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse KMS public key: %w", err)
+	}
+	return &kmsSigner{ctx: ctx, client: c, keyID: keyID, public: pub}, nil
+}
+
+type kmsSigner struct {
+	ctx    context.Context
+	client *kmsClient
+	keyID  string
+	public crypto.PublicKey
+}
+
+func (s *kmsSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s *kmsSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != crypto.SHA256 {
+		return nil, fmt.Errorf("unsupported hash function for KMS signing: %v", opts.HashFunc())
+	}
+	var out struct {
+		Signature []byte `json:"Signature"`
+	}
+	err := s.client.api.call(s.ctx, "Sign", map[string]interface{}{
+		"KeyId":            s.keyID,
+		"Message":          digest,
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": "ECDSA_SHA_256",
+	}, &out)
+	return out.Signature, err
+}
+
+func isKMSNotFoundError(err error) bool {
+	var apiErr *awsAPIError
+	return errors.As(err, &apiErr) && apiErr.Code == "NotFoundException"
+}
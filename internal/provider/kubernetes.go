@@ -0,0 +1,339 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errKubernetesNotFound is returned when the Kubernetes API responds with 404.
+var errKubernetesNotFound = errors.New("not found")
+
+// kubernetesExec describes a client-go style exec credential plugin, such as
+// "aws eks get-token".
+type kubernetesExec struct {
+	APIVersion string
+	Command    string
+	Args       []string
+	Env        map[string]string
+}
+
+// kubernetesClient calls the Kubernetes core API. Connection settings come
+// from explicit attributes or, when ConfigPath is set, from a kubeconfig file
+// flattened by kubectl.
+type kubernetesClient struct {
+	Host                 string
+	Token                string
+	ClusterCACertificate string
+	ClientCertificate    string
+	ClientKey            string
+	Exec                 *kubernetesExec
+	ConfigPath           string
+	ConfigContext        string
+
+	mu            sync.Mutex
+	loaded        bool
+	httpClient    *http.Client
+	execToken     string
+	execExpiry    time.Time
+	execHasExpiry bool
+}
+
+func (c *kubernetesClient) configured() bool {
+	return c != nil && (c.Host != "" || c.ConfigPath != "")
+}
+
+// kubeconfig is the subset of `kubectl config view --raw --minify -o json`
+// output used by the provider.
+type kubeconfig struct {
+	Clusters []struct {
+		Cluster struct {
+			Server                   string `json:"server"`
+			CertificateAuthorityData string `json:"certificate-authority-data"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+	Users []struct {
+		User struct {
+			Token                 string `json:"token"`
+			ClientCertificateData string `json:"client-certificate-data"`
+			ClientKeyData         string `json:"client-key-data"`
+			Exec                  *struct {
+				APIVersion string   `json:"apiVersion"`
+				Command    string   `json:"command"`
+				Args       []string `json:"args"`
+				Env        []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"env"`
+			} `json:"exec"`
+		} `json:"user"`
+	} `json:"users"`
+}
+
+func (c *kubernetesClient) loadConfigFile(ctx context.Context) error {
+	args := []string{"config", "view", "--raw", "--minify", "--flatten", "-o", "json", "--kubeconfig", c.ConfigPath}
+	if c.ConfigContext != "" {
+		args = append(args, "--context", c.ConfigContext)
+	}
+	kubectl, err := exec.LookPath("kubectl")
+	if err != nil {
+		return fmt.Errorf("kubernetes_config_path is read with kubectl, which was not found on PATH (%w). Install kubectl, "+
+			"or configure kubernetes_host, kubernetes_cluster_ca_certificate and kubernetes_token or kubernetes_exec instead", err)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, kubectl, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to read kubeconfig with kubectl: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var cfg kubeconfig
+	if err := json.Unmarshal(out, &cfg); err != nil {
+		return fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+	if len(cfg.Clusters) == 0 || len(cfg.Users) == 0 {
+		return fmt.Errorf("kubeconfig %s has no current cluster and user", c.ConfigPath)
+	}
+
+	cluster := cfg.Clusters[0].Cluster
+	user := cfg.Users[0].User
+	if c.Host == "" {
+		c.Host = cluster.Server
+	}
+	if c.ClusterCACertificate == "" && cluster.CertificateAuthorityData != "" {
+		ca, err := base64.StdEncoding.DecodeString(cluster.CertificateAuthorityData)
+		if err != nil {
+			return fmt.Errorf("invalid certificate-authority-data in kubeconfig: %w", err)
+		}
+		c.ClusterCACertificate = string(ca)
+	}
+	if c.Token == "" && c.Exec == nil {
+		c.Token = user.Token
+		if user.Exec != nil {
+			c.Exec = &kubernetesExec{
+				APIVersion: user.Exec.APIVersion,
+				Command:    user.Exec.Command,
+				Args:       user.Exec.Args,
+				Env:        map[string]string{},
+			}
+			for _, env := range user.Exec.Env {
+				c.Exec.Env[env.Name] = env.Value
+			}
+		}
+	}
+	if c.ClientCertificate == "" && user.ClientCertificateData != "" {
+		certData, err := base64.StdEncoding.DecodeString(user.ClientCertificateData)
+		if err != nil {
+			return fmt.Errorf("invalid client-certificate-data in kubeconfig: %w", err)
+		}
+		keyData, err := base64.StdEncoding.DecodeString(user.ClientKeyData)
+		if err != nil {
+			return fmt.Errorf("invalid client-key-data in kubeconfig: %w", err)
+		}
+		c.ClientCertificate = string(certData)
+		c.ClientKey = string(keyData)
+	}
+	return nil
+}
+
+func (c *kubernetesClient) init(ctx context.Context) error {
+	if c.loaded {
+		return nil
+	}
+	if c.ConfigPath != "" {
+		if err := c.loadConfigFile(ctx); err != nil {
+			return err
+		}
+	}
+	if c.Host == "" {
+		return fmt.Errorf("kubernetes host is not configured")
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.ClusterCACertificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.ClusterCACertificate)) {
+			return fmt.Errorf("kubernetes cluster CA certificate is not valid PEM")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.ClientCertificate != "" {
+		pair, err := tls.X509KeyPair([]byte(c.ClientCertificate), []byte(c.ClientKey))
+		if err != nil {
+			return fmt.Errorf("invalid kubernetes client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+	c.httpClient = newLoggingHTTPClient("Kubernetes", &http.Transport{TLSClientConfig: tlsConfig})
+	c.loaded = true
+	return nil
+}
+
+// bearerToken returns the static token or runs the exec plugin, caching its
+// token until the reported expiry.
+func (c *kubernetesClient) bearerToken(ctx context.Context) (string, error) {
+	if c.Exec == nil {
+		return c.Token, nil
+	}
+	if c.execToken != "" && (!c.execHasExpiry || time.Now().Before(c.execExpiry.Add(-time.Minute))) {
+		return c.execToken, nil
+	}
+
+	cmd := exec.CommandContext(ctx, c.Exec.Command, c.Exec.Args...)
+	cmd.Env = os.Environ()
+	for name, value := range c.Exec.Env {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+	apiVersion := c.Exec.APIVersion
+	if apiVersion == "" {
+		apiVersion = "client.authentication.k8s.io/v1beta1"
+	}
+	cmd.Env = append(cmd.Env, fmt.Sprintf(`KUBERNETES_EXEC_INFO={"apiVersion":%q,"kind":"ExecCredential","spec":{"interactive":false}}`, apiVersion))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("kubernetes exec credential plugin %s failed: %w: %s", c.Exec.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var cred struct {
+		Status struct {
+			Token               string `json:"token"`
+			ExpirationTimestamp string `json:"expirationTimestamp"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(out, &cred); err != nil {
+		return "", fmt.Errorf("failed to parse exec credential: %w", err)
+	}
+	c.execToken = cred.Status.Token
+	c.execHasExpiry = false
+	if expiry, err := time.Parse(time.RFC3339, cred.Status.ExpirationTimestamp); err == nil {
+		c.execExpiry = expiry
+		c.execHasExpiry = true
+	}
+	return c.execToken, nil
+}
+
+// prepare lazily builds the HTTP client and returns a current bearer token.
+func (c *kubernetesClient) prepare(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.init(ctx); err != nil {
+		return "", err
+	}
+	return c.bearerToken(ctx)
+}
+
+func (c *kubernetesClient) do(ctx context.Context, method, apiPath string, body, out interface{}) error {
+	token, err := c.prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.Host, "/")+apiPath, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode == http.StatusNotFound {
+		return errKubernetesNotFound
+	}
+	if httpResp.StatusCode >= 300 {
+		var status struct {
+			Message string `json:"message"`
+			Reason  string `json:"reason"`
+		}
+		if json.Unmarshal(respBody, &status) == nil && status.Message != "" {
+			return fmt.Errorf("kubernetes API error (%s): %s", status.Reason, status.Message)
+		}
+		return fmt.Errorf("kubernetes API error (status %d)", httpResp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+type kubernetesSecret struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name            string            `json:"name"`
+		Namespace       string            `json:"namespace"`
+		Labels          map[string]string `json:"labels,omitempty"`
+		UID             string            `json:"uid,omitempty"`
+		ResourceVersion string            `json:"resourceVersion,omitempty"`
+	} `json:"metadata"`
+	Type string            `json:"type"`
+	Data map[string][]byte `json:"data"`
+}
+
+func kubernetesSecretPath(namespace, name string) string {
+	p := "/api/v1/namespaces/" + url.PathEscape(namespace) + "/secrets"
+	if name != "" {
+		p += "/" + url.PathEscape(name)
+	}
+	return p
+}
+
+func (c *kubernetesClient) createSecret(ctx context.Context, secret kubernetesSecret) (kubernetesSecret, error) {
+	var created kubernetesSecret
+	err := c.do(ctx, "POST", kubernetesSecretPath(secret.Metadata.Namespace, ""), secret, &created)
+	return created, err
+}
+
+func (c *kubernetesClient) getSecret(ctx context.Context, namespace, name string) (kubernetesSecret, error) {
+	var secret kubernetesSecret
+	err := c.do(ctx, "GET", kubernetesSecretPath(namespace, name), nil, &secret)
+	return secret, err
+}
+
+func (c *kubernetesClient) deleteSecret(ctx context.Context, namespace, name string) error {
+	return c.do(ctx, "DELETE", kubernetesSecretPath(namespace, name), nil, nil)
+}
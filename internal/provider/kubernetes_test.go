@@ -0,0 +1,16 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestKubernetesConfigPathWithoutKubectl(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	c := &kubernetesClient{ConfigPath: "kubeconfig"}
+	_, err := c.prepare(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "kubectl, which was not found on PATH") {
+		t.Errorf("prepare() error = %v, want one saying kubectl is needed", err)
+	}
+}
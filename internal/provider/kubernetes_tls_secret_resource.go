@@ -0,0 +1,287 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &KubernetesTLSSecretResource{}
+var _ resource.ResourceWithConfigure = &KubernetesTLSSecretResource{}
+var _ resource.ResourceWithModifyPlan = &KubernetesTLSSecretResource{}
+
+// createdUIDKey is the private state key holding the UID of the secret the
+// resource created. Read sets uid to the live secret's UID, so a uid that
+// differs from it means the secret was recreated outside Terraform.
+const createdUIDKey = "created_uid"
+
+type KubernetesTLSSecretResource struct {
+	clients *ProviderClients
+}
+
+type KubernetesTLSSecretResourceModel struct {
+	DomainName     tfTypes.String `tfsdk:"domain_name"`
+	Name           tfTypes.String `tfsdk:"name"`
+	Namespace      tfTypes.String `tfsdk:"namespace"`
+	Labels         tfTypes.Map    `tfsdk:"labels"`
+	CertificatePEM tfTypes.String `tfsdk:"certificate_pem"`
+	UID            tfTypes.String `tfsdk:"uid"`
+	ID             tfTypes.String `tfsdk:"id"`
+}
+
+func NewKubernetesTLSSecretResource() resource.Resource {
+	return &KubernetesTLSSecretResource{}
+}
+
+func (r *KubernetesTLSSecretResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_kubernetes_tls_secret"
+}
+
+func (r *KubernetesTLSSecretResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Issues a Cloudflare Origin Certificate and writes it to a Kubernetes secret of type kubernetes.io/tls.",
+		Attributes: map[string]schema.Attribute{
+			"domain_name": schema.StringAttribute{
+				Description: "The domain name for the certificate.",
+				Required:    true,
+				Validators: []validator.String{
+					validHostname(),
+				},
+				PlanModifiers: []planmodifier.String{
+					equivalentHostname(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The secret name.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Description: "The secret namespace. Defaults to \"default\".",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("default"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"labels": schema.MapAttribute{
+				Description: "Labels to set on the secret.",
+				ElementType: tfTypes.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"certificate_pem": schema.StringAttribute{
+				Description: "The issued certificate in PEM format.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"uid": schema.StringAttribute{
+				Description: "The UID of the secret.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Description: "Resource identifier (<namespace>/<name>).",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *KubernetesTLSSecretResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*ProviderClients)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderClients, got: %T", req.ProviderData),
+		)
+		return
+	}
+	r.clients = clients
+}
+
+func (r *KubernetesTLSSecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data KubernetesTLSSecretResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.clients.KubernetesClient.configured() {
+		resp.Diagnostics.AddError(
+			"Missing Kubernetes Configuration",
+			"cfcert_kubernetes_tls_secret requires the provider kubernetes_host or kubernetes_config_path attribute, "+
+				"or the KUBE_HOST or KUBE_CONFIG_PATH environment variable.",
+		)
+		return
+	}
+
+	labels := map[string]string{}
+	resp.Diagnostics.Append(data.Labels.ElementsAs(ctx, &labels, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = withDomainLogField(ctx, data.DomainName.ValueString())
+	issued, keyPEM, err := r.clients.issueWithLocalKey(ctx, []string{data.DomainName.ValueString()})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to issue certificate", err.Error())
+		return
+	}
+	defer zeroize(keyPEM)
+	certPEM := issued.Certificate
+
+	secret := kubernetesSecret{APIVersion: "v1", Kind: "Secret", Type: "kubernetes.io/tls"}
+	secret.Metadata.Name = data.Name.ValueString()
+	secret.Metadata.Namespace = data.Namespace.ValueString()
+	secret.Metadata.Labels = labels
+	secret.Data = map[string][]byte{
+		"tls.crt": []byte(certPEM),
+		"tls.key": keyPEM,
+	}
+
+	created, err := r.clients.KubernetesClient.createSecret(ctx, secret)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create Kubernetes secret", err.Error())
+		return
+	}
+
+	data.ID = tfTypes.StringValue(secret.Metadata.Namespace + "/" + secret.Metadata.Name)
+	data.UID = tfTypes.StringValue(created.Metadata.UID)
+	data.CertificatePEM = tfTypes.StringValue(certPEM)
+
+	resp.Diagnostics.Append(setCreatedUID(ctx, resp.Private, created.Metadata.UID)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KubernetesTLSSecretResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data KubernetesTLSSecretResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secret, err := r.clients.KubernetesClient.getSecret(ctx, data.Namespace.ValueString(), data.Name.ValueString())
+	if errors.Is(err, errKubernetesNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read Kubernetes secret", err.Error())
+		return
+	}
+
+	// A secret recreated outside Terraform no longer holds this certificate.
+	// It stays in state under its new UID, and ModifyPlan replaces it: the
+	// name is taken, so a fresh create would fail with AlreadyExists.
+	createdUID, diags := getCreatedUID(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if createdUID == "" {
+		// Resources created before the UID was recorded.
+		createdUID = data.UID.ValueString()
+		resp.Diagnostics.Append(setCreatedUID(ctx, resp.Private, createdUID)...)
+	}
+	if secret.Metadata.UID != createdUID {
+		resp.Diagnostics.AddWarning(
+			"Kubernetes Secret Recreated Outside Terraform",
+			fmt.Sprintf("Secret %s was deleted and recreated outside Terraform, so it no longer holds the certificate "+
+				"Terraform issued. The next apply replaces it.", data.ID.ValueString()),
+		)
+	}
+	data.UID = tfTypes.StringValue(secret.Metadata.UID)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// ModifyPlan replaces a secret that Read found recreated outside Terraform.
+func (r *KubernetesTLSSecretResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	var state KubernetesTLSSecretResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	createdUID, diags := getCreatedUID(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || createdUID == "" || state.UID.ValueString() == createdUID {
+		return
+	}
+
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("uid"))
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("uid"), tfTypes.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certificate_pem"), tfTypes.StringUnknown())...)
+}
+
+func (r *KubernetesTLSSecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All configurable attributes force replacement, so Update is a no-op
+	var data KubernetesTLSSecretResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *KubernetesTLSSecretResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data KubernetesTLSSecretResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.clients.KubernetesClient.deleteSecret(ctx, data.Namespace.ValueString(), data.Name.ValueString())
+	if err != nil && !errors.Is(err, errKubernetesNotFound) {
+		resp.Diagnostics.AddError("Failed to delete Kubernetes secret", err.Error())
+	}
+}
+
+func setCreatedUID(ctx context.Context, private privateState, uid string) diag.Diagnostics {
+	value, err := json.Marshal(uid)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to encode secret UID", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, createdUIDKey, value)
+}
+
+// getCreatedUID returns the UID of the secret the resource created, or "" for
+// resources created before it was recorded.
+func getCreatedUID(ctx context.Context, private privateState) (string, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, createdUIDKey)
+	if diags.HasError() || len(value) == 0 {
+		return "", diags
+	}
+	var uid string
+	if err := json.Unmarshal(value, &uid); err != nil {
+		diags.AddError("Failed to decode secret UID", err.Error())
+	}
+	return uid, diags
+}
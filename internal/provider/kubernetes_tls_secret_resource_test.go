@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// fakeSecrets is a Kubernetes API holding secrets in one namespace, giving
+// each created secret a new UID.
+type fakeSecrets struct {
+	mu      sync.Mutex
+	uids    int
+	secrets map[string]kubernetesSecret
+}
+
+func (f *fakeSecrets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/namespaces/default/secrets")
+	name = strings.TrimPrefix(name, "/")
+	switch r.Method {
+	case http.MethodPost:
+		var secret kubernetesSecret
+		_ = json.NewDecoder(r.Body).Decode(&secret)
+		if _, ok := f.secrets[secret.Metadata.Name]; ok {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(map[string]string{"kind": "Status", "reason": "AlreadyExists"})
+			return
+		}
+		f.uids++
+		secret.Metadata.UID = fmt.Sprintf("uid-%d", f.uids)
+		f.secrets[secret.Metadata.Name] = secret
+		_ = json.NewEncoder(w).Encode(secret)
+	case http.MethodGet:
+		secret, ok := f.secrets[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(secret)
+	case http.MethodDelete:
+		if _, ok := f.secrets[name]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(f.secrets, name)
+	}
+}
+
+// recreate deletes and recreates name, as kubectl delete and apply would.
+func (f *fakeSecrets) recreate(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	secret := f.secrets[name]
+	f.uids++
+	secret.Metadata.UID = fmt.Sprintf("uid-%d", f.uids)
+	secret.Data = map[string][]byte{"tls.crt": []byte("other"), "tls.key": []byte("other")}
+	f.secrets[name] = secret
+}
+
+func TestKubernetesTLSSecretRecreatedOutsideTerraform(t *testing.T) {
+	secrets := &fakeSecrets{secrets: map[string]kubernetesSecret{}}
+	server := httptest.NewTLSServer(secrets)
+	t.Cleanup(server.Close)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	h := newProviderHarness(t, map[string]tftypes.Value{
+		"kubernetes_host":                   tftypes.NewValue(tftypes.String, server.URL),
+		"kubernetes_token":                  tftypes.NewValue(tftypes.String, "test-token"),
+		"kubernetes_cluster_ca_certificate": tftypes.NewValue(tftypes.String, string(caPEM)),
+	})
+	config := h.config("cfcert_kubernetes_tls_secret", map[string]tftypes.Value{
+		"domain_name": tftypes.NewValue(tftypes.String, "example.com"),
+		"name":        tftypes.NewValue(tftypes.String, "example-tls"),
+		"namespace":   tftypes.NewValue(tftypes.String, "default"),
+	})
+	res := h.create("cfcert_kubernetes_tls_secret", config)
+	if got := res.stringAttribute(t, "uid"); got != "uid-1" {
+		t.Fatalf("uid = %q, want uid-1", got)
+	}
+
+	h.refresh(res)
+	if plan := h.plan(res, config); len(plan.RequiresReplace) != 0 {
+		t.Errorf("plan for an unchanged secret requires replacing %v", plan.RequiresReplace)
+	}
+
+	secrets.recreate("example-tls")
+	h.refresh(res)
+	if res.state.IsNull() {
+		t.Fatal("refresh removed the recreated secret from state, so the next create would fail with AlreadyExists")
+	}
+	if got := res.stringAttribute(t, "uid"); got != "uid-2" {
+		t.Errorf("uid = %q after refresh, want the live uid-2", got)
+	}
+	plan := h.plan(res, config)
+	if len(plan.RequiresReplace) != 1 || plan.RequiresReplace[0].String() != tftypes.NewAttributePath().WithAttributeName("uid").String() {
+		t.Errorf("plan requires replacing %v, want uid", plan.RequiresReplace)
+	}
+
+	h.destroy(res)
+	replacement := h.create("cfcert_kubernetes_tls_secret", config)
+	if got := replacement.stringAttribute(t, "uid"); got != "uid-3" {
+		t.Errorf("replacement uid = %q, want uid-3", got)
+	}
+}
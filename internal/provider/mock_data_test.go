@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	cfcerttesting "github.com/envato/origin-certificate-provider/testing"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+var (
+	mockBlockPattern = regexp.MustCompile(`(?s)mock_(resource|data) "(\w+)" \{\s*defaults = \{(.*?)\n  \}`)
+	mockKeyPattern   = regexp.MustCompile(`(?m)^\s+(\w+)\s+=`)
+)
+
+// TestMockData checks that the mock data shipped for terraform test only
+// sets computed attributes the provider has, so it keeps up with the schema.
+func TestMockData(t *testing.T) {
+	server := providerserver.NewProtocol6(New("test")())()
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocks := mockBlockPattern.FindAllStringSubmatch(string(cfcerttesting.MockData), -1)
+	if len(blocks) == 0 {
+		t.Fatal("no mock_resource or mock_data blocks")
+	}
+	for _, block := range blocks {
+		kind, typeName, defaults := block[1], block[2], block[3]
+		schemas := resp.ResourceSchemas
+		if kind == "data" {
+			schemas = resp.DataSourceSchemas
+		}
+		schema, ok := schemas[typeName]
+		if !ok {
+			t.Errorf("mock_%s %q: provider has no such type", kind, typeName)
+			continue
+		}
+		computed := map[string]bool{}
+		for _, attr := range schema.Block.Attributes {
+			computed[attr.Name] = attr.Computed
+		}
+		for _, key := range mockKeyPattern.FindAllStringSubmatch(defaults, -1) {
+			if !computed[key[1]] {
+				t.Errorf("mock_%s %q sets %s, which is not a computed attribute", kind, typeName, key[1])
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+)
+
+func TestEmbeddedOriginCARoots(t *testing.T) {
+	var roots []*x509.Certificate
+	rest := embeddedOriginCARoots
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("origin_ca_roots.pem: %v", err)
+		}
+		roots = append(roots, cert)
+	}
+	if len(roots) == 0 {
+		t.Fatal("origin_ca_roots.pem holds no certificates; run go generate ./internal/provider")
+	}
+	for _, root := range roots {
+		if !root.IsCA || root.CheckSignatureFrom(root) != nil {
+			t.Errorf("%s is not a self-signed CA certificate", root.Subject)
+		}
+	}
+}
+
+func TestVerifyOriginCAChain(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	clients := newTestClients(mock)
+	clients.OriginCARoots = x509.NewCertPool()
+	clients.OriginCARoots.AddCert(mock.Root())
+	if _, _, err := clients.issueWithLocalKey(context.Background(), []string{"example.com"}); err != nil {
+		t.Fatalf("issueWithLocalKey with the issuing root: %v", err)
+	}
+
+	clients.OriginCARoots = x509.NewCertPool()
+	clients.OriginCARoots.AddCert(cloudflaretest.NewServer(t).Root())
+	_, _, err := clients.issueWithLocalKey(context.Background(), []string{"example.com"})
+	if err == nil || !strings.Contains(err.Error(), "does not chain to a Cloudflare Origin CA root") {
+		t.Errorf("issueWithLocalKey with another root = %v, want a chain error", err)
+	}
+}
+
+func TestLoadOriginCARootsFallsBack(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+	saved := originCARootURLs
+	originCARootURLs = []string{server.URL + "/origin_ca_ecc_root.pem"}
+	t.Cleanup(func() { originCARootURLs = saved })
+
+	roots, err := loadOriginCARoots(context.Background(), true, server.Client())
+	if err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("error = %v, want the failed fetch", err)
+	}
+	embedded := x509.NewCertPool()
+	if embedded.AppendCertsFromPEM(embeddedOriginCARoots) != (roots != nil) {
+		t.Error("did not fall back to the embedded roots")
+	}
+}
@@ -0,0 +1,386 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &OriginCertificateListDataSource{}
+var _ datasource.DataSourceWithConfigure = &OriginCertificateListDataSource{}
+
+type OriginCertificateListDataSource struct {
+	clients *ProviderClients
+}
+
+type OriginCertificateListDataSourceModel struct {
+	Zone                tfTypes.String `tfsdk:"zone"`
+	AccountID           tfTypes.String `tfsdk:"account_id"`
+	Matched             tfTypes.List   `tfsdk:"matched"`
+	UnmatchedCloudflare tfTypes.List   `tfsdk:"unmatched_cloudflare"`
+	UnmatchedACM        tfTypes.List   `tfsdk:"unmatched_acm"`
+	ID                  tfTypes.String `tfsdk:"id"`
+}
+
+// OriginCertificateMatchModel is one element of matched: a Cloudflare origin
+// certificate and the ACM import of it.
+type OriginCertificateMatchModel struct {
+	CloudflareID   tfTypes.String `tfsdk:"cloudflare_id"`
+	CertificateArn tfTypes.String `tfsdk:"certificate_arn"`
+	SerialNumber   tfTypes.String `tfsdk:"serial_number"`
+	Hostnames      tfTypes.List   `tfsdk:"hostnames"`
+	Revoked        tfTypes.Bool   `tfsdk:"revoked"`
+}
+
+// CloudflareCertificateModel is one element of unmatched_cloudflare.
+type CloudflareCertificateModel struct {
+	CloudflareID tfTypes.String `tfsdk:"cloudflare_id"`
+	SerialNumber tfTypes.String `tfsdk:"serial_number"`
+	Hostnames    tfTypes.List   `tfsdk:"hostnames"`
+	ExpiresOn    tfTypes.String `tfsdk:"expires_on"`
+}
+
+// ACMImportModel is one element of unmatched_acm.
+type ACMImportModel struct {
+	CertificateArn tfTypes.String `tfsdk:"certificate_arn"`
+	SerialNumber   tfTypes.String `tfsdk:"serial_number"`
+	DomainName     tfTypes.String `tfsdk:"domain_name"`
+}
+
+func originCertificateMatchAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"cloudflare_id":   tfTypes.StringType,
+		"certificate_arn": tfTypes.StringType,
+		"serial_number":   tfTypes.StringType,
+		"hostnames":       tfTypes.ListType{ElemType: tfTypes.StringType},
+		"revoked":         tfTypes.BoolType,
+	}
+}
+
+func cloudflareCertificateAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"cloudflare_id": tfTypes.StringType,
+		"serial_number": tfTypes.StringType,
+		"hostnames":     tfTypes.ListType{ElemType: tfTypes.StringType},
+		"expires_on":    tfTypes.StringType,
+	}
+}
+
+func acmImportAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"certificate_arn": tfTypes.StringType,
+		"serial_number":   tfTypes.StringType,
+		"domain_name":     tfTypes.StringType,
+	}
+}
+
+func NewOriginCertificateListDataSource() datasource.DataSource {
+	return &OriginCertificateListDataSource{}
+}
+
+func (d *OriginCertificateListDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_origin_certificate_list"
+}
+
+func (d *OriginCertificateListDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	serialNumber := schema.StringAttribute{
+		Description: "The certificate's serial number, in lower case hex.",
+		Computed:    true,
+	}
+	resp.Schema = schema.Schema{
+		Description: "Reconcile the origin certificates Cloudflare has issued for a zone with the Origin CA certificates " +
+			"imported into ACM, matching them by serial number and public key, and report entries missing from either side.",
+		Attributes: map[string]schema.Attribute{
+			"zone": schema.StringAttribute{
+				Description: "The Cloudflare zone whose certificates to reconcile, such as example.com. ACM imports are " +
+					"included when their domain name or a subject alternative name is in the zone.",
+				Required: true,
+				Validators: []validator.String{
+					validHostname(),
+				},
+			},
+			"account_id": schema.StringAttribute{
+				Description: "Cloudflare account ID to look the zone up in, overriding the provider's account_id, for API " +
+					"tokens that can access several accounts with a zone of the same name.",
+				Optional: true,
+			},
+			"matched": schema.ListNestedAttribute{
+				Description: "Cloudflare certificates imported into ACM. A certificate imported more than once appears once per ARN.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"cloudflare_id": schema.StringAttribute{
+							Description: "The Cloudflare certificate ID.",
+							Computed:    true,
+						},
+						"certificate_arn": schema.StringAttribute{
+							Description: "The ACM certificate ARN.",
+							Computed:    true,
+						},
+						"serial_number": serialNumber,
+						"hostnames": schema.ListAttribute{
+							Description: "The hostnames Cloudflare issued the certificate for.",
+							ElementType: tfTypes.StringType,
+							Computed:    true,
+						},
+						"revoked": schema.BoolAttribute{
+							Description: "Whether Cloudflare has revoked the certificate, which ACM does not detect.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"unmatched_cloudflare": schema.ListNestedAttribute{
+				Description: "Unrevoked Cloudflare certificates that are not imported into ACM in this account and region.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"cloudflare_id": schema.StringAttribute{
+							Description: "The Cloudflare certificate ID.",
+							Computed:    true,
+						},
+						"serial_number": serialNumber,
+						"hostnames": schema.ListAttribute{
+							Description: "The hostnames Cloudflare issued the certificate for.",
+							ElementType: tfTypes.StringType,
+							Computed:    true,
+						},
+						"expires_on": schema.StringAttribute{
+							Description: "When the certificate expires, as reported by Cloudflare.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"unmatched_acm": schema.ListNestedAttribute{
+				Description: "Origin CA certificates imported into ACM that Cloudflare does not list for the zone, such as " +
+					"certificates issued by another Cloudflare account.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"certificate_arn": schema.StringAttribute{
+							Description: "The ACM certificate ARN.",
+							Computed:    true,
+						},
+						"serial_number": serialNumber,
+						"domain_name": schema.StringAttribute{
+							Description: "The certificate's domain name.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				Description: "The Cloudflare zone ID.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *OriginCertificateListDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	clients, ok := req.ProviderData.(*ProviderClients)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderClients, got: %T", req.ProviderData),
+		)
+		return
+	}
+	d.clients = clients
+}
+
+func (d *OriginCertificateListDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data OriginCertificateListDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	zoneName := normalizeHostname(data.Zone.ValueString())
+	if accountID := data.AccountID.ValueString(); accountID != "" {
+		d = &OriginCertificateListDataSource{clients: d.clients.forCloudflareAccount(accountID)}
+	}
+
+	zone, found, err := d.clients.Cloudflare.Zone(ctx, zoneName)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to look up Cloudflare zone", err.Error())
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("zone"),
+			"Zone Not Found",
+			fmt.Sprintf("%s is not a zone %s.", zoneName, d.clients.zoneScope()),
+		)
+		return
+	}
+
+	report, err := d.reconcile(ctx, zone.ID, zoneName)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to reconcile certificates", err.Error())
+		return
+	}
+
+	matched, diags := tfTypes.ListValueFrom(ctx, tfTypes.ObjectType{AttrTypes: originCertificateMatchAttrTypes()}, report.matched)
+	resp.Diagnostics.Append(diags...)
+	unmatchedCloudflare, diags := tfTypes.ListValueFrom(ctx, tfTypes.ObjectType{AttrTypes: cloudflareCertificateAttrTypes()}, report.unmatchedCloudflare)
+	resp.Diagnostics.Append(diags...)
+	unmatchedACM, diags := tfTypes.ListValueFrom(ctx, tfTypes.ObjectType{AttrTypes: acmImportAttrTypes()}, report.unmatchedACM)
+	resp.Diagnostics.Append(diags...)
+	data.Matched = matched
+	data.UnmatchedCloudflare = unmatchedCloudflare
+	data.UnmatchedACM = unmatchedACM
+	data.ID = tfTypes.StringValue(zone.ID)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// originCertificateReport is the result of reconciling one zone.
+type originCertificateReport struct {
+	matched             []OriginCertificateMatchModel
+	unmatchedCloudflare []CloudflareCertificateModel
+	unmatchedACM        []ACMImportModel
+}
+
+// acmOriginImport is an Origin CA certificate imported into ACM.
+type acmOriginImport struct {
+	arn        string
+	domainName string
+	serial     string
+	identity   string
+}
+
+// reconcile pairs the zone's Cloudflare certificates with ACM imports that
+// have the same serial number and public key.
+func (d *OriginCertificateListDataSource) reconcile(ctx context.Context, zoneID, zoneName string) (originCertificateReport, error) {
+	var report originCertificateReport
+	certs, err := d.clients.Cloudflare.List(ctx, zoneID)
+	if err != nil {
+		return report, fmt.Errorf("failed to list Cloudflare origin certificates: %w", err)
+	}
+	imports, err := d.acmOriginImports(ctx, zoneName)
+	if err != nil {
+		return report, err
+	}
+
+	matchedArns := map[string]bool{}
+	for _, cert := range certs {
+		parsed, err := parseCertificatePEM(cert.Certificate)
+		if err != nil {
+			return report, fmt.Errorf("failed to parse Cloudflare certificate %s: %w", cert.ID, err)
+		}
+		serial := serialHex(parsed.SerialNumber)
+		identity := serial + "/" + keyFingerprint(cert.Certificate)
+		hostnames := stringList(cert.Hostnames)
+
+		matches := 0
+		for _, imported := range imports {
+			if imported.identity != identity {
+				continue
+			}
+			matches++
+			matchedArns[imported.arn] = true
+			report.matched = append(report.matched, OriginCertificateMatchModel{
+				CloudflareID:   tfTypes.StringValue(cert.ID),
+				CertificateArn: tfTypes.StringValue(imported.arn),
+				SerialNumber:   tfTypes.StringValue(serial),
+				Hostnames:      hostnames,
+				Revoked:        tfTypes.BoolValue(cert.RevokedAt != ""),
+			})
+		}
+		if matches == 0 && cert.RevokedAt == "" {
+			report.unmatchedCloudflare = append(report.unmatchedCloudflare, CloudflareCertificateModel{
+				CloudflareID: tfTypes.StringValue(cert.ID),
+				SerialNumber: tfTypes.StringValue(serial),
+				Hostnames:    hostnames,
+				ExpiresOn:    tfTypes.StringValue(cert.ExpiresOn),
+			})
+		}
+	}
+	for _, imported := range imports {
+		if !matchedArns[imported.arn] {
+			report.unmatchedACM = append(report.unmatchedACM, ACMImportModel{
+				CertificateArn: tfTypes.StringValue(imported.arn),
+				SerialNumber:   tfTypes.StringValue(imported.serial),
+				DomainName:     tfTypes.StringValue(imported.domainName),
+			})
+		}
+	}
+	return report, nil
+}
+
+// acmOriginImports returns the issued certificates imported into ACM that
+// Cloudflare's Origin CA issued for a name in zoneName. Origin CA issues
+// both RSA and ECDSA certificates, so this lists every key type rather than
+// using the P-256 listing cache.
+func (d *OriginCertificateListDataSource) acmOriginImports(ctx context.Context, zoneName string) ([]acmOriginImport, error) {
+	paginator := acm.NewListCertificatesPaginator(d.clients.ACMClient, &acm.ListCertificatesInput{
+		CertificateStatuses: []types.CertificateStatus{types.CertificateStatusIssued},
+		Includes: &types.Filters{
+			KeyTypes: []types.KeyAlgorithm{types.KeyAlgorithmRsa2048, types.KeyAlgorithmEcPrime256v1},
+		},
+	})
+	var imports []acmOriginImport
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ACM certificates: %w", err)
+		}
+		for _, summary := range page.CertificateSummaryList {
+			names := append([]string{aws.ToString(summary.DomainName)}, summary.SubjectAlternativeNameSummaries...)
+			if summary.Type != types.CertificateTypeImported || !slices.ContainsFunc(names, func(name string) bool { return inZone(name, zoneName) }) {
+				continue
+			}
+			arn := aws.ToString(summary.CertificateArn)
+			output, err := d.clients.ACMClient.GetCertificate(ctx, &acm.GetCertificateInput{CertificateArn: aws.String(arn)})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s: %w", arn, err)
+			}
+			certPEM := aws.ToString(output.Certificate)
+			parsed, err := parseCertificatePEM(certPEM)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", arn, err)
+			}
+			if !isCloudflareOriginCA(parsed) {
+				continue
+			}
+			serial := serialHex(parsed.SerialNumber)
+			imports = append(imports, acmOriginImport{
+				arn:        arn,
+				domainName: aws.ToString(summary.DomainName),
+				serial:     serial,
+				identity:   serial + "/" + keyFingerprint(certPEM),
+			})
+		}
+	}
+	return imports, nil
+}
+
+// inZone reports whether hostname, or the domain a wildcard hostname covers,
+// is zoneName or one of its subdomains.
+func inZone(hostname, zoneName string) bool {
+	name := strings.TrimPrefix(normalizeHostname(hostname), "*.")
+	return name == zoneName || strings.HasSuffix(name, "."+zoneName)
+}
+
+func stringList(values []string) tfTypes.List {
+	elements := make([]attr.Value, len(values))
+	for i, value := range values {
+		elements[i] = tfTypes.StringValue(value)
+	}
+	return tfTypes.ListValueMust(tfTypes.StringType, elements)
+}
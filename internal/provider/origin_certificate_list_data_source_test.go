@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+)
+
+// pemACM is a fakeACM that returns the PEM certificate stored for each ARN.
+type pemACM struct {
+	fakeACM
+	certificates map[string]string
+}
+
+func (f *pemACM) GetCertificate(_ context.Context, params *acm.GetCertificateInput, _ ...func(*acm.Options)) (*acm.GetCertificateOutput, error) {
+	return &acm.GetCertificateOutput{Certificate: aws.String(f.certificates[*params.CertificateArn])}, nil
+}
+
+func TestReconcileOriginCertificates(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	zoneID := mock.AddZone("example.com")
+	clients := newTestClients(mock)
+	other := newTestClients(cloudflaretest.NewServer(t))
+	ctx := context.Background()
+
+	issue := func(clients *ProviderClients, hostname string) cloudflare.OriginCert {
+		cert, _, err := clients.issueWithLocalKey(ctx, []string{hostname})
+		if err != nil {
+			t.Fatalf("issueWithLocalKey(%s): %v", hostname, err)
+		}
+		return cert
+	}
+	imported := issue(clients, "www.example.com")
+	notImported := issue(clients, "api.example.com")
+	revoked := issue(clients, "old.example.com")
+	if err := clients.Cloudflare.Revoke(ctx, revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+	fromOtherAccount := issue(other, "example.com")
+
+	fake := &pemACM{certificates: map[string]string{
+		"arn:imported": imported.Certificate,
+		"arn:other":    fromOtherAccount.Certificate,
+		"arn:outside":  issue(clients, "example.org").Certificate,
+	}}
+	fake.pageSize = 100
+	for _, summary := range []struct{ arn, domain string }{
+		{"arn:imported", "www.example.com"},
+		{"arn:other", "example.com"},
+		{"arn:outside", "example.org"},
+	} {
+		fake.summaries = append(fake.summaries, types.CertificateSummary{
+			CertificateArn: aws.String(summary.arn),
+			DomainName:     aws.String(summary.domain),
+			Type:           types.CertificateTypeImported,
+		})
+	}
+	clients.ACMClient = fake
+	d := &OriginCertificateListDataSource{clients: clients}
+
+	report, err := d.reconcile(ctx, zoneID, "example.com")
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(report.matched) != 1 || report.matched[0].CloudflareID.ValueString() != imported.ID ||
+		report.matched[0].CertificateArn.ValueString() != "arn:imported" {
+		t.Errorf("matched = %v, want %s as arn:imported", report.matched, imported.ID)
+	}
+	if len(report.unmatchedCloudflare) != 1 || report.unmatchedCloudflare[0].CloudflareID.ValueString() != notImported.ID {
+		t.Errorf("unmatched_cloudflare = %v, want only %s", report.unmatchedCloudflare, notImported.ID)
+	}
+	if len(report.unmatchedACM) != 1 || report.unmatchedACM[0].CertificateArn.ValueString() != "arn:other" {
+		t.Errorf("unmatched_acm = %v, want only arn:other", report.unmatchedACM)
+	}
+}
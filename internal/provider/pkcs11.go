@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// pkcs11Client generates and uses keys on a PKCS#11 token (such as CloudHSM)
+// by driving OpenSC's pkcs11-tool against the configured module. Raw key
+// bytes never leave the token; the provider only sees public keys and
+// signatures.
+type pkcs11Client struct {
+	ModulePath string
+	TokenLabel string
+	PIN        string
+	ToolPath   string
+}
+
+func (c *pkcs11Client) configured() bool {
+	return c != nil && c.ModulePath != ""
+}
+
+func (c *pkcs11Client) run(ctx context.Context, args ...string) ([]byte, error) {
+	base := []string{"--module", c.ModulePath}
+	if c.TokenLabel != "" {
+		base = append(base, "--token-label", c.TokenLabel)
+	}
+	tool := c.ToolPath
+	if tool == "" {
+		tool = "pkcs11-tool"
+	}
+
+	cmd := exec.CommandContext(ctx, tool, append(base, args...)...)
+	// The PIN is passed through the environment so it does not appear in
+	// the process list.
+	cmd.Env = append(os.Environ(), "CFCERT_PKCS11_PIN="+c.PIN)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11-tool %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+func (c *pkcs11Client) loginArgs() []string {
+	return []string{"--login", "--pin", "env:CFCERT_PKCS11_PIN"}
+}
+
+// generateKey creates a P-256 key pair on the token and returns its object ID.
+func (c *pkcs11Client) generateKey(ctx context.Context, label string) (string, error) {
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(idBytes)
+
+	args := append([]string{"--keypairgen"}, c.loginArgs()...)
+	args = append(args, "--key-type", "EC:prime256v1", "--id", id, "--label", label)
+	if _, err := c.run(ctx, args...); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (c *pkcs11Client) deleteKey(ctx context.Context, id string) error {
+	for _, objType := range []string{"privkey", "pubkey"} {
+		args := append([]string{"--delete-object"}, c.loginArgs()...)
+		args = append(args, "--type", objType, "--id", id)
+		if _, err := c.run(ctx, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// signer returns a crypto.Signer backed by the token key with the given ID.
+func (c *pkcs11Client) signer(ctx context.Context, id string) (crypto.Signer, error) {
+	der, err := c.run(ctx, "--read-object", "--type", "pubkey", "--id", id)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PKCS#11 public key: %w", err)
+	}
+	return &pkcs11Signer{ctx: ctx, client: c, id: id, public: pub}, nil
+}
+
+type pkcs11Signer struct {
+	ctx    context.Context
+	client *pkcs11Client
+	id     string
+	public crypto.PublicKey
+}
+
+func (s *pkcs11Signer) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s *pkcs11Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts.HashFunc() != crypto.SHA256 {
+		return nil, fmt.Errorf("unsupported hash function for PKCS#11 signing: %v", opts.HashFunc())
+	}
+
+	dir, err := os.MkdirTemp("", "cfcert-pkcs11")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "digest")
+	out := filepath.Join(dir, "signature")
+	if err := os.WriteFile(in, digest, 0600); err != nil {
+		return nil, err
+	}
+
+	args := append([]string{"--sign"}, s.client.loginArgs()...)
+	args = append(args,
+		"--id", s.id,
+		"--mechanism", "ECDSA",
+		"--signature-format", "openssl",
+		"--input-file", in,
+		"--output-file", out,
+	)
+	if _, err := s.client.run(s.ctx, args...); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(out)
+}
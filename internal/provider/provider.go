@@ -2,18 +2,32 @@ package provider
 
 import (
 	"context"
+	"crypto/x509"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
 
 var _ provider.Provider = &CertificateProvider{}
+var _ provider.ProviderWithEphemeralResources = &CertificateProvider{}
+var _ provider.ProviderWithFunctions = &CertificateProvider{}
 
 type CertificateProvider struct {
 	version string
@@ -23,13 +37,110 @@ type CertificateProviderModel struct {
 	Region                    types.String `tfsdk:"region"`
 	CloudflareAPIToken        types.String `tfsdk:"cloudflare_api_token"`
 	CloudflareServiceAPIToken types.String `tfsdk:"cloudflare_service_api_token"`
+	CloudflareAPITokenFile    types.String `tfsdk:"cloudflare_api_token_file"`
+	CloudflareAPITokenSecret  types.String `tfsdk:"cloudflare_api_token_secret_id"`
+	AccountID                 types.String `tfsdk:"account_id"`
+	AllowedZoneIDs            types.Set    `tfsdk:"allowed_zone_ids"`
+	PKCS11ModulePath          types.String `tfsdk:"pkcs11_module_path"`
+	PKCS11TokenLabel          types.String `tfsdk:"pkcs11_token_label"`
+	PKCS11PIN                 types.String `tfsdk:"pkcs11_pin"`
+	VaultAddress              types.String `tfsdk:"vault_address"`
+	VaultToken                types.String `tfsdk:"vault_token"`
+	VaultNamespace            types.String `tfsdk:"vault_namespace"`
+	GoogleProject             types.String `tfsdk:"google_project"`
+	GoogleAccessToken         types.String `tfsdk:"google_access_token"`
+	GoogleCredentialsFile     types.String `tfsdk:"google_credentials_file"`
+	AzureTenantID             types.String `tfsdk:"azure_tenant_id"`
+	AzureClientID             types.String `tfsdk:"azure_client_id"`
+	AzureClientSecret         types.String `tfsdk:"azure_client_secret"`
+	AzureAccessToken          types.String `tfsdk:"azure_access_token"`
+	KubernetesHost            types.String `tfsdk:"kubernetes_host"`
+	KubernetesToken           types.String `tfsdk:"kubernetes_token"`
+	KubernetesClusterCA       types.String `tfsdk:"kubernetes_cluster_ca_certificate"`
+	KubernetesConfigPath      types.String `tfsdk:"kubernetes_config_path"`
+	KubernetesConfigContext   types.String `tfsdk:"kubernetes_config_context"`
+	KubernetesExec            types.Object `tfsdk:"kubernetes_exec"`
+	ExpiryWarningDays         types.Int64  `tfsdk:"expiry_warning_days"`
+	RetryMode                 types.String `tfsdk:"retry_mode"`
+	DefaultTags               types.Map    `tfsdk:"default_tags"`
+	Workspace                 types.String `tfsdk:"workspace"`
+	EventBusName              types.String `tfsdk:"event_bus_name"`
+	AuditS3URI                types.String `tfsdk:"audit_s3_uri"`
+	RefreshOriginCARoots      types.Bool   `tfsdk:"refresh_origin_ca_roots"`
+	Telemetry                 types.Bool   `tfsdk:"telemetry"`
+	TelemetryEndpoint         types.String `tfsdk:"telemetry_endpoint"`
+	Features                  types.Object `tfsdk:"features"`
+	IgnoreTags                types.Object `tfsdk:"ignore_tags"`
 }
 
+type KubernetesExecModel struct {
+	APIVersion types.String `tfsdk:"api_version"`
+	Command    types.String `tfsdk:"command"`
+	Args       types.List   `tfsdk:"args"`
+	Env        types.Map    `tfsdk:"env"`
+}
+
+// acmMaxAttempts is how many times an AWS request is sent, unless a
+// resource's retry attribute overrides it.
+const acmMaxAttempts = 10
+
 type ProviderClients struct {
-	ACMClient                 *acm.Client
+	ACMClient                 ACMAPI
+	KMSClient                 *kmsClient
+	SNSClient                 *snsClient
+	EventBridgeClient         *eventBridgeClient
+	ServiceQuotasClient       *serviceQuotasClient
+	PKCS11Client              *pkcs11Client
+	VaultClient               *vaultClient
+	SecretsManagerClient      *secretsManagerClient
+	SSMClient                 *ssmClient
+	S3Client                  *s3Client
+	GCPClient                 *gcpClient
+	AzureClient               *azureClient
+	KubernetesClient          *kubernetesClient
 	CloudflareAPIToken        string
 	CloudflareServiceAPIToken string
-	Region                    string
+	// AllowedZoneIDs, when not empty, are the only Cloudflare zones
+	// certificates may be issued for.
+	AllowedZoneIDs    []string
+	Region            string
+	ExpiryWarningDays int64
+	DefaultTags       map[string]string
+	IgnoreTags        ignoreTags
+	Workspace         string
+	// EventBusName receives certificate lifecycle events when set.
+	EventBusName string
+	// AuditLog records issuance and deletion in S3, or is nil.
+	AuditLog *auditLog
+	// Cloudflare calls the Cloudflare API, through a circuit breaker shared
+	// by every resource.
+	Cloudflare cloudflare.Client
+	// CloudflareConfig is the configuration Cloudflare was created from.
+	CloudflareConfig cloudflare.Config
+	// Issuances deduplicates issuance for the same domain and alias across
+	// resources.
+	Issuances *issuanceLocks
+	// CertificateList caches the account's certificate listing for
+	// adoption lookups.
+	CertificateList *certificateListCache
+	// OriginCARoots verifies that certificates were issued by Cloudflare's
+	// Origin CA, or is nil to check only the issuer's name.
+	OriginCARoots *x509.CertPool
+	// AWSConfig is the configuration the AWS clients were created from.
+	AWSConfig aws.Config
+	// AssumedRoles holds the clients for resources that set assume_role.
+	AssumedRoles *assumedRoleClients
+	// Features are the provider's features block settings.
+	Features providerFeatures
+}
+
+// newACMClient returns an ACM client for cfg that logs its calls and sends
+// them through a circuit breaker.
+func newACMClient(cfg aws.Config) *acm.Client {
+	return acm.NewFromConfig(cfg, func(o *acm.Options) {
+		o.HTTPClient = &breakerHTTPClient{next: &loggingHTTPClient{service: "AWS ACM", next: o.HTTPClient}, breaker: newCircuitBreaker("AWS ACM")}
+		o.APIOptions = append(o.APIOptions, addACMCallLogging)
+	})
 }
 
 func New(version string) func() provider.Provider {
@@ -63,6 +174,185 @@ func (p *CertificateProvider) Schema(ctx context.Context, req provider.SchemaReq
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"cloudflare_api_token_file": schema.StringAttribute{
+				Description: "Path of a file holding the Cloudflare API token, used instead of cloudflare_api_token. The file is read again whenever Cloudflare rejects the token with HTTP 401 or 403, so a token rotated during a long apply is picked up. Can also be set via CLOUDFLARE_API_TOKEN_FILE environment variable.",
+				Optional:    true,
+			},
+			"cloudflare_api_token_secret_id": schema.StringAttribute{
+				Description: "Name or ARN of an AWS Secrets Manager secret whose string value is the Cloudflare API token, used instead of cloudflare_api_token. The secret is read again whenever Cloudflare rejects the token with HTTP 401 or 403, so a token rotated during a long apply is picked up. Can also be set via CLOUDFLARE_API_TOKEN_SECRET_ID environment variable.",
+				Optional:    true,
+			},
+			"account_id": schema.StringAttribute{
+				Description: "Cloudflare account ID that zone lookups, and so origin certificate listing, are limited to, for API tokens that can access several accounts. Resources can override it. Can also be set via CLOUDFLARE_ACCOUNT_ID environment variable.",
+				Optional:    true,
+			},
+			"allowed_zone_ids": schema.SetAttribute{
+				Description: "IDs of the only Cloudflare zones certificates may be issued for. Before issuing, each hostname's zone is looked up and issuance is refused if it is not listed, so a token scoped too broadly cannot mint certificates for unrelated zones in a shared account. Requires cloudflare_api_token with Zone Read permission. Can also be set via CFCERT_ALLOWED_ZONE_IDS environment variable, comma separated.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"pkcs11_module_path": schema.StringAttribute{
+				Description: "Path to the PKCS#11 module used when key_backend is \"pkcs11\" (e.g. /opt/cloudhsm/lib/libcloudhsm_pkcs11.so). Can also be set via PKCS11_MODULE_PATH environment variable.",
+				Optional:    true,
+			},
+			"pkcs11_token_label": schema.StringAttribute{
+				Description: "Label of the PKCS#11 token to use. Can also be set via PKCS11_TOKEN_LABEL environment variable.",
+				Optional:    true,
+			},
+			"pkcs11_pin": schema.StringAttribute{
+				Description: "User PIN for the PKCS#11 token (for CloudHSM, \"<user>:<password>\"). Can also be set via PKCS11_PIN environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"vault_address": schema.StringAttribute{
+				Description: "Address of the Vault server used by vault_kv_path. Can also be set via VAULT_ADDR environment variable.",
+				Optional:    true,
+			},
+			"vault_token": schema.StringAttribute{
+				Description: "Vault token used by vault_kv_path. Can also be set via VAULT_TOKEN environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"vault_namespace": schema.StringAttribute{
+				Description: "Vault Enterprise namespace used by vault_kv_path. Can also be set via VAULT_NAMESPACE environment variable.",
+				Optional:    true,
+			},
+			"google_project": schema.StringAttribute{
+				Description: "Default Google Cloud project for cfcert_gcp_certificate. Can also be set via GOOGLE_PROJECT environment variable.",
+				Optional:    true,
+			},
+			"google_access_token": schema.StringAttribute{
+				Description: "Google OAuth2 access token. Can also be set via GOOGLE_OAUTH_ACCESS_TOKEN environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"google_credentials_file": schema.StringAttribute{
+				Description: "Path to a Google service account key file. Can also be set via GOOGLE_APPLICATION_CREDENTIALS environment variable. " +
+					"When neither this nor an access token is set, the GCE metadata server is used.",
+				Optional: true,
+			},
+			"azure_tenant_id": schema.StringAttribute{
+				Description: "Azure AD tenant ID for service principal authentication. Can also be set via AZURE_TENANT_ID environment variable.",
+				Optional:    true,
+			},
+			"azure_client_id": schema.StringAttribute{
+				Description: "Azure service principal or user-assigned managed identity client ID. Can also be set via AZURE_CLIENT_ID environment variable.",
+				Optional:    true,
+			},
+			"azure_client_secret": schema.StringAttribute{
+				Description: "Azure service principal client secret. Can also be set via AZURE_CLIENT_SECRET environment variable. " +
+					"When neither this nor an access token is set, managed identity is used.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"azure_access_token": schema.StringAttribute{
+				Description: "Azure access token for the Key Vault resource. Can also be set via AZURE_ACCESS_TOKEN environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"kubernetes_host": schema.StringAttribute{
+				Description: "Kubernetes API server URL. Can also be set via KUBE_HOST environment variable.",
+				Optional:    true,
+			},
+			"kubernetes_token": schema.StringAttribute{
+				Description: "Kubernetes bearer token. Can also be set via KUBE_TOKEN environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"kubernetes_cluster_ca_certificate": schema.StringAttribute{
+				Description: "PEM-encoded Kubernetes cluster CA certificate. Can also be set via KUBE_CLUSTER_CA_CERT_DATA environment variable.",
+				Optional:    true,
+			},
+			"kubernetes_config_path": schema.StringAttribute{
+				Description: "Path to a kubeconfig file, read with kubectl, which must be on PATH. Can also be set via KUBE_CONFIG_PATH environment variable.",
+				Optional:    true,
+			},
+			"kubernetes_config_context": schema.StringAttribute{
+				Description: "Context to use from the kubeconfig file. Can also be set via KUBE_CTX environment variable.",
+				Optional:    true,
+			},
+			"kubernetes_exec": schema.SingleNestedAttribute{
+				Description: "Exec credential plugin used to obtain a Kubernetes token, e.g. aws eks get-token.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"api_version": schema.StringAttribute{
+						Description: "ExecCredential API version. Defaults to client.authentication.k8s.io/v1beta1.",
+						Optional:    true,
+					},
+					"command": schema.StringAttribute{
+						Description: "Command to run.",
+						Required:    true,
+					},
+					"args": schema.ListAttribute{
+						Description: "Command arguments.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+					"env": schema.MapAttribute{
+						Description: "Additional environment variables for the command.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
+			"default_tags": schema.MapAttribute{
+				Description: "Tags applied to every ACM certificate the provider imports. Resource tags win on conflicting keys.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"workspace": schema.StringAttribute{
+				Description: "Recorded in the cfcert:workspace tag of every ACM certificate the provider imports, to tell " +
+					"workspaces sharing an account apart. Can also be set via TF_WORKSPACE environment variable.",
+				Optional: true,
+			},
+			"event_bus_name": schema.StringAttribute{
+				Description: "Name or ARN of an EventBridge event bus, in the provider's region, that receives an event with source " +
+					"\"cfcert\" whenever a cfcert_origin_certificate is issued, adopted, renewed or deleted. Can also be set via " +
+					"CFCERT_EVENT_BUS_NAME environment variable.",
+				Optional: true,
+			},
+			"audit_s3_uri": schema.StringAttribute{
+				Description: "S3 location, as \"s3://<bucket>/<prefix>\", to which a JSON audit record is written whenever a " +
+					"cfcert_origin_certificate is issued, renewed or deleted. Each record is a new object that is never " +
+					"overwritten. Can also be set via CFCERT_AUDIT_S3_URI environment variable.",
+				Optional: true,
+			},
+			"refresh_origin_ca_roots": schema.BoolAttribute{
+				Description: "Fetch Cloudflare's Origin CA root certificates when the provider is configured, instead of " +
+					"using the copy built into the provider, to verify issued and adopted certificates against. The built-in " +
+					"copy is used, with a warning, if they cannot be fetched. Defaults to false.",
+				Optional: true,
+			},
+			"telemetry": schema.BoolAttribute{
+				Description: "Send an anonymous usage report to telemetry_endpoint when the provider exits: the provider version, " +
+					"counts of API operations and of error classes, and never hostnames, ARNs, account IDs or key material. " +
+					"Defaults to false. Can also be enabled with CFCERT_TELEMETRY=true; CFCERT_TELEMETRY=false or DO_NOT_TRACK=1 " +
+					"disable it regardless of this setting.",
+				Optional: true,
+			},
+			"telemetry_endpoint": schema.StringAttribute{
+				Description: "The HTTPS URL usage reports are POSTed to. Required when telemetry is enabled. Can also be set via " +
+					"CFCERT_TELEMETRY_ENDPOINT environment variable.",
+				Optional: true,
+			},
+			"expiry_warning_days": schema.Int64Attribute{
+				Description: "Warn during refresh when a managed certificate expires within this many days. Set to 0 to disable. " +
+					"Defaults to 30. Can also be set via CFCERT_EXPIRY_WARNING_DAYS environment variable.",
+				Optional: true,
+			},
+			"retry_mode": schema.StringAttribute{
+				Description: "How AWS requests, including ACM imports, are retried: \"adaptive\" adds client-side rate limiting on " +
+					"top of \"standard\" exponential backoff once ACM starts throttling, which keeps bursts of hundreds of imports " +
+					"from failing. Defaults to \"adaptive\". Can also be set via CFCERT_RETRY_MODE environment variable.",
+				Optional: true,
+				Validators: []validator.String{
+					stringOneOf(retryModeStandard, retryModeAdaptive),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"features":    featuresBlock(),
+			"ignore_tags": ignoreTagsBlock(),
 		},
 	}
 }
@@ -89,6 +379,251 @@ func (p *CertificateProvider) Configure(ctx context.Context, req provider.Config
 		cloudflareServiceToken = data.CloudflareServiceAPIToken.ValueString()
 	}
 
+	cloudflareTokenFile := os.Getenv("CLOUDFLARE_API_TOKEN_FILE")
+	if !data.CloudflareAPITokenFile.IsNull() && data.CloudflareAPITokenFile.ValueString() != "" {
+		cloudflareTokenFile = data.CloudflareAPITokenFile.ValueString()
+	}
+
+	cloudflareTokenSecretID := os.Getenv("CLOUDFLARE_API_TOKEN_SECRET_ID")
+	if !data.CloudflareAPITokenSecret.IsNull() && data.CloudflareAPITokenSecret.ValueString() != "" {
+		cloudflareTokenSecretID = data.CloudflareAPITokenSecret.ValueString()
+	}
+	rotatableToken := cloudflareTokenFile != "" || cloudflareTokenSecretID != ""
+
+	accountID := os.Getenv("CLOUDFLARE_ACCOUNT_ID")
+	if !data.AccountID.IsNull() && data.AccountID.ValueString() != "" {
+		accountID = data.AccountID.ValueString()
+	}
+
+	allowedZoneIDs := splitEnvList("CFCERT_ALLOWED_ZONE_IDS")
+	if !data.AllowedZoneIDs.IsNull() {
+		allowedZoneIDs = nil
+		resp.Diagnostics.Append(data.AllowedZoneIDs.ElementsAs(ctx, &allowedZoneIDs, false)...)
+		if len(allowedZoneIDs) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("allowed_zone_ids"),
+				"Empty Zone Allowlist",
+				"allowed_zone_ids must list at least one Cloudflare zone ID; remove it to allow every zone.",
+			)
+		}
+	}
+
+	pkcs11 := &pkcs11Client{
+		ModulePath: os.Getenv("PKCS11_MODULE_PATH"),
+		TokenLabel: os.Getenv("PKCS11_TOKEN_LABEL"),
+		PIN:        os.Getenv("PKCS11_PIN"),
+	}
+	if !data.PKCS11ModulePath.IsNull() && data.PKCS11ModulePath.ValueString() != "" {
+		pkcs11.ModulePath = data.PKCS11ModulePath.ValueString()
+	}
+	if !data.PKCS11TokenLabel.IsNull() && data.PKCS11TokenLabel.ValueString() != "" {
+		pkcs11.TokenLabel = data.PKCS11TokenLabel.ValueString()
+	}
+	if !data.PKCS11PIN.IsNull() && data.PKCS11PIN.ValueString() != "" {
+		pkcs11.PIN = data.PKCS11PIN.ValueString()
+	}
+
+	vault := &vaultClient{
+		Address:   os.Getenv("VAULT_ADDR"),
+		Token:     os.Getenv("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+	}
+	if !data.VaultAddress.IsNull() && data.VaultAddress.ValueString() != "" {
+		vault.Address = data.VaultAddress.ValueString()
+	}
+	if !data.VaultToken.IsNull() && data.VaultToken.ValueString() != "" {
+		vault.Token = data.VaultToken.ValueString()
+	}
+	if !data.VaultNamespace.IsNull() && data.VaultNamespace.ValueString() != "" {
+		vault.Namespace = data.VaultNamespace.ValueString()
+	}
+
+	gcp := &gcpClient{
+		Project:         os.Getenv("GOOGLE_PROJECT"),
+		AccessToken:     os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+		CredentialsFile: os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"),
+	}
+	if !data.GoogleProject.IsNull() && data.GoogleProject.ValueString() != "" {
+		gcp.Project = data.GoogleProject.ValueString()
+	}
+	if !data.GoogleAccessToken.IsNull() && data.GoogleAccessToken.ValueString() != "" {
+		gcp.AccessToken = data.GoogleAccessToken.ValueString()
+	}
+	if !data.GoogleCredentialsFile.IsNull() && data.GoogleCredentialsFile.ValueString() != "" {
+		gcp.CredentialsFile = data.GoogleCredentialsFile.ValueString()
+	}
+
+	azure := &azureClient{
+		TenantID:     os.Getenv("AZURE_TENANT_ID"),
+		ClientID:     os.Getenv("AZURE_CLIENT_ID"),
+		ClientSecret: os.Getenv("AZURE_CLIENT_SECRET"),
+		AccessToken:  os.Getenv("AZURE_ACCESS_TOKEN"),
+	}
+	if !data.AzureTenantID.IsNull() && data.AzureTenantID.ValueString() != "" {
+		azure.TenantID = data.AzureTenantID.ValueString()
+	}
+	if !data.AzureClientID.IsNull() && data.AzureClientID.ValueString() != "" {
+		azure.ClientID = data.AzureClientID.ValueString()
+	}
+	if !data.AzureClientSecret.IsNull() && data.AzureClientSecret.ValueString() != "" {
+		azure.ClientSecret = data.AzureClientSecret.ValueString()
+	}
+	if !data.AzureAccessToken.IsNull() && data.AzureAccessToken.ValueString() != "" {
+		azure.AccessToken = data.AzureAccessToken.ValueString()
+	}
+
+	kubernetes := &kubernetesClient{
+		Host:                 os.Getenv("KUBE_HOST"),
+		Token:                os.Getenv("KUBE_TOKEN"),
+		ClusterCACertificate: os.Getenv("KUBE_CLUSTER_CA_CERT_DATA"),
+		ConfigPath:           os.Getenv("KUBE_CONFIG_PATH"),
+		ConfigContext:        os.Getenv("KUBE_CTX"),
+	}
+	if !data.KubernetesHost.IsNull() && data.KubernetesHost.ValueString() != "" {
+		kubernetes.Host = data.KubernetesHost.ValueString()
+	}
+	if !data.KubernetesToken.IsNull() && data.KubernetesToken.ValueString() != "" {
+		kubernetes.Token = data.KubernetesToken.ValueString()
+	}
+	if !data.KubernetesClusterCA.IsNull() && data.KubernetesClusterCA.ValueString() != "" {
+		kubernetes.ClusterCACertificate = data.KubernetesClusterCA.ValueString()
+	}
+	if !data.KubernetesConfigPath.IsNull() && data.KubernetesConfigPath.ValueString() != "" {
+		kubernetes.ConfigPath = data.KubernetesConfigPath.ValueString()
+	}
+	if !data.KubernetesConfigContext.IsNull() && data.KubernetesConfigContext.ValueString() != "" {
+		kubernetes.ConfigContext = data.KubernetesConfigContext.ValueString()
+	}
+	if !data.KubernetesExec.IsNull() && !data.KubernetesExec.IsUnknown() {
+		var execData KubernetesExecModel
+		resp.Diagnostics.Append(data.KubernetesExec.As(ctx, &execData, basetypes.ObjectAsOptions{})...)
+		kubernetes.Exec = &kubernetesExec{
+			APIVersion: execData.APIVersion.ValueString(),
+			Command:    execData.Command.ValueString(),
+			Env:        map[string]string{},
+		}
+		resp.Diagnostics.Append(execData.Args.ElementsAs(ctx, &kubernetes.Exec.Args, false)...)
+		resp.Diagnostics.Append(execData.Env.ElementsAs(ctx, &kubernetes.Exec.Env, false)...)
+	}
+
+	expiryWarningDays := int64(30)
+	if v := os.Getenv("CFCERT_EXPIRY_WARNING_DAYS"); v != "" {
+		days, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid CFCERT_EXPIRY_WARNING_DAYS",
+				"CFCERT_EXPIRY_WARNING_DAYS must be a whole number of days: "+err.Error(),
+			)
+		}
+		expiryWarningDays = days
+	}
+	if !data.ExpiryWarningDays.IsNull() {
+		expiryWarningDays = data.ExpiryWarningDays.ValueInt64()
+	}
+
+	retryMode := retryModeAdaptive
+	if v := os.Getenv("CFCERT_RETRY_MODE"); v != "" {
+		if v != retryModeStandard && v != retryModeAdaptive {
+			resp.Diagnostics.AddError(
+				"Invalid CFCERT_RETRY_MODE",
+				fmt.Sprintf("CFCERT_RETRY_MODE must be %q or %q, got %q.", retryModeStandard, retryModeAdaptive, v),
+			)
+		}
+		retryMode = v
+	}
+	if !data.RetryMode.IsNull() {
+		retryMode = data.RetryMode.ValueString()
+	}
+
+	features, diags := parseFeatures(ctx, data.Features)
+	resp.Diagnostics.Append(diags...)
+
+	defaultTags := map[string]string{}
+	resp.Diagnostics.Append(data.DefaultTags.ElementsAs(ctx, &defaultTags, false)...)
+	for key := range defaultTags {
+		if isOwnershipTag(key) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("default_tags"),
+				"Reserved Tag Key",
+				fmt.Sprintf("Tag keys starting with %q are managed by the provider.", ownershipTagPrefix),
+			)
+		}
+	}
+
+	ignore, diags := parseIgnoreTags(ctx, data.IgnoreTags)
+	resp.Diagnostics.Append(diags...)
+
+	workspace := os.Getenv("TF_WORKSPACE")
+	if !data.Workspace.IsNull() && data.Workspace.ValueString() != "" {
+		workspace = data.Workspace.ValueString()
+	}
+
+	eventBusName := os.Getenv("CFCERT_EVENT_BUS_NAME")
+	if !data.EventBusName.IsNull() && data.EventBusName.ValueString() != "" {
+		eventBusName = data.EventBusName.ValueString()
+	}
+
+	cloudflareConfig := cloudflare.Config{
+		// Only overridden to point tests at a mock API.
+		BaseURL:    os.Getenv("CFCERT_CLOUDFLARE_API_URL"),
+		APIToken:   cloudflareToken,
+		ServiceKey: cloudflareServiceToken,
+		AccountID:  accountID,
+		HTTPClient: &breakerHTTPClient{next: newLoggingHTTPClient("Cloudflare", nil), breaker: newCircuitBreaker("Cloudflare Origin CA API")},
+		LogCall: func(ctx context.Context, operation string, start time.Time, attempts int, err error, fields map[string]interface{}) {
+			logAPICall(ctx, logSubsystemCloudflare, operation, start, attempts, err, fields)
+		},
+	}
+
+	originCARoots, err := loadOriginCARoots(ctx, data.RefreshOriginCARoots.ValueBool(), newLoggingHTTPClient("Cloudflare", nil))
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Unable to Refresh Origin CA Roots",
+			"Verifying certificates against the Origin CA roots built into the provider instead: "+err.Error(),
+		)
+	}
+	if originCARoots == nil {
+		resp.Diagnostics.AddWarning(
+			"No Origin CA Roots Available",
+			"This build of the provider has no Origin CA roots built in, so certificates are only checked to name a "+
+				"Cloudflare Origin CA as their issuer, not to chain to one. Set refresh_origin_ca_roots to fetch the roots "+
+				"from Cloudflare.",
+		)
+	}
+
+	auditS3URI := os.Getenv("CFCERT_AUDIT_S3_URI")
+	if !data.AuditS3URI.IsNull() && data.AuditS3URI.ValueString() != "" {
+		auditS3URI = data.AuditS3URI.ValueString()
+	}
+	var auditBucket, auditPrefix string
+	if auditS3URI != "" {
+		var err error
+		auditBucket, auditPrefix, err = parseS3URI(auditS3URI)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("audit_s3_uri"), "Invalid Audit Location", err.Error())
+		}
+	}
+
+	telemetryEnabled := os.Getenv("CFCERT_TELEMETRY") == "true" || data.Telemetry.ValueBool()
+	if os.Getenv("CFCERT_TELEMETRY") == "false" || os.Getenv("DO_NOT_TRACK") == "1" {
+		telemetryEnabled = false
+	}
+	telemetryEndpoint := os.Getenv("CFCERT_TELEMETRY_ENDPOINT")
+	if !data.TelemetryEndpoint.IsNull() && data.TelemetryEndpoint.ValueString() != "" {
+		telemetryEndpoint = data.TelemetryEndpoint.ValueString()
+	}
+	if telemetryEnabled {
+		if !strings.HasPrefix(telemetryEndpoint, "https://") {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("telemetry_endpoint"),
+				"Missing Telemetry Endpoint",
+				"telemetry requires an https:// URL in the telemetry_endpoint attribute or CFCERT_TELEMETRY_ENDPOINT environment variable.",
+			)
+		} else {
+			telemetry.enable(telemetryEndpoint, p.version)
+		}
+	}
+
 	if region == "" {
 		resp.Diagnostics.AddError(
 			"Missing AWS Region",
@@ -96,10 +631,26 @@ func (p *CertificateProvider) Configure(ctx context.Context, req provider.Config
 		)
 	}
 
-	if cloudflareToken == "" && cloudflareServiceToken == "" {
+	if cloudflareToken == "" && cloudflareServiceToken == "" && !rotatableToken {
 		resp.Diagnostics.AddError(
 			"Missing Cloudflare API or Service Token",
-			"Cloudflare API or Service token must be set via the cloudflare_api_token or cloudflare_service_api_token attributes or CLOUDFLARE_API_TOKEN or CLOUDFLARE_SERVICE_API_TOKEN environment variables.",
+			"Cloudflare API or Service token must be set via the cloudflare_api_token, cloudflare_api_token_file, cloudflare_api_token_secret_id or cloudflare_service_api_token attributes or CLOUDFLARE_API_TOKEN, CLOUDFLARE_API_TOKEN_FILE, CLOUDFLARE_API_TOKEN_SECRET_ID or CLOUDFLARE_SERVICE_API_TOKEN environment variables.",
+		)
+	}
+
+	if cloudflareTokenFile != "" && cloudflareTokenSecretID != "" {
+		resp.Diagnostics.AddError(
+			"Conflicting Cloudflare API Token Sources",
+			"Only one of cloudflare_api_token_file (CLOUDFLARE_API_TOKEN_FILE) and cloudflare_api_token_secret_id (CLOUDFLARE_API_TOKEN_SECRET_ID) can be set.",
+		)
+	}
+
+	if len(allowedZoneIDs) > 0 && cloudflareToken == "" && !rotatableToken {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("allowed_zone_ids"),
+			"Cannot Verify Allowed Zones",
+			"allowed_zone_ids requires cloudflare_api_token, with Zone Read permission; the Origin CA service key cannot look up "+
+				"the zone of a hostname.",
 		)
 	}
 
@@ -107,7 +658,7 @@ func (p *CertificateProvider) Configure(ctx context.Context, req provider.Config
 		return
 	}
 
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region), config.WithRetryer(newAWSRetryer(retryMode)))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create AWS Config",
@@ -116,25 +667,100 @@ func (p *CertificateProvider) Configure(ctx context.Context, req provider.Config
 		return
 	}
 
+	secretsManager := &secretsManagerClient{api: newAWSJSONClient(cfg, "secretsmanager", "secretsmanager")}
+	if rotatableToken {
+		refreshToken := cloudflareTokenSource(cloudflareTokenFile, cloudflareTokenSecretID, secretsManager)
+		cloudflareToken, err = refreshToken(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Read Cloudflare API Token", err.Error())
+			return
+		}
+		cloudflareConfig.APIToken = cloudflareToken
+		cloudflareConfig.RefreshAPIToken = refreshToken
+	}
+
 	clients := &ProviderClients{
-		ACMClient:                 acm.NewFromConfig(cfg),
+		ACMClient:                 newACMClient(cfg),
+		AWSConfig:                 cfg,
+		AssumedRoles:              newAssumedRoleClients(),
+		KMSClient:                 &kmsClient{api: newAWSJSONClient(cfg, "kms", "TrentService")},
+		Issuances:                 newIssuanceLocks(),
+		CertificateList:           newCertificateListCache(),
+		SNSClient:                 &snsClient{api: newAWSQueryClient(cfg, "sns", "2010-03-31")},
+		EventBridgeClient:         &eventBridgeClient{api: newAWSJSONClient(cfg, "events", "AWSEvents")},
+		ServiceQuotasClient:       &serviceQuotasClient{api: newAWSJSONClient(cfg, "servicequotas", "ServiceQuotasV20190624")},
+		PKCS11Client:              pkcs11,
+		VaultClient:               vault,
+		SecretsManagerClient:      secretsManager,
+		SSMClient:                 &ssmClient{api: newAWSJSONClient(cfg, "ssm", "AmazonSSM")},
+		S3Client:                  newS3Client(cfg),
+		GCPClient:                 gcp,
+		AzureClient:               azure,
+		KubernetesClient:          kubernetes,
 		CloudflareAPIToken:        cloudflareToken,
 		CloudflareServiceAPIToken: cloudflareServiceToken,
+		AllowedZoneIDs:            allowedZoneIDs,
 		Region:                    region,
+		ExpiryWarningDays:         expiryWarningDays,
+		DefaultTags:               defaultTags,
+		IgnoreTags:                ignore,
+		Workspace:                 workspace,
+		EventBusName:              eventBusName,
+		Cloudflare:                cloudflare.New(cloudflareConfig),
+		CloudflareConfig:          cloudflareConfig,
+		OriginCARoots:             originCARoots,
+		Features:                  features,
+	}
+
+	if auditBucket != "" {
+		clients.AuditLog = &auditLog{
+			s3:     newS3Client(cfg),
+			sts:    newAWSQueryClient(cfg, "sts", "2011-06-15"),
+			region: region,
+			bucket: auditBucket,
+			prefix: auditPrefix,
+		}
 	}
 
 	resp.DataSourceData = clients
 	resp.ResourceData = clients
+	resp.EphemeralResourceData = clients
 }
 
 func (p *CertificateProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewCertificateResource,
+		NewGCPCertificateResource,
+		NewAzureKeyVaultCertificateResource,
+		NewKubernetesTLSSecretResource,
+		NewCertificateCheckResource,
+		NewRotationPolicyResource,
+		NewACMCertificateTagsResource,
 	}
 }
 
 func (p *CertificateProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewCertificateDataSource,
+		NewCoverageReportDataSource,
+		NewOriginCertificateListDataSource,
+		NewIssuancePreflightDataSource,
+		NewACMUnusedCertificatesDataSource,
+	}
+}
+
+func (p *CertificateProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewCertificateEphemeralResource,
+		NewCertificateBundleEphemeralResource,
+	}
+}
+
+func (p *CertificateProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewExpiresAtFunction,
+		NewFingerprintFunction,
+		NewCoversFunction,
+		NewValidValidityFunction,
 	}
 }
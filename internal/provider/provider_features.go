@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// FeaturesModel is the provider's features block, which gates behavior
+// changes. Each group is its own nested block so new toggles have an obvious
+// home and configurations that do not mention them keep today's behavior.
+type FeaturesModel struct {
+	Adoption types.Object `tfsdk:"adoption"`
+	Delete   types.Object `tfsdk:"delete"`
+}
+
+type AdoptionFeaturesModel struct {
+	Enabled types.Bool `tfsdk:"enabled"`
+}
+
+type DeleteFeaturesModel struct {
+	RevokeCloudflare types.Bool `tfsdk:"revoke_cloudflare"`
+}
+
+// providerFeatures are the settings of the features block, with defaults
+// applied.
+type providerFeatures struct {
+	// Adoption lets cfcert_origin_certificate adopt a matching certificate
+	// already in ACM instead of issuing a new one.
+	Adoption bool
+	// RevokeOnDelete revokes a certificate's Cloudflare origin certificates
+	// once it has been destroyed.
+	RevokeOnDelete bool
+}
+
+// defaultFeatures is the behavior without a features block.
+var defaultFeatures = providerFeatures{Adoption: true}
+
+func featuresBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Opt in or out of provider behaviors. Leaving a setting out keeps the current default.",
+		Blocks: map[string]schema.Block{
+			"adoption": schema.SingleNestedBlock{
+				Description: "Adoption of certificates already in ACM by cfcert_origin_certificate.",
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{
+						Description: "Adopt an existing certificate imported by this provider for the same domain and alias, " +
+							"instead of issuing a new one. When false, every resource issues its own certificate. Defaults to true.",
+						Optional: true,
+					},
+				},
+			},
+			"delete": schema.SingleNestedBlock{
+				Description: "What destroying a cfcert_origin_certificate does beyond removing it from ACM or its key backend.",
+				Attributes: map[string]schema.Attribute{
+					"revoke_cloudflare": schema.BoolAttribute{
+						Description: "Revoke the certificate's Cloudflare origin certificates once it has been destroyed, so " +
+							"a copy of the private key left elsewhere can no longer be used. Defaults to false.",
+						Optional: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// parseFeatures applies the features block over defaultFeatures.
+func parseFeatures(ctx context.Context, block types.Object) (providerFeatures, diag.Diagnostics) {
+	features := defaultFeatures
+	var diags diag.Diagnostics
+	if block.IsNull() || block.IsUnknown() {
+		return features, diags
+	}
+	var model FeaturesModel
+	diags.Append(block.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+
+	if !model.Adoption.IsNull() && !model.Adoption.IsUnknown() {
+		var adoption AdoptionFeaturesModel
+		diags.Append(model.Adoption.As(ctx, &adoption, basetypes.ObjectAsOptions{})...)
+		if !adoption.Enabled.IsNull() {
+			features.Adoption = adoption.Enabled.ValueBool()
+		}
+	}
+	if !model.Delete.IsNull() && !model.Delete.IsUnknown() {
+		var del DeleteFeaturesModel
+		diags.Append(model.Delete.As(ctx, &del, basetypes.ObjectAsOptions{})...)
+		if !del.RevokeCloudflare.IsNull() {
+			features.RevokeOnDelete = del.RevokeCloudflare.ValueBool()
+		}
+	}
+	return features, diags
+}
+
+// revokeOnDelete revokes the Cloudflare origin certificates recorded for a
+// destroyed certificate when the provider's features block asks for it.
+// Failures are warnings: the certificate is already gone from AWS, and the
+// IDs are named so they can be revoked by hand.
+func (r *CertificateResource) revokeOnDelete(ctx context.Context, private privateState, diags *diag.Diagnostics) {
+	if !r.clients.Features.RevokeOnDelete {
+		return
+	}
+	meta, d := getIssuanceMetadata(ctx, private)
+	diags.Append(d...)
+	if meta == nil {
+		diags.AddWarning(
+			"Cloudflare Certificate Not Revoked",
+			"features.delete.revoke_cloudflare is set, but the resource has no record of its Cloudflare certificate IDs, "+
+				"which are only kept for certificates issued by this version of the provider.",
+		)
+		return
+	}
+	for _, id := range meta.CloudflareCertificateIDs {
+		err := r.clients.Cloudflare.Revoke(ctx, id)
+		var apiErr *cloudflare.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			diags.AddWarning(
+				"Cloudflare Certificate Not Revoked",
+				fmt.Sprintf("Failed to revoke Cloudflare certificate %s: %s", id, withOriginCAHint(err)),
+			)
+		}
+	}
+}
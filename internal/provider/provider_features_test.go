@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// memoryPrivateState is a privateState held in memory.
+type memoryPrivateState map[string][]byte
+
+func (m memoryPrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return m[key], nil
+}
+
+func (m memoryPrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	m[key] = value
+	return nil
+}
+
+func TestRevokeOnDelete(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	clients := newTestClients(mock)
+	ctx := context.Background()
+	cert, _, err := clients.issueWithLocalKey(ctx, []string{"example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	private := memoryPrivateState{}
+	if diags := setIssuanceMetadata(ctx, private, newIssuanceMetadata(cert.Certificate, []string{cert.ID, "missing"})); diags.HasError() {
+		t.Fatal(diags)
+	}
+	r := &CertificateResource{clients: clients}
+
+	var diags diag.Diagnostics
+	r.revokeOnDelete(ctx, private, &diags)
+	if got, _ := clients.Cloudflare.Get(ctx, cert.ID); got.RevokedAt != "" {
+		t.Error("revoked without features.delete.revoke_cloudflare")
+	}
+
+	clients.Features.RevokeOnDelete = true
+	r.revokeOnDelete(ctx, private, &diags)
+	if diags.WarningsCount() > 0 {
+		t.Errorf("unexpected warnings, a certificate Cloudflare does not know is skipped: %v", diags)
+	}
+	if got, _ := clients.Cloudflare.Get(ctx, cert.ID); got.RevokedAt == "" {
+		t.Error("certificate not revoked on delete")
+	}
+}
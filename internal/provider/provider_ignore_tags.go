@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// IgnoreTagsModel is the provider's ignore_tags block.
+type IgnoreTagsModel struct {
+	Keys        types.Set `tfsdk:"keys"`
+	KeyPrefixes types.Set `tfsdk:"key_prefixes"`
+}
+
+// ignoreTags are the ACM tags the provider leaves to other tooling, such as
+// cost allocation or backup schedulers: they are not read into tags or
+// tags_all, are not reported as drift, and are never removed.
+type ignoreTags struct {
+	Keys        []string
+	KeyPrefixes []string
+}
+
+func (i ignoreTags) ignored(key string) bool {
+	for _, k := range i.Keys {
+		if key == k {
+			return true
+		}
+	}
+	for _, prefix := range i.KeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func ignoreTagsBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "ACM tags set by other tooling that cfcert_origin_certificate should neither read nor report as drift. " +
+			"Keys listed here should not also be set in tags or default_tags.",
+		Attributes: map[string]schema.Attribute{
+			"keys": schema.SetAttribute{
+				Description: "Tag keys to ignore. Can also be set via CFCERT_IGNORE_TAGS_KEYS environment variable, comma separated.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"key_prefixes": schema.SetAttribute{
+				Description: "Tag key prefixes to ignore. Can also be set via CFCERT_IGNORE_TAGS_KEY_PREFIXES environment " +
+					"variable, comma separated.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// parseIgnoreTags reads the ignore_tags block, falling back to the
+// environment for each attribute that is not set.
+func parseIgnoreTags(ctx context.Context, block types.Object) (ignoreTags, diag.Diagnostics) {
+	ignore := ignoreTags{
+		Keys:        splitEnvList("CFCERT_IGNORE_TAGS_KEYS"),
+		KeyPrefixes: splitEnvList("CFCERT_IGNORE_TAGS_KEY_PREFIXES"),
+	}
+	var diags diag.Diagnostics
+	if block.IsNull() || block.IsUnknown() {
+		return ignore, diags
+	}
+	var model IgnoreTagsModel
+	diags.Append(block.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	if !model.Keys.IsNull() && !model.Keys.IsUnknown() {
+		ignore.Keys = nil
+		diags.Append(model.Keys.ElementsAs(ctx, &ignore.Keys, false)...)
+	}
+	if !model.KeyPrefixes.IsNull() && !model.KeyPrefixes.IsUnknown() {
+		ignore.KeyPrefixes = nil
+		diags.Append(model.KeyPrefixes.ElementsAs(ctx, &ignore.KeyPrefixes, false)...)
+	}
+	return ignore, diags
+}
+
+// splitEnvList returns the non-empty, comma separated values of the
+// environment variable name.
+func splitEnvList(name string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
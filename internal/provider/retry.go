@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// Provider retry_mode values.
+const (
+	retryModeStandard = "standard"
+	retryModeAdaptive = "adaptive"
+)
+
+// newAWSRetryer returns the retryer for AWS clients in mode. Adaptive mode
+// adds client-side rate limiting on top of the standard backoff, which keeps
+// parallel applies from tripping ACM's throttling.
+func newAWSRetryer(mode string) func() aws.Retryer {
+	standard := func(so *retry.StandardOptions) {
+		so.MaxAttempts = acmMaxAttempts
+	}
+	if mode == retryModeStandard {
+		return func() aws.Retryer {
+			return retry.NewStandard(standard)
+		}
+	}
+	return func() aws.Retryer {
+		return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+			o.StandardOptions = append(o.StandardOptions, standard)
+		})
+	}
+}
+
+// RetryModel is a certificate resource's retry attribute.
+type RetryModel struct {
+	MaxAttempts tfTypes.Int64  `tfsdk:"max_attempts"`
+	MaxBackoff  tfTypes.String `tfsdk:"max_backoff"`
+}
+
+func retryAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"max_attempts": tfTypes.Int64Type,
+		"max_backoff":  tfTypes.StringType,
+	}
+}
+
+// forRetry returns a copy of c whose Cloudflare and ACM calls use the
+// attempts and backoff cap in settings, where set, instead of the provider's
+// defaults. The circuit breakers, caches and locks are shared with c.
+func (c *ProviderClients) forRetry(settings RetryModel) *ProviderClients {
+	var maxAttempts int
+	if !settings.MaxAttempts.IsNull() {
+		maxAttempts = int(settings.MaxAttempts.ValueInt64())
+	}
+	// max_backoff is validated with the rest of the configuration.
+	maxBackoff, _ := time.ParseDuration(settings.MaxBackoff.ValueString())
+
+	clients := *c
+	cfg := c.CloudflareConfig
+	cfg.MaxAttempts = maxAttempts
+	cfg.MaxRetryDelay = maxBackoff
+	clients.Cloudflare = cloudflare.New(cfg)
+	clients.ACMClient = retryingACM{ACMAPI: c.ACMClient, maxAttempts: maxAttempts, maxBackoff: maxBackoff}
+	return &clients
+}
+
+// withRetry returns r, or a copy of r whose API calls use the settings in
+// retrySettings when it is set.
+func (r *CertificateResource) withRetry(ctx context.Context, retrySettings tfTypes.Object, diags *diag.Diagnostics) *CertificateResource {
+	if retrySettings.IsNull() || retrySettings.IsUnknown() {
+		return r
+	}
+	var settings RetryModel
+	diags.Append(retrySettings.As(ctx, &settings, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return r
+	}
+	return &CertificateResource{clients: r.clients.forRetry(settings)}
+}
+
+// retryingACM overrides the retryer's attempts and backoff cap for every
+// call it makes through ACMAPI. Zero values keep the client's own.
+type retryingACM struct {
+	ACMAPI
+	maxAttempts int
+	maxBackoff  time.Duration
+}
+
+func (a retryingACM) withRetryer(optFns []func(*acm.Options)) []func(*acm.Options) {
+	return append(optFns, func(o *acm.Options) {
+		if a.maxAttempts > 0 {
+			o.Retryer = retry.AddWithMaxAttempts(o.Retryer, a.maxAttempts)
+		}
+		if a.maxBackoff > 0 {
+			o.Retryer = retry.AddWithMaxBackoffDelay(o.Retryer, a.maxBackoff)
+		}
+	})
+}
+
+func (a retryingACM) ListCertificates(ctx context.Context, params *acm.ListCertificatesInput, optFns ...func(*acm.Options)) (*acm.ListCertificatesOutput, error) {
+	return a.ACMAPI.ListCertificates(ctx, params, a.withRetryer(optFns)...)
+}
+
+func (a retryingACM) ImportCertificate(ctx context.Context, params *acm.ImportCertificateInput, optFns ...func(*acm.Options)) (*acm.ImportCertificateOutput, error) {
+	return a.ACMAPI.ImportCertificate(ctx, params, a.withRetryer(optFns)...)
+}
+
+func (a retryingACM) DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	return a.ACMAPI.DescribeCertificate(ctx, params, a.withRetryer(optFns)...)
+}
+
+func (a retryingACM) GetCertificate(ctx context.Context, params *acm.GetCertificateInput, optFns ...func(*acm.Options)) (*acm.GetCertificateOutput, error) {
+	return a.ACMAPI.GetCertificate(ctx, params, a.withRetryer(optFns)...)
+}
+
+func (a retryingACM) DeleteCertificate(ctx context.Context, params *acm.DeleteCertificateInput, optFns ...func(*acm.Options)) (*acm.DeleteCertificateOutput, error) {
+	return a.ACMAPI.DeleteCertificate(ctx, params, a.withRetryer(optFns)...)
+}
+
+func (a retryingACM) ListTagsForCertificate(ctx context.Context, params *acm.ListTagsForCertificateInput, optFns ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error) {
+	return a.ACMAPI.ListTagsForCertificate(ctx, params, a.withRetryer(optFns)...)
+}
+
+func (a retryingACM) AddTagsToCertificate(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error) {
+	return a.ACMAPI.AddTagsToCertificate(ctx, params, a.withRetryer(optFns)...)
+}
+
+func (a retryingACM) RemoveTagsFromCertificate(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error) {
+	return a.ACMAPI.RemoveTagsFromCertificate(ctx, params, a.withRetryer(optFns)...)
+}
+
+var _ ACMAPI = retryingACM{}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// optionsACM records the options each call would be made with.
+type optionsACM struct {
+	fakeACM
+	options acm.Options
+}
+
+func (f *optionsACM) DescribeCertificate(_ context.Context, _ *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	f.options = acm.Options{Retryer: retry.NewStandard()}
+	for _, fn := range optFns {
+		fn(&f.options)
+	}
+	return &acm.DescribeCertificateOutput{}, nil
+}
+
+func TestRetryingACM(t *testing.T) {
+	fake := &optionsACM{}
+	client := retryingACM{ACMAPI: fake, maxAttempts: 3, maxBackoff: time.Second}
+	if _, err := client.DescribeCertificate(context.Background(), &acm.DescribeCertificateInput{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.options.Retryer.MaxAttempts(); got != 3 {
+		t.Errorf("MaxAttempts() = %d, want 3", got)
+	}
+
+	unset := retryingACM{ACMAPI: fake}
+	if _, err := unset.DescribeCertificate(context.Background(), &acm.DescribeCertificateInput{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fake.options.Retryer.MaxAttempts(), retry.NewStandard().MaxAttempts(); got != want {
+		t.Errorf("MaxAttempts() without an override = %d, want %d", got, want)
+	}
+}
+
+func TestNewAWSRetryer(t *testing.T) {
+	standard := newAWSRetryer(retryModeStandard)()
+	if _, ok := standard.(*retry.Standard); !ok {
+		t.Errorf("standard mode retryer is %T", standard)
+	}
+	adaptive := newAWSRetryer(retryModeAdaptive)()
+	if _, ok := adaptive.(*retry.AdaptiveMode); !ok {
+		t.Errorf("adaptive mode retryer is %T", adaptive)
+	}
+	for _, r := range []aws.Retryer{standard, adaptive} {
+		if r.MaxAttempts() != acmMaxAttempts {
+			t.Errorf("%T MaxAttempts() = %d, want %d", r, r.MaxAttempts(), acmMaxAttempts)
+		}
+	}
+}
+
+func TestACMRetrySummary(t *testing.T) {
+	statuses := []int{http.StatusServiceUnavailable, http.StatusBadRequest}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		status := statuses[0]
+		statuses = statuses[1:]
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.WriteHeader(status)
+		if status == http.StatusBadRequest {
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := acm.New(acm.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  aws.AnonymousCredentials{},
+		Retryer: retry.NewStandard(func(o *retry.StandardOptions) {
+			o.MaxAttempts = 2
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+		}),
+		APIOptions: []func(*middleware.Stack) error{addACMCallLogging},
+	})
+	_, err := client.DescribeCertificate(context.Background(), &acm.DescribeCertificateInput{CertificateArn: aws.String("arn")})
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		t.Fatalf("error %v does not wrap the final ResourceNotFoundException", err)
+	}
+	if !strings.Contains(err.Error(), "Attempts: 2\nResponses: 503, 400") {
+		t.Errorf("error %q does not summarise the attempts", err)
+	}
+}
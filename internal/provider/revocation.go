@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxCRLBytes caps how much of a CRL is downloaded.
+const maxCRLBytes = 10 << 20
+
+// revokedAt checks cert against the CRLs it names and returns when it was
+// revoked, or the zero time if no CRL lists it. OCSP is not checked; the
+// CRLs Cloudflare Origin CA publishes carry the same information. The CRL
+// signature is not verified because the issuing CA certificate is not
+// available here, so this is a defence in depth check rather than proof.
+func revokedAt(ctx context.Context, cert *x509.Certificate) (time.Time, error) {
+	if len(cert.CRLDistributionPoints) == 0 {
+		return time.Time{}, fmt.Errorf("certificate names no CRL distribution point")
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, url := range cert.CRLDistributionPoints {
+		crl, err := fetchCRL(ctx, client, url)
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, entry := range crl.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return entry.RevocationTime, nil
+			}
+		}
+	}
+	return time.Time{}, nil
+}
+
+func fetchCRL(ctx context.Context, client *http.Client, url string) (*x509.RevocationList, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CRL request for %s: %w", url, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch CRL %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch CRL %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	der, err := io.ReadAll(io.LimitReader(resp.Body, maxCRLBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CRL %s: %w", url, err)
+	}
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CRL %s: %w", url, err)
+	}
+	return crl, nil
+}
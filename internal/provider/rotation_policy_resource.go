@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+var _ resource.Resource = &RotationPolicyResource{}
+
+// RotationPolicyResource is a named, reusable set of renewal settings. It
+// holds no remote state; certificate resources consume its policy attribute.
+type RotationPolicyResource struct{}
+
+type RotationPolicyResourceModel struct {
+	Name                tfTypes.String `tfsdk:"name"`
+	RenewBeforeDays     tfTypes.Int64  `tfsdk:"renew_before_days"`
+	RenewBeforePercent  tfTypes.Int64  `tfsdk:"renew_before_percent"`
+	RotateKeyOnRenew    tfTypes.Bool   `tfsdk:"rotate_key_on_renew"`
+	NotificationTargets tfTypes.List   `tfsdk:"notification_targets"`
+	Policy              tfTypes.Object `tfsdk:"policy"`
+	ID                  tfTypes.String `tfsdk:"id"`
+}
+
+// RotationPolicyModel is the shape of the policy object passed to
+// certificate resources through their rotation_policy attribute.
+type RotationPolicyModel struct {
+	Name                tfTypes.String `tfsdk:"name"`
+	RenewBeforeDays     tfTypes.Int64  `tfsdk:"renew_before_days"`
+	RenewBeforePercent  tfTypes.Int64  `tfsdk:"renew_before_percent"`
+	RotateKeyOnRenew    tfTypes.Bool   `tfsdk:"rotate_key_on_renew"`
+	NotificationTargets tfTypes.List   `tfsdk:"notification_targets"`
+}
+
+func rotationPolicyAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"name":                 tfTypes.StringType,
+		"renew_before_days":    tfTypes.Int64Type,
+		"renew_before_percent": tfTypes.Int64Type,
+		"rotate_key_on_renew":  tfTypes.BoolType,
+		"notification_targets": tfTypes.ListType{ElemType: tfTypes.StringType},
+	}
+}
+
+// renewalDue reports whether a certificate issued at issuedAt and expiring at
+// expiresAt falls within the policy's renewal window.
+func (p RotationPolicyModel) renewalDue(issuedAt time.Time, expiresAt string) bool {
+	expires, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		return false
+	}
+	return !time.Now().Before(p.renewAfter(issuedAt, expires))
+}
+
+// renewAfter returns when a certificate issued at issuedAt and expiring at
+// expires enters the policy's renewal window: renew_before_days before
+// expiry, or once only renew_before_percent of its lifetime remains. The
+// percentage is not checked when issuedAt is zero.
+func (p RotationPolicyModel) renewAfter(issuedAt, expires time.Time) time.Time {
+	window := time.Duration(p.RenewBeforeDays.ValueInt64()) * 24 * time.Hour
+	if !p.RenewBeforePercent.IsNull() && !issuedAt.IsZero() && expires.After(issuedAt) {
+		window = max(window, expires.Sub(issuedAt)*time.Duration(p.RenewBeforePercent.ValueInt64())/100)
+	}
+	return expires.Add(-window)
+}
+
+func (p RotationPolicyModel) notificationTargets(ctx context.Context) ([]string, diag.Diagnostics) {
+	var targets []string
+	diags := p.NotificationTargets.ElementsAs(ctx, &targets, false)
+	return targets, diags
+}
+
+// rotationPolicyFromObject decodes a rotation_policy object, applying the
+// same defaults as cfcert_rotation_policy for attributes left null.
+func rotationPolicyFromObject(ctx context.Context, obj tfTypes.Object) (RotationPolicyModel, diag.Diagnostics) {
+	var policy RotationPolicyModel
+	diags := obj.As(ctx, &policy, basetypes.ObjectAsOptions{})
+	if policy.RenewBeforeDays.IsNull() {
+		policy.RenewBeforeDays = tfTypes.Int64Value(30)
+	}
+	if policy.RotateKeyOnRenew.IsNull() {
+		policy.RotateKeyOnRenew = tfTypes.BoolValue(true)
+	}
+	return policy, diags
+}
+
+func NewRotationPolicyResource() resource.Resource {
+	return &RotationPolicyResource{}
+}
+
+func (r *RotationPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rotation_policy"
+}
+
+func (r *RotationPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Defines a named certificate rotation policy. Pass its policy attribute to the rotation_policy " +
+			"argument of certificate resources to centralize renewal behavior.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Description: "The policy name.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"renew_before_days": schema.Int64Attribute{
+				Description: "Renew certificates this many days before they expire. Defaults to 30.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(30),
+			},
+			"renew_before_percent": schema.Int64Attribute{
+				Description: "Also renew certificates once this percentage of their lifetime remains, so the window scales with " +
+					"the validity they were issued with. Whichever of this and renew_before_days is reached first triggers renewal.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64Between(1, 99),
+				},
+			},
+			"rotate_key_on_renew": schema.BoolAttribute{
+				Description: "Generate a new key pair when renewing. Only KMS and PKCS#11 backed certificates can keep their key; " +
+					"ACM backed certificates always get a new key. Defaults to true.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"notification_targets": schema.ListAttribute{
+				Description: "SNS topic ARNs notified when a certificate using this policy is renewed.",
+				ElementType: tfTypes.StringType,
+				Optional:    true,
+			},
+			"policy": schema.ObjectAttribute{
+				Description:    "The policy as an object, for use as a certificate resource's rotation_policy.",
+				AttributeTypes: rotationPolicyAttrTypes(),
+				Computed:       true,
+			},
+			"id": schema.StringAttribute{
+				Description: "Resource identifier (same as name).",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *RotationPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RotationPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.setComputed(&data, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RotationPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data RotationPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RotationPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data RotationPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.setComputed(&data, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RotationPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+func (r *RotationPolicyResource) setComputed(data *RotationPolicyResourceModel, diags *diag.Diagnostics) {
+	targets := data.NotificationTargets
+	if targets.IsNull() {
+		targets = tfTypes.ListValueMust(tfTypes.StringType, []attr.Value{})
+	}
+	policy, d := tfTypes.ObjectValue(rotationPolicyAttrTypes(), map[string]attr.Value{
+		"name":                 data.Name,
+		"renew_before_days":    data.RenewBeforeDays,
+		"renew_before_percent": data.RenewBeforePercent,
+		"rotate_key_on_renew":  data.RotateKeyOnRenew,
+		"notification_targets": targets,
+	})
+	diags.Append(d...)
+	data.Policy = policy
+	data.ID = data.Name
+}
+
+func renewalNotification(domainName, identifier, expiresAt string) (string, string) {
+	return fmt.Sprintf("Certificate renewed: %s", domainName),
+		fmt.Sprintf("The Cloudflare Origin Certificate for %s (%s) was renewed. New expiry: %s.", domainName, identifier, expiresAt)
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestRenewalDue(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		percent  tfTypes.Int64
+		issuedAt time.Time
+		expires  time.Time
+		want     bool
+	}{
+		{
+			name:    "outside the days window",
+			percent: tfTypes.Int64Null(),
+			expires: now.Add(60 * 24 * time.Hour),
+		},
+		{
+			name:    "inside the days window",
+			percent: tfTypes.Int64Null(),
+			expires: now.Add(10 * 24 * time.Hour),
+			want:    true,
+		},
+		{
+			name:     "a third of a year remaining",
+			percent:  tfTypes.Int64Value(33),
+			issuedAt: now.Add(-250 * 24 * time.Hour),
+			expires:  now.Add(115 * 24 * time.Hour),
+			want:     true,
+		},
+		{
+			name:     "most of a year remaining",
+			percent:  tfTypes.Int64Value(33),
+			issuedAt: now.Add(-100 * 24 * time.Hour),
+			expires:  now.Add(265 * 24 * time.Hour),
+		},
+		{
+			name:     "week long certificate reaches the days window first",
+			percent:  tfTypes.Int64Value(10),
+			issuedAt: now.Add(-24 * time.Hour),
+			expires:  now.Add(6 * 24 * time.Hour),
+			want:     true,
+		},
+		{
+			name:    "percentage from the ACM import time without certificate_pem",
+			percent: tfTypes.Int64Value(90),
+			issuedAt: certificateIssuedAt(CertificateResourceModel{
+				CertificatePEM: tfTypes.StringNull(),
+				ImportedAt:     tfTypes.StringValue(now.Add(-300 * 24 * time.Hour).Format(time.RFC3339)),
+				CreatedAt:      tfTypes.StringNull(),
+			}),
+			expires: now.Add(60 * 24 * time.Hour),
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := RotationPolicyModel{RenewBeforeDays: tfTypes.Int64Value(30), RenewBeforePercent: tt.percent}
+			if got := policy.renewalDue(tt.issuedAt, tt.expires.Format(time.RFC3339)); got != tt.want {
+				t.Errorf("renewalDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenewAfter(t *testing.T) {
+	issued := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	expires := issued.AddDate(1, 0, 0)
+	policy := RotationPolicyModel{RenewBeforeDays: tfTypes.Int64Value(30), RenewBeforePercent: tfTypes.Int64Null()}
+	if got, want := policy.renewAfter(issued, expires), expires.AddDate(0, 0, -30); !got.Equal(want) {
+		t.Errorf("renewAfter() = %v, want %v", got, want)
+	}
+	policy.RenewBeforePercent = tfTypes.Int64Value(50)
+	if got, want := policy.renewAfter(issued, expires), issued.Add(expires.Sub(issued)/2); !got.Equal(want) {
+		t.Errorf("renewAfter() with 50%% = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// s3Client reads and writes S3 objects with SigV4-signed HTTP requests, since
+// the S3 SDK module is not part of this provider's dependency set.
+type s3Client struct {
+	cfg        aws.Config
+	httpClient *http.Client
+}
+
+func newS3Client(cfg aws.Config) *s3Client {
+	return &s3Client{cfg: cfg, httpClient: newLoggingHTTPClient("AWS s3", nil)}
+}
+
+// putNewObject writes body to key in bucket, failing rather than overwriting
+// an object that already exists.
+func (c *s3Client) putNewObject(ctx context.Context, bucket, key, contentType string, body []byte) error {
+	_, err := c.do(ctx, "PUT", "PutObject", bucket, key, map[string]string{
+		"Content-Type":  contentType,
+		"If-None-Match": "*",
+	}, body)
+	return err
+}
+
+// putObject writes body to key in bucket, replacing any existing object.
+func (c *s3Client) putObject(ctx context.Context, bucket, key, contentType string, body []byte) error {
+	_, err := c.do(ctx, "PUT", "PutObject", bucket, key, map[string]string{"Content-Type": contentType}, body)
+	return err
+}
+
+func (c *s3Client) getObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	return c.do(ctx, "GET", "GetObject", bucket, key, nil, nil)
+}
+
+func (c *s3Client) deleteObject(ctx context.Context, bucket, key string) error {
+	_, err := c.do(ctx, "DELETE", "DeleteObject", bucket, key, nil, nil)
+	return err
+}
+
+// do sends a signed request for key in bucket and returns the response body.
+// operation names the request in errors.
+func (c *s3Client) do(ctx context.Context, method, operation, bucket, key string, headers map[string]string, body []byte) ([]byte, error) {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	endpoint := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, c.cfg.Region, strings.Join(segments, "/"))
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", operation, err)
+	}
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	for name, value := range headers {
+		httpReq.Header.Set(name, value)
+	}
+	httpReq.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	creds, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	err = v4.NewSigner().SignHTTP(ctx, creds, httpReq, payloadHash, "s3", c.cfg.Region, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign %s request: %w", operation, err)
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s request: %w", operation, err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", operation, err)
+	}
+	if httpResp.StatusCode >= 300 {
+		var errResp struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+		_ = xml.Unmarshal(respBody, &errResp)
+		apiErr := &awsAPIError{
+			StatusCode: httpResp.StatusCode,
+			Code:       errResp.Code,
+			Message:    errResp.Message,
+		}
+		if apiErr.Code == "" {
+			apiErr.Code = http.StatusText(httpResp.StatusCode)
+		}
+		return nil, apiErr
+	}
+	return respBody, nil
+}
+
+var _ KeyStore = &s3Client{}
+
+func (c *s3Client) Name() string {
+	return "S3"
+}
+
+func (c *s3Client) Check() error {
+	return nil
+}
+
+// Write stores data as a JSON object at uri, an s3://bucket/key URI. The
+// object is encrypted with the bucket's default encryption.
+func (c *s3Client) Write(ctx context.Context, uri string, data map[string]string) error {
+	bucket, key, err := parseS3ObjectURI(uri)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal object: %w", err)
+	}
+	return c.putObject(ctx, bucket, key, "application/json", body)
+}
+
+func (c *s3Client) Read(ctx context.Context, uri string) (map[string]string, error) {
+	bucket, key, err := parseS3ObjectURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.getObject(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]string
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("%s is not a JSON object of strings: %w", uri, err)
+	}
+	return data, nil
+}
+
+// Delete removes the object. S3 reports success whether or not it existed.
+func (c *s3Client) Delete(ctx context.Context, uri string) error {
+	bucket, key, err := parseS3ObjectURI(uri)
+	if err != nil {
+		return err
+	}
+	return c.deleteObject(ctx, bucket, key)
+}
+
+// parseS3ObjectURI splits an s3://bucket/key URI.
+func parseS3ObjectURI(uri string) (bucket, key string, err error) {
+	parsed, err := url.Parse(uri)
+	if err == nil {
+		key = strings.TrimPrefix(parsed.Path, "/")
+	}
+	if err != nil || parsed.Scheme != "s3" || parsed.Host == "" || key == "" || strings.HasSuffix(key, "/") {
+		return "", "", fmt.Errorf("%q is not an s3://bucket/key URI", uri)
+	}
+	return parsed.Host, key, nil
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// secretsManagerClient stores certificate material as JSON secrets in AWS
+// Secrets Manager.
+type secretsManagerClient struct {
+	api *awsJSONClient
+}
+
+var _ KeyStore = &secretsManagerClient{}
+
+func (c *secretsManagerClient) Name() string {
+	return "Secrets Manager"
+}
+
+func (c *secretsManagerClient) Check() error {
+	return nil
+}
+
+// Write stores data as a new version of the secret, creating the secret if it
+// does not exist yet.
+func (c *secretsManagerClient) Write(ctx context.Context, secretID string, data map[string]string) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal secret: %w", err)
+	}
+	err = c.api.call(ctx, "PutSecretValue", map[string]interface{}{
+		"SecretId":     secretID,
+		"SecretString": string(payload),
+	}, nil)
+	if !isSecretsManagerNotFoundError(err) {
+		return err
+	}
+	return c.api.call(ctx, "CreateSecret", map[string]interface{}{
+		"Name":         secretID,
+		"Description":  "Cloudflare Origin Certificate and private key managed by Terraform.",
+		"SecretString": string(payload),
+	}, nil)
+}
+
+func (c *secretsManagerClient) Read(ctx context.Context, secretID string) (map[string]string, error) {
+	secret, err := c.secretString(ctx, secretID)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]string
+	if err := json.Unmarshal([]byte(secret), &data); err != nil {
+		return nil, fmt.Errorf("secret %s is not a JSON object of strings: %w", secretID, err)
+	}
+	return data, nil
+}
+
+// secretString returns the current value of a secret stored as a string.
+func (c *secretsManagerClient) secretString(ctx context.Context, secretID string) (string, error) {
+	var out struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := c.api.call(ctx, "GetSecretValue", map[string]interface{}{"SecretId": secretID}, &out); err != nil {
+		return "", err
+	}
+	return out.SecretString, nil
+}
+
+// Delete removes the secret without a recovery window, so a replacement can
+// reuse its name straight away.
+func (c *secretsManagerClient) Delete(ctx context.Context, secretID string) error {
+	err := c.api.call(ctx, "DeleteSecret", map[string]interface{}{
+		"SecretId":                   secretID,
+		"ForceDeleteWithoutRecovery": true,
+	}, nil)
+	if isSecretsManagerNotFoundError(err) {
+		return nil
+	}
+	return err
+}
+
+func isSecretsManagerNotFoundError(err error) bool {
+	var apiErr *awsAPIError
+	return errors.As(err, &apiErr) && apiErr.Code == "ResourceNotFoundException"
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestSecretsManagerKeyStore(t *testing.T) {
+	secrets := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input struct {
+			SecretId     string
+			Name         string
+			SecretString string
+		}
+		_ = json.NewDecoder(r.Body).Decode(&input)
+		action := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "secretsmanager.")
+		_, exists := secrets[input.SecretId]
+		switch {
+		case action == "CreateSecret":
+			secrets[input.Name] = input.SecretString
+		case !exists:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"not found"}`))
+		case action == "PutSecretValue":
+			secrets[input.SecretId] = input.SecretString
+		case action == "GetSecretValue":
+			_ = json.NewEncoder(w).Encode(map[string]string{"SecretString": secrets[input.SecretId]})
+		case action == "DeleteSecret":
+			delete(secrets, input.SecretId)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	api := newAWSJSONClient(aws.Config{
+		Region: "us-east-1",
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test", SecretAccessKey: "test"}, nil
+		}),
+	}, "secretsmanager", "secretsmanager")
+	api.endpoint = server.URL
+	var store KeyStore = &secretsManagerClient{api: api}
+	ctx := context.Background()
+
+	// The first write creates the secret, later ones add versions to it.
+	for _, arn := range []string{"first", "second"} {
+		if err := store.Write(ctx, "origin/example", map[string]string{"certificate_arn": arn}); err != nil {
+			t.Fatalf("Write(%s): %v", arn, err)
+		}
+	}
+	data, err := store.Read(ctx, "origin/example")
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got := data["certificate_arn"]; got != "second" {
+		t.Errorf("certificate_arn = %q, want second", got)
+	}
+
+	if err := store.Delete(ctx, "origin/example"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(ctx, "origin/example"); err != nil {
+		t.Errorf("Delete of a missing secret: %v", err)
+	}
+}
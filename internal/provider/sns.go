@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+// snsClient publishes certificate lifecycle notifications to SNS topics.
+type snsClient struct {
+	api *awsQueryClient
+}
+
+// publish sends message to topicArn in the topic's own region.
+func (c *snsClient) publish(ctx context.Context, topicArn, subject, message string) error {
+	parsed, err := arn.Parse(topicArn)
+	if err != nil {
+		return fmt.Errorf("invalid SNS topic ARN %q: %w", topicArn, err)
+	}
+	params := url.Values{
+		"TopicArn": {topicArn},
+		"Message":  {message},
+	}
+	if subject != "" {
+		params.Set("Subject", subject)
+	}
+	return c.api.call(ctx, parsed.Region, "Publish", params, nil)
+}
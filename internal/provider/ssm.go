@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ssmClient stores certificate material as JSON SecureString parameters in
+// the AWS Systems Manager Parameter Store.
+type ssmClient struct {
+	api *awsJSONClient
+}
+
+var _ KeyStore = &ssmClient{}
+
+func (c *ssmClient) Name() string {
+	return "SSM Parameter Store"
+}
+
+func (c *ssmClient) Check() error {
+	return nil
+}
+
+// Write overwrites the parameter with data. Intelligent tiering moves the
+// parameter to the advanced tier when a bundle of several certificates
+// exceeds the standard tier's 4 KB limit.
+func (c *ssmClient) Write(ctx context.Context, name string, data map[string]string) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal parameter: %w", err)
+	}
+	return c.api.call(ctx, "PutParameter", map[string]interface{}{
+		"Name":      name,
+		"Value":     string(payload),
+		"Type":      "SecureString",
+		"Tier":      "Intelligent-Tiering",
+		"Overwrite": true,
+	}, nil)
+}
+
+func (c *ssmClient) Read(ctx context.Context, name string) (map[string]string, error) {
+	var out struct {
+		Parameter struct {
+			Value string `json:"Value"`
+		} `json:"Parameter"`
+	}
+	err := c.api.call(ctx, "GetParameter", map[string]interface{}{
+		"Name":           name,
+		"WithDecryption": true,
+	}, &out)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]string
+	if err := json.Unmarshal([]byte(out.Parameter.Value), &data); err != nil {
+		return nil, fmt.Errorf("parameter %s is not a JSON object of strings: %w", name, err)
+	}
+	return data, nil
+}
+
+func (c *ssmClient) Delete(ctx context.Context, name string) error {
+	err := c.api.call(ctx, "DeleteParameter", map[string]interface{}{"Name": name}, nil)
+	var apiErr *awsAPIError
+	if errors.As(err, &apiErr) && apiErr.Code == "ParameterNotFound" {
+		return nil
+	}
+	return err
+}
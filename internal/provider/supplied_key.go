@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// validateSuppliedKey checks a caller-supplied private key, and the CSR for
+// it when there is one, before anything is sent to Cloudflare or ACM.
+//
+// cfcert_origin_certificate does not accept private_key_pem or csr_pem yet:
+// until they can be write-only arguments, a supplied key would be stored in
+// state. Once they land, ValidateConfig runs this alongside an exactlyOneOf
+// check between the supplied key and key_backend, so a mismatched key fails
+// the plan instead of the ACM import.
+func validateSuppliedKey(keyPEM, csrPEM string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	signer, err := parseSuppliedKey(keyPEM)
+	if err != nil {
+		diags.AddAttributeError(path.Root("private_key_pem"), "Invalid Private Key", err.Error())
+		return diags
+	}
+	keyAlgorithm := publicKeyAlgorithm(signer.Public())
+	if key, ok := signer.Public().(*ecdsa.PublicKey); !ok || key.Curve != elliptic.P256() {
+		diags.AddAttributeError(
+			path.Root("private_key_pem"),
+			"Unsupported Private Key Algorithm",
+			fmt.Sprintf("private_key_pem holds a %s key. Certificates are issued for ECDSA P-256 keys only, the kind the provider "+
+				"generates, since ACM reports any other as key_algorithm drift and the certificate would be replaced.", keyAlgorithm),
+		)
+		return diags
+	}
+	if csrPEM == "" {
+		return diags
+	}
+
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		diags.AddAttributeError(path.Root("csr_pem"), "Invalid CSR", "csr_pem is not a PEM encoded CERTIFICATE REQUEST.")
+		return diags
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err == nil {
+		err = csr.CheckSignature()
+	}
+	if err != nil {
+		diags.AddAttributeError(path.Root("csr_pem"), "Invalid CSR", err.Error())
+		return diags
+	}
+	if csrAlgorithm := publicKeyAlgorithm(csr.PublicKey); csrAlgorithm != keyAlgorithm {
+		diags.AddAttributeError(
+			path.Root("csr_pem"),
+			"CSR Key Algorithm Mismatch",
+			fmt.Sprintf("csr_pem is for a %s key, but private_key_pem holds a %s key.", csrAlgorithm, keyAlgorithm),
+		)
+		return diags
+	}
+	if !signer.Public().(*ecdsa.PublicKey).Equal(csr.PublicKey) {
+		diags.AddAttributeError(
+			path.Root("csr_pem"),
+			"CSR Does Not Match Private Key",
+			"csr_pem was created for a different key from the one in private_key_pem. ACM would refuse to import the "+
+				"certificate with it.",
+		)
+	}
+	return diags
+}
+
+// parseSuppliedKey parses a SEC 1, PKCS#1 or PKCS#8 private key.
+func parseSuppliedKey(keyPEM string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("private_key_pem is not PEM encoded")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("private_key_pem holds a %q PEM block, not a private key", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// publicKeyAlgorithm describes key's algorithm for diagnostics, such as
+// "ECDSA P-256" or "RSA 2048-bit".
+func publicKeyAlgorithm(key crypto.PublicKey) string {
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		return "ECDSA " + key.Curve.Params().Name
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d-bit", key.N.BitLen())
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return fmt.Sprintf("%T", key)
+	}
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func testKeyPEM(t *testing.T, key crypto.Signer) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func TestValidateSuppliedKey(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherP256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	csrFor := func(key crypto.Signer) string {
+		csr, err := createCSR([]string{"example.com"}, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return csr
+	}
+
+	tests := []struct {
+		name   string
+		keyPEM string
+		csrPEM string
+		want   string
+	}{
+		{name: "P-256 key", keyPEM: testKeyPEM(t, p256)},
+		{name: "P-256 key and its CSR", keyPEM: testKeyPEM(t, p256), csrPEM: csrFor(p256)},
+		{name: "not a key", keyPEM: "not a key", want: "Invalid Private Key"},
+		{name: "P-384 key", keyPEM: testKeyPEM(t, p384), want: "Unsupported Private Key Algorithm"},
+		{name: "RSA key", keyPEM: testKeyPEM(t, rsaKey), want: "Unsupported Private Key Algorithm"},
+		{name: "not a CSR", keyPEM: testKeyPEM(t, p256), csrPEM: testKeyPEM(t, p256), want: "Invalid CSR"},
+		{name: "CSR for an RSA key", keyPEM: testKeyPEM(t, p256), csrPEM: csrFor(rsaKey), want: "CSR Key Algorithm Mismatch"},
+		{name: "CSR for another key", keyPEM: testKeyPEM(t, p256), csrPEM: csrFor(otherP256), want: "CSR Does Not Match Private Key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateSuppliedKey(tt.keyPEM, tt.csrPEM)
+			switch {
+			case tt.want == "" && diags.HasError():
+				t.Errorf("validateSuppliedKey() = %v, want no errors", diags)
+			case tt.want != "" && (diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != tt.want):
+				t.Errorf("validateSuppliedKey() = %v, want %q", diags, tt.want)
+			}
+		})
+	}
+}
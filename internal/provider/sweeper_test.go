@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare/cloudflaretest"
+)
+
+// sweepRegion runs the sweepers instead of the tests when set, as in
+// go test ./internal/provider -sweep=us-east-1.
+var sweepRegion = flag.String("sweep", "", "delete certificates acceptance tests left in this AWS region, then exit")
+
+// testSweeper deletes resources that failed acceptance test runs left behind.
+type testSweeper struct {
+	name  string
+	sweep func(ctx context.Context, region string) error
+}
+
+var testSweepers = []testSweeper{
+	{name: "ACM certificates", sweep: sweepACMCertificates},
+	{name: "Cloudflare origin certificates", sweep: sweepCloudflareOriginCerts},
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if *sweepRegion == "" {
+		os.Exit(m.Run())
+	}
+
+	failed := false
+	for _, sweeper := range testSweepers {
+		log.Printf("[INFO] sweeping %s", sweeper.name)
+		if err := sweeper.sweep(context.Background(), *sweepRegion); err != nil {
+			log.Printf("[ERROR] sweeping %s: %v", sweeper.name, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// isTestAccHostname reports whether hostname was certified by an acceptance
+// test.
+func isTestAccHostname(hostname string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(hostname, "*."), testAccDomainPrefix)
+}
+
+// sweepACMCertificates deletes the ACM certificates for acceptance test
+// domains in region. Certificates still in use are reported and kept.
+func sweepACMCertificates(ctx context.Context, region string) error {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	client := acm.NewFromConfig(cfg)
+
+	// ListCertificates only returns RSA_2048 certificates unless other key
+	// types are asked for.
+	paginator := acm.NewListCertificatesPaginator(client, &acm.ListCertificatesInput{
+		Includes: &types.Filters{KeyTypes: []types.KeyAlgorithm{
+			types.KeyAlgorithmEcPrime256v1, types.KeyAlgorithmEcSecp384r1, types.KeyAlgorithmRsa2048,
+		}},
+	})
+	var errs []string
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, summary := range page.CertificateSummaryList {
+			if !isTestAccHostname(aws.ToString(summary.DomainName)) {
+				continue
+			}
+			arn := aws.ToString(summary.CertificateArn)
+			log.Printf("[INFO] deleting ACM certificate %s for %s", arn, aws.ToString(summary.DomainName))
+			if _, err := client.DeleteCertificate(ctx, &acm.DeleteCertificateInput{CertificateArn: summary.CertificateArn}); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", arn, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to delete %d certificates: %s", len(errs), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// sweepCloudflareOriginCerts revokes the unrevoked origin certificates in
+// CFCERT_SWEEP_ZONE_ID that cover only acceptance test hostnames. It is
+// skipped unless the zone and Cloudflare credentials are set, as acceptance
+// tests normally run against a mock Cloudflare API.
+func sweepCloudflareOriginCerts(ctx context.Context, _ string) error {
+	zoneID := os.Getenv("CFCERT_SWEEP_ZONE_ID")
+	token, serviceKey := os.Getenv("CLOUDFLARE_API_TOKEN"), os.Getenv("CLOUDFLARE_SERVICE_API_TOKEN")
+	if zoneID == "" || token == "" && serviceKey == "" {
+		log.Printf("[INFO] skipping: set CFCERT_SWEEP_ZONE_ID and CLOUDFLARE_API_TOKEN or CLOUDFLARE_SERVICE_API_TOKEN")
+		return nil
+	}
+	client := cloudflare.New(cloudflare.Config{
+		BaseURL:    os.Getenv("CFCERT_CLOUDFLARE_API_URL"),
+		APIToken:   token,
+		ServiceKey: serviceKey,
+	})
+
+	certs, err := client.List(ctx, zoneID)
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for _, cert := range certs {
+		if cert.RevokedAt != "" || len(cert.Hostnames) == 0 {
+			continue
+		}
+		testOnly := true
+		for _, hostname := range cert.Hostnames {
+			testOnly = testOnly && isTestAccHostname(hostname)
+		}
+		if !testOnly {
+			continue
+		}
+		log.Printf("[INFO] revoking Cloudflare origin certificate %s for %s", cert.ID, strings.Join(cert.Hostnames, ", "))
+		if err := client.Revoke(ctx, cert.ID); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", cert.ID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to revoke %d certificates: %s", len(errs), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func TestSweepCloudflareOriginCerts(t *testing.T) {
+	mock := cloudflaretest.NewServer(t)
+	zoneID := mock.AddZone("example.com")
+	clients := newTestClients(mock)
+	ctx := context.Background()
+	for _, hostname := range []string{testAccDomainPrefix + "1.example.com", "www.example.com"} {
+		if _, _, err := clients.issueWithLocalKey(ctx, []string{hostname}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("CFCERT_SWEEP_ZONE_ID", zoneID)
+	t.Setenv("CFCERT_CLOUDFLARE_API_URL", mock.APIURL())
+	t.Setenv("CLOUDFLARE_API_TOKEN", "test-token")
+
+	if err := sweepCloudflareOriginCerts(ctx, ""); err != nil {
+		t.Fatalf("sweepCloudflareOriginCerts: %v", err)
+	}
+	for _, cert := range mock.Issued() {
+		if revoked := cert.RevokedAt != ""; revoked != isTestAccHostname(cert.Hostnames[0]) {
+			t.Errorf("certificate for %v revoked = %v", cert.Hostnames, revoked)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+)
+
+// telemetryFlushTimeout bounds the report sent as the provider exits. Terraform
+// waits two seconds for a plugin to stop before killing it.
+const telemetryFlushTimeout = time.Second
+
+// telemetryRecorder counts API operations and error classes for the anonymous
+// usage report of providers that opt in. Only operation names, error codes
+// and the provider version are kept: never hostnames, ARNs, IDs, accounts or
+// key material.
+type telemetryRecorder struct {
+	mu         sync.Mutex
+	endpoint   string
+	version    string
+	operations map[string]int
+	errors     map[string]int
+	httpClient *http.Client
+}
+
+// telemetry is shared by every provider instance in the process, since the
+// report is sent once the plugin server has stopped.
+var telemetry = &telemetryRecorder{}
+
+// telemetryReport is the body sent to the telemetry endpoint.
+type telemetryReport struct {
+	ProviderVersion string         `json:"provider_version"`
+	OS              string         `json:"os"`
+	Arch            string         `json:"arch"`
+	Operations      map[string]int `json:"operations"`
+	Errors          map[string]int `json:"errors"`
+}
+
+// enable starts counting operations, to be reported to endpoint.
+func (t *telemetryRecorder) enable(endpoint, version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.endpoint = endpoint
+	t.version = version
+	if t.operations == nil {
+		t.operations = map[string]int{}
+		t.errors = map[string]int{}
+	}
+}
+
+// record counts a completed API call to subsystem. It does nothing unless
+// telemetry is enabled.
+func (t *telemetryRecorder) record(subsystem, operation string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.endpoint == "" {
+		return
+	}
+	t.operations[subsystem+" "+telemetryOperation(operation)]++
+	if err != nil {
+		t.errors[subsystem+" "+errorClass(err)]++
+	}
+}
+
+// flush sends the counts collected so far, if telemetry is enabled and
+// anything was recorded, and resets them.
+func (t *telemetryRecorder) flush(ctx context.Context) error {
+	t.mu.Lock()
+	report := telemetryReport{
+		ProviderVersion: t.version,
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		Operations:      t.operations,
+		Errors:          t.errors,
+	}
+	endpoint := t.endpoint
+	t.operations = map[string]int{}
+	t.errors = map[string]int{}
+	t.mu.Unlock()
+	if endpoint == "" || len(report.Operations) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal telemetry report: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	client := t.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry report: %w", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint responded with status %d", httpResp.StatusCode)
+	}
+	return nil
+}
+
+// FlushTelemetry sends the usage report of a provider that opted in to
+// telemetry, giving up after a second. main calls it once the plugin server
+// has stopped; failures are ignored.
+func FlushTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryFlushTimeout)
+	defer cancel()
+	_ = telemetry.flush(ctx)
+}
+
+// telemetryOperation strips identifiers from an operation name. Cloudflare
+// operations are a method and path, such as "GET /certificates/<id>"; every
+// path segment that is not a fixed API word is replaced with "{id}".
+func telemetryOperation(operation string) string {
+	method, path, found := strings.Cut(operation, " ")
+	if !found {
+		return operation
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.Trim(segment, "abcdefghijklmnopqrstuvwxyz_") != "" {
+			segments[i] = "{id}"
+		}
+	}
+	return method + " " + strings.Join(segments, "/")
+}
+
+// errorClass names the kind of failure err is without any of its message:
+// an HTTP status for Cloudflare, the error code for AWS, or a broad class.
+func errorClass(err error) string {
+	var cloudflareErr *cloudflare.APIError
+	var awsErr *awsAPIError
+	var smithyErr smithy.APIError
+	var circuitErr *circuitOpenError
+	switch {
+	case errors.As(err, &cloudflareErr):
+		return fmt.Sprintf("http_%d", cloudflareErr.StatusCode)
+	case errors.As(err, &awsErr):
+		return awsErr.Code
+	case errors.As(err, &smithyErr):
+		return smithyErr.ErrorCode()
+	case errors.As(err, &circuitErr):
+		return "circuit_open"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "other"
+	}
+}
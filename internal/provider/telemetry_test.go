@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+)
+
+func TestTelemetryReport(t *testing.T) {
+	var report telemetryReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&report)
+	}))
+	t.Cleanup(server.Close)
+
+	recorder := &telemetryRecorder{httpClient: server.Client()}
+	recorder.record(logSubsystemACM, "ImportCertificate", nil)
+	recorder.enable(server.URL, "1.2.3")
+	recorder.record(logSubsystemCloudflare, "GET /certificates/0123456789abcdef", nil)
+	recorder.record(logSubsystemCloudflare, "GET /certificates/fedcba9876543210", &cloudflare.APIError{StatusCode: 429, RayID: "8a1b"})
+	recorder.record(logSubsystemACM, "ImportCertificate", &types.LimitExceededException{Message: new(string)})
+	if err := recorder.flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if report.ProviderVersion != "1.2.3" {
+		t.Errorf("provider_version = %q, want 1.2.3", report.ProviderVersion)
+	}
+	wantOperations := map[string]int{"cloudflare GET /certificates/{id}": 2, "acm ImportCertificate": 1}
+	if len(report.Operations) != len(wantOperations) {
+		t.Errorf("operations = %v, want %v", report.Operations, wantOperations)
+	}
+	for operation, count := range wantOperations {
+		if report.Operations[operation] != count {
+			t.Errorf("operations[%q] = %d, want %d", operation, report.Operations[operation], count)
+		}
+	}
+	wantErrors := map[string]int{"cloudflare http_429": 1, "acm LimitExceededException": 1}
+	for class, count := range wantErrors {
+		if report.Errors[class] != count {
+			t.Errorf("errors[%q] = %d, want %d (got %v)", class, report.Errors[class], count, report.Errors)
+		}
+	}
+}
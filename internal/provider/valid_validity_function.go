@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/envato/origin-certificate-provider/internal/cloudflare"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &ValidValidityFunction{}
+
+// ValidValidityFunction reports whether a number of days is a certificate
+// lifetime Cloudflare accepts.
+type ValidValidityFunction struct{}
+
+func NewValidValidityFunction() function.Function {
+	return &ValidValidityFunction{}
+}
+
+func (f *ValidValidityFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "valid_validity"
+}
+
+func (f *ValidValidityFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks whether a validity period is accepted by Cloudflare.",
+		Description: "Returns true when days is one of the validity periods Cloudflare Origin CA accepts: " +
+			"7, 30, 90, 365, 730, 1095 or 5475.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:        "days",
+				Description: "The validity period in days.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *ValidValidityFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var days int64
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &days))
+	if resp.Error != nil {
+		return
+	}
+
+	valid := false
+	for _, accepted := range cloudflare.ValidityDays {
+		if days == accepted {
+			valid = true
+			break
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, valid))
+}
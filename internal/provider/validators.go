@@ -0,0 +1,266 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ validator.String = stringOneOfValidator{}
+
+// stringOneOfValidator checks that a string attribute is one of a fixed set
+// of values.
+type stringOneOfValidator struct {
+	values []string
+}
+
+func stringOneOf(values ...string) stringOneOfValidator {
+	return stringOneOfValidator{values: values}
+}
+
+func (v stringOneOfValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be one of: %s", strings.Join(v.values, ", "))
+}
+
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	value := req.ConfigValue.ValueString()
+	for _, allowed := range v.values {
+		if value == allowed {
+			return
+		}
+	}
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid Attribute Value",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+	)
+}
+
+var _ validator.Int64 = int64BetweenValidator{}
+
+// int64BetweenValidator checks that an integer attribute is within an
+// inclusive range.
+type int64BetweenValidator struct {
+	min, max int64
+}
+
+func int64Between(min, max int64) int64BetweenValidator {
+	return int64BetweenValidator{min: min, max: max}
+}
+
+func (v int64BetweenValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be between %d and %d", v.min, v.max)
+}
+
+func (v int64BetweenValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int64BetweenValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if value := req.ConfigValue.ValueInt64(); value < v.min || value > v.max {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %d", req.Path, v.Description(ctx), value),
+		)
+	}
+}
+
+var _ validator.String = hostnameValidator{}
+var _ validator.List = hostnameValidator{}
+
+// hostnameValidator checks that a hostname, or each hostname in a list, is
+// one Cloudflare Origin CA will issue a certificate for.
+type hostnameValidator struct{}
+
+func validHostname() hostnameValidator {
+	return hostnameValidator{}
+}
+
+func (v hostnameValidator) Description(ctx context.Context) string {
+	return "value must be a hostname, optionally with a single leading wildcard label"
+}
+
+func (v hostnameValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v hostnameValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	resp.Diagnostics.Append(v.validate(req.Path, req.ConfigValue.ValueString())...)
+}
+
+func (v hostnameValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	for i, element := range req.ConfigValue.Elements() {
+		hostname, ok := element.(tfTypes.String)
+		if !ok || hostname.IsNull() || hostname.IsUnknown() {
+			continue
+		}
+		resp.Diagnostics.Append(v.validate(req.Path.AtListIndex(i), hostname.ValueString())...)
+	}
+}
+
+func (v hostnameValidator) validate(p path.Path, hostname string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if problem := hostnameProblem(hostname); problem != "" {
+		diags.AddAttributeError(p, "Invalid Hostname", fmt.Sprintf("Cloudflare Origin CA will not issue a certificate for %q: %s.", hostname, problem))
+	}
+	return diags
+}
+
+var _ resource.ConfigValidator = oneOfValidator{}
+var _ ephemeral.ConfigValidator = oneOfValidator{}
+var _ datasource.ConfigValidator = oneOfValidator{}
+
+// oneOfValidator checks that exactly one, or with optional at most one, of a
+// set of top-level attributes is configured.
+type oneOfValidator struct {
+	attributes []string
+	optional   bool
+}
+
+func exactlyOneOf(attributes ...string) oneOfValidator {
+	return oneOfValidator{attributes: attributes}
+}
+
+func atMostOneOf(attributes ...string) oneOfValidator {
+	return oneOfValidator{attributes: attributes, optional: true}
+}
+
+func (v oneOfValidator) Description(ctx context.Context) string {
+	if v.optional {
+		return fmt.Sprintf("at most one of these attributes can be configured: %s", strings.Join(v.attributes, ", "))
+	}
+	return fmt.Sprintf("exactly one of these attributes must be configured: %s", strings.Join(v.attributes, ", "))
+}
+
+func (v oneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v oneOfValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(v.validate(ctx, req.Config)...)
+}
+
+func (v oneOfValidator) ValidateEphemeralResource(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	resp.Diagnostics.Append(v.validate(ctx, req.Config)...)
+}
+
+func (v oneOfValidator) ValidateDataSource(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(v.validate(ctx, req.Config)...)
+}
+
+func (v oneOfValidator) validate(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var set []string
+	for _, name := range v.attributes {
+		var value attr.Value
+		diags.Append(config.GetAttribute(ctx, path.Root(name), &value)...)
+		if diags.HasError() {
+			return diags
+		}
+		// An unknown value may turn out null or set; decide once it is known.
+		if value.IsUnknown() {
+			return diags
+		}
+		if !value.IsNull() {
+			set = append(set, name)
+		}
+	}
+
+	switch {
+	case len(set) == 0 && !v.optional:
+		diags.AddError(
+			"Missing Attribute Configuration",
+			fmt.Sprintf("Exactly one of %s must be configured.", strings.Join(v.attributes, ", ")),
+		)
+	case len(set) > 1:
+		diags.AddAttributeError(
+			path.Root(set[1]),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("Only one of %s can be configured, got: %s.", strings.Join(v.attributes, ", "), strings.Join(set, ", ")),
+		)
+	}
+	return diags
+}
+
+var _ resource.ConfigValidator = keyBackendRequiredValidator{}
+
+// keyBackendRequiredValidator checks that an attribute is only configured
+// alongside one of the given key_backend values. An unset key_backend counts
+// as its default, "acm".
+type keyBackendRequiredValidator struct {
+	attribute string
+	backends  []string
+	reason    string
+}
+
+func keyBackendRequired(attribute, reason string, backends ...string) keyBackendRequiredValidator {
+	return keyBackendRequiredValidator{attribute: attribute, backends: backends, reason: reason}
+}
+
+func (v keyBackendRequiredValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("%s requires key_backend to be one of: %s", v.attribute, strings.Join(v.backends, ", "))
+}
+
+func (v keyBackendRequiredValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v keyBackendRequiredValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var value attr.Value
+	var backend tfTypes.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(v.attribute), &value)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("key_backend"), &backend)...)
+	if resp.Diagnostics.HasError() || value.IsNull() || backend.IsUnknown() {
+		return
+	}
+
+	current := keyBackendACM
+	if !backend.IsNull() {
+		current = backend.ValueString()
+	}
+	for _, allowed := range v.backends {
+		if current == allowed {
+			return
+		}
+	}
+	resp.Diagnostics.AddAttributeError(
+		path.Root(v.attribute),
+		"Invalid Attribute Combination",
+		fmt.Sprintf("%s can only be used with key_backend %s; %s", v.attribute, strings.Join(quoted(v.backends), " or "), v.reason),
+	)
+}
+
+func quoted(values []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = fmt.Sprintf("%q", value)
+	}
+	return result
+}
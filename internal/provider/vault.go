@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// vaultClient reads and writes certificate material in a HashiCorp Vault KV
+// version 2 secrets engine.
+type vaultClient struct {
+	Address    string
+	Token      string
+	Namespace  string
+	httpClient *http.Client
+}
+
+var _ KeyStore = &vaultClient{}
+
+func (c *vaultClient) configured() bool {
+	return c != nil && c.Address != "" && c.Token != ""
+}
+
+func (c *vaultClient) Name() string {
+	return "Vault"
+}
+
+func (c *vaultClient) Check() error {
+	if !c.configured() {
+		return errors.New("vault_kv_path requires the provider vault_address and vault_token attributes or VAULT_ADDR and VAULT_TOKEN environment variables.")
+	}
+	return nil
+}
+
+// kvURL splits a "<mount>/<path>" KV path and returns the API URL for the
+// given KV v2 endpoint ("data" or "metadata").
+func (c *vaultClient) kvURL(kvPath, endpoint string) (string, error) {
+	kvPath = strings.Trim(kvPath, "/")
+	mount, rest, found := strings.Cut(kvPath, "/")
+	if !found || mount == "" || rest == "" {
+		return "", fmt.Errorf("vault KV path %q must be of the form <mount>/<path>", kvPath)
+	}
+	return fmt.Sprintf("%s/v1/%s/%s/%s", strings.TrimSuffix(c.Address, "/"), mount, endpoint, rest), nil
+}
+
+func (c *vaultClient) do(ctx context.Context, method, url string, body, output interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("X-Vault-Token", c.Token)
+	if c.Namespace != "" {
+		httpReq.Header.Set("X-Vault-Namespace", c.Namespace)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	client := c.httpClient
+	if client == nil {
+		client = newLoggingHTTPClient("Vault", nil)
+	}
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(httpResp.Body, 4096))
+		var vaultResp struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(respBody, &vaultResp) == nil && len(vaultResp.Errors) > 0 {
+			return fmt.Errorf("vault API error (status %d): %s", httpResp.StatusCode, strings.Join(vaultResp.Errors, "; "))
+		}
+		return fmt.Errorf("vault API error (status %d)", httpResp.StatusCode)
+	}
+	if output != nil {
+		if err := json.NewDecoder(httpResp.Body).Decode(output); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *vaultClient) Write(ctx context.Context, kvPath string, data map[string]string) error {
+	url, err := c.kvURL(kvPath, "data")
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, "POST", url, map[string]interface{}{"data": data}, nil)
+}
+
+// Read returns the latest version of the secret.
+func (c *vaultClient) Read(ctx context.Context, kvPath string) (map[string]string, error) {
+	url, err := c.kvURL(kvPath, "data")
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := c.do(ctx, "GET", url, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data.Data, nil
+}
+
+// Delete removes all versions and metadata of the secret.
+func (c *vaultClient) Delete(ctx context.Context, kvPath string) error {
+	url, err := c.kvURL(kvPath, "metadata")
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, "DELETE", url, nil, nil)
+}
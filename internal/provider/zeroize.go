@@ -0,0 +1,28 @@
+package provider
+
+import "crypto/ecdsa"
+
+// zeroize overwrites private key material once it has been handed to its
+// destination, so it does not linger in memory where a core dump or debugger
+// on a shared CI runner could read it. Strings built from key material, and
+// copies made by SDKs while encoding requests, cannot be cleared; those are
+// kept to the places that require them.
+func zeroize(buffers ...[]byte) {
+	for _, buffer := range buffers {
+		clear(buffer)
+	}
+}
+
+// zeroizeECKey clears the private scalar of key.
+func zeroizeECKey(key *ecdsa.PrivateKey) {
+	if key != nil && key.D != nil {
+		clear(key.D.Bits())
+	}
+}
+
+// zeroizeIssued clears the private keys of issued certificates.
+func zeroizeIssued(issued []issuedCertificate) {
+	for _, cert := range issued {
+		zeroize(cert.keyPEM)
+	}
+}
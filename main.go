@@ -22,6 +22,7 @@ func main() {
 	}
 
 	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	provider.FlushTelemetry()
 	if err != nil {
 		log.Fatal(err.Error())
 	}
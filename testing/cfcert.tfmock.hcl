@@ -0,0 +1,55 @@
+# Mock data for the cfcert provider, for terraform test (Terraform 1.7+).
+#
+#   mock_provider "cfcert" {
+#     source = "./testing/cfcert" # a directory holding this file
+#   }
+#
+# Terraform generates values for computed attributes of a mocked provider,
+# but not ones a module can parse: these defaults give them the shapes the
+# real provider returns. Override any of them per test with
+# override_resource or override_data.
+
+mock_resource "cfcert_origin_certificate" {
+  defaults = {
+    id                        = "arn:aws:acm:us-east-1:123456789012:certificate/00000000-0000-4000-8000-000000000001"
+    certificate_arn           = "arn:aws:acm:us-east-1:123456789012:certificate/00000000-0000-4000-8000-000000000001"
+    certificate_arns          = ["arn:aws:acm:us-east-1:123456789012:certificate/00000000-0000-4000-8000-000000000001"]
+    replaced_certificate_arns = []
+    key_backend               = "acm"
+    key_algorithm             = "EC_prime256v1"
+    adoption_strategy         = "newest"
+    delete_wait_for_unused    = "5m"
+    expires_at                = "2041-01-01T00:00:00Z"
+    imported_at               = "2026-01-01T00:00:00Z"
+    created_at                = "2026-01-01T00:00:00Z"
+    acm_type                  = "IMPORTED"
+    renewal_eligibility       = "INELIGIBLE"
+    serial_number             = "1f2e3d4c5b6a79880123456789abcdef01234567"
+    certificate_status        = "ISSUED"
+    cloudflare_status         = "active"
+    origin                    = "issued"
+  }
+}
+
+mock_data "cfcert_origin_certificate" {
+  defaults = {
+    id              = "arn:aws:acm:us-east-1:123456789012:certificate/00000000-0000-4000-8000-000000000001"
+    certificate_arn = "arn:aws:acm:us-east-1:123456789012:certificate/00000000-0000-4000-8000-000000000001"
+  }
+}
+
+mock_data "cfcert_issuance_preflight" {
+  defaults = {
+    ready                      = true
+    problems                   = []
+    imported_certificates      = 0
+    imported_certificate_quota = 2500
+  }
+}
+
+mock_data "cfcert_acm_unused_certificates" {
+  defaults = {
+    certificates     = []
+    certificate_arns = []
+  }
+}
@@ -0,0 +1,39 @@
+// Package testing ships mock data for the cfcert provider, so authors of
+// Terraform modules that wrap it can run terraform test without Cloudflare or
+// AWS credentials.
+//
+// A mock_provider block reads mock data from a directory of .tfmock.hcl
+// files. Copy cfcert.tfmock.hcl from this directory into the module, or
+// write it from Go with WriteMockData, which keeps it matched to the provider
+// version in the module's go.mod:
+//
+//	mock_provider "cfcert" {
+//	  source = "./testing/cfcert"
+//	}
+package testing
+
+import (
+	_ "embed"
+	"os"
+	"path/filepath"
+)
+
+// MockDataFile is the name WriteMockData writes MockData under.
+const MockDataFile = "cfcert.tfmock.hcl"
+
+// MockData holds mock_resource and mock_data blocks giving the provider's
+// computed attributes the shapes the real provider returns, such as an ACM
+// ARN and an RFC 3339 expires_at, where the values Terraform generates for a
+// mocked provider would fail a module's validation or parsing.
+//
+//go:embed cfcert.tfmock.hcl
+var MockData []byte
+
+// WriteMockData writes MockData into dir, creating dir if needed, for a
+// mock_provider block's source to point at.
+func WriteMockData(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, MockDataFile), MockData, 0o644)
+}